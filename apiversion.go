@@ -0,0 +1,226 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// APIVersion selects the generation of service endpoints the client talks to
+type APIVersion string
+
+const (
+	APIVersionV1 APIVersion = "v1"
+	APIVersionV2 APIVersion = "v2"
+	// APIVersionDetect queries the service on first use and picks the newest
+	// version both sides support
+	APIVersionDetect APIVersion = "detect"
+)
+
+// supportedAPIVersions lists the versions this SDK can map, newest first
+var supportedAPIVersions = []APIVersion{APIVersionV2, APIVersionV1}
+
+// apiVersionsPath is the endpoint listing the versions a service supports
+const apiVersionsPath = "/api/versions"
+
+// apiMapper translates the SDK's v1-shaped paths and payloads to and from a
+// specific API version, so that the public methods are version independent
+type apiMapper interface {
+	mapPath(path string) string
+	encodeRequest(path string, body []byte) ([]byte, error)
+	decodeResponse(path string, body []byte) ([]byte, error)
+}
+
+// mapperFor returns the mapper for a resolved version
+func mapperFor(version APIVersion) apiMapper {
+	if version == APIVersionV2 {
+		return v2Mapper{}
+	}
+	return v1Mapper{}
+}
+
+// v1Mapper is the identity mapping; the SDK's types follow v1
+type v1Mapper struct{}
+
+func (v1Mapper) mapPath(path string) string { return path }
+
+func (v1Mapper) encodeRequest(path string, body []byte) ([]byte, error) { return body, nil }
+
+func (v1Mapper) decodeResponse(path string, body []byte) ([]byte, error) { return body, nil }
+
+// v2Mapper maps to /api/v2 endpoints. v2 nests callback settings under
+// "callback", renames object_body to "payload", identifies messages by
+// "message_id", and wraps every response in a {"data": ...} envelope
+type v2Mapper struct{}
+
+func (v2Mapper) mapPath(path string) string {
+	if strings.HasPrefix(path, "/api/v1/") {
+		return "/api/v2/" + strings.TrimPrefix(path, "/api/v1/")
+	}
+	return path
+}
+
+func (v2Mapper) encodeRequest(path string, body []byte) ([]byte, error) {
+	if !isMessageSubmission(path) {
+		return body, nil
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("failed to map request to v2: %w", err)
+	}
+
+	if messages, ok := value["messages"].([]interface{}); ok {
+		for i, m := range messages {
+			if msg, ok := m.(map[string]interface{}); ok {
+				messages[i] = v2Message(msg)
+			}
+		}
+	} else {
+		value = v2Message(value)
+	}
+
+	return json.Marshal(value)
+}
+
+func (v2Mapper) decodeResponse(path string, body []byte) ([]byte, error) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return body, nil
+	}
+	if data, ok := envelope["data"]; ok {
+		body = data
+	}
+
+	if !isMessageSubmission(path) || isDryRun(path) {
+		return body, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body, nil
+	}
+	return json.Marshal(renameKeys(value, map[string]string{"message_id": "id", "item_id": "itemId", "correlation_id": "correlationId"}))
+}
+
+// v2Message reshapes a v1 message object into its v2 form
+func v2Message(msg map[string]interface{}) map[string]interface{} {
+	callback := map[string]interface{}{}
+	for v1Key, v2Key := range map[string]string{
+		"callback_url":          "url",
+		"processing_timeout_ms": "timeout_ms",
+		"budget_ms":             "budget_ms",
+	} {
+		if v, ok := msg[v1Key]; ok {
+			callback[v2Key] = v
+			delete(msg, v1Key)
+		}
+	}
+	msg["callback"] = callback
+
+	if body, ok := msg["object_body"]; ok {
+		msg["payload"] = body
+		delete(msg, "object_body")
+	}
+	return msg
+}
+
+// isMessageSubmission reports whether path submits single or bulk messages
+func isMessageSubmission(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	return strings.HasSuffix(path, "/messages") || strings.HasSuffix(path, "/messages/bulk")
+}
+
+// apiVersionState holds the configured API version and, in detect mode, the
+// version picked once the service has been asked
+type apiVersionState struct {
+	mu         sync.Mutex
+	configured APIVersion
+	resolved   APIVersion
+}
+
+// resolveAPIVersion returns the API version to use, detecting it on first
+// use in detect mode. Failed detections are retried on the next request
+func (c *Client) resolveAPIVersion(ctx context.Context) (APIVersion, error) {
+	s := c.apiVersion
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.configured != APIVersionDetect {
+		return s.configured, nil
+	}
+	if s.resolved != "" {
+		return s.resolved, nil
+	}
+
+	version, err := c.detectAPIVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.resolved = version
+	return version, nil
+}
+
+// detectAPIVersion asks the service which API versions it supports and picks
+// the newest one the SDK supports. Services without the endpoint only speak v1
+func (c *Client) detectAPIVersion(ctx context.Context) (APIVersion, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+apiVersionsPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API version detection failed: %w", err)
+	}
+	defer resp.Body.Close()
+	c.version.observe(resp)
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return APIVersionV1, nil
+	}
+	if resp.StatusCode >= 400 {
+		return "", c.translateError(resp, body)
+	}
+
+	var versions struct {
+		Versions []APIVersion `json:"versions"`
+	}
+	if err := json.Unmarshal(body, &versions); err != nil {
+		return "", fmt.Errorf("failed to unmarshal API versions: %w", err)
+	}
+
+	offered := make(map[APIVersion]bool, len(versions.Versions))
+	for _, v := range versions.Versions {
+		offered[v] = true
+	}
+	for _, v := range supportedAPIVersions {
+		if offered[v] {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("service offers API versions %v, none supported by this SDK", versions.Versions)
+}
+
+// APIVersion returns the API version in use, or APIVersionDetect when it has
+// not been detected yet
+func (c *Client) APIVersion() APIVersion {
+	s := c.apiVersion
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.configured == APIVersionDetect && s.resolved != "" {
+		return s.resolved
+	}
+	return s.configured
+}
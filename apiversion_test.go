@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIVersionV2Mapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/messages" {
+			t.Errorf("Expected path '/api/v2/messages', got '%s'", r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		callback, _ := body["callback"].(map[string]interface{})
+		if callback["url"] != "https://example.com/callback" || callback["timeout_ms"] != float64(5000) {
+			t.Errorf("Expected nested callback settings, got %v", body["callback"])
+		}
+		if _, ok := body["callback_url"]; ok {
+			t.Error("Expected callback_url to be removed in v2")
+		}
+		if payload, _ := body["payload"].(map[string]interface{}); payload["diff"] != "x" {
+			t.Errorf("Expected object_body as payload, got %v", body)
+		}
+
+		w.Write([]byte(`{"data":{"message_id":"msg-1","status":"queued","item_id":"pr-1","priority":"high","topic":"pullrequests"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionV2})
+	resp, err := client.PostMessage(context.Background(), &MessageRequest{
+		ItemID:            "pr-1",
+		Priority:          PriorityHigh,
+		Topic:             TopicPullRequests,
+		CallbackURL:       "https://example.com/callback",
+		ObjectBody:        map[string]string{"diff": "x"},
+		ProcessingTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.ID != "msg-1" || resp.ItemID != "pr-1" || resp.Status != "queued" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestAPIVersionDetect(t *testing.T) {
+	detections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/versions":
+			detections++
+			w.Write([]byte(`{"versions":["v1","v2","v3"]}`))
+		case "/api/v2/workers/status":
+			w.Write([]byte(`{"data":{"total_workers":3}}`))
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionDetect})
+	if client.APIVersion() != APIVersionDetect {
+		t.Errorf("Expected version to be undetected, got '%s'", client.APIVersion())
+	}
+
+	for i := 0; i < 2; i++ {
+		status, err := client.GetWorkerStatus(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if status.TotalWorkers != 3 {
+			t.Errorf("Expected 3 workers, got %d", status.TotalWorkers)
+		}
+	}
+
+	if detections != 1 {
+		t.Errorf("Expected a single detection, got %d", detections)
+	}
+	if client.APIVersion() != APIVersionV2 {
+		t.Errorf("Expected detected version 'v2', got '%s'", client.APIVersion())
+	}
+}
+
+func TestAPIVersionDetectFallsBackToV1(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/versions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/api/v1/workers/status" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`{"total_workers":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionDetect})
+	if _, err := client.GetWorkerStatus(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if client.APIVersion() != APIVersionV1 {
+		t.Errorf("Expected 'v1', got '%s'", client.APIVersion())
+	}
+}
@@ -0,0 +1,180 @@
+package sdk
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ArchiveFormat identifies message archives in their header
+const ArchiveFormat = "messages-worker-archive"
+
+// ArchiveSchemaVersion is the archive schema written by this SDK; archives
+// with a newer schema are rejected by ReadArchive
+const ArchiveSchemaVersion = 1
+
+// ArchiveHeader is the first line of an archive. It is always stored
+// uncompressed so that readers can learn how the records are encoded
+type ArchiveHeader struct {
+	Format        string    `json:"format"`
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Compression is the content-coding of the records, e.g. EncodingGzip;
+	// empty means uncompressed. Any registered compressor may be used
+	Compression string `json:"compression,omitempty"`
+	// Metadata describes the archive, e.g. its source environment
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ArchiveRecord is a single archived message
+type ArchiveRecord struct {
+	Request MessageRequest `json:"request"`
+	// EnqueuedAt is when the message was first submitted, if known
+	EnqueuedAt time.Time `json:"enqueued_at,omitzero"`
+}
+
+// ArchiveWriter writes records to an archive
+type ArchiveWriter struct {
+	enc        *json.Encoder
+	compressed io.WriteCloser
+	buf        *bufio.Writer
+}
+
+// WriteArchive writes header to w and returns a writer for the records.
+// Format, SchemaVersion, and a zero CreatedAt are filled in. Close must be
+// called to flush the records; it does not close w
+func WriteArchive(w io.Writer, header ArchiveHeader) (*ArchiveWriter, error) {
+	return writeArchive(w, header, SystemClock)
+}
+
+// writeArchive is WriteArchive taking a zero CreatedAt from clock
+func writeArchive(w io.Writer, header ArchiveHeader, clock Clock) (*ArchiveWriter, error) {
+	header.Format = ArchiveFormat
+	header.SchemaVersion = ArchiveSchemaVersion
+	if header.CreatedAt.IsZero() {
+		header.CreatedAt = clock.Now().UTC()
+	}
+
+	if err := json.NewEncoder(w).Encode(header); err != nil {
+		return nil, fmt.Errorf("failed to write archive header: %w", err)
+	}
+
+	aw := &ArchiveWriter{buf: bufio.NewWriter(w)}
+	out := io.Writer(aw.buf)
+	if header.Compression != "" {
+		c, ok := compressorFor(header.Compression)
+		if !ok {
+			return nil, fmt.Errorf("unknown archive compression '%s'", header.Compression)
+		}
+		compressed, err := c.Compress(aw.buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s writer: %w", c.Encoding(), err)
+		}
+		aw.compressed = compressed
+		out = compressed
+	}
+
+	aw.enc = json.NewEncoder(out)
+	return aw, nil
+}
+
+// Write appends a record to the archive
+func (w *ArchiveWriter) Write(record ArchiveRecord) error {
+	if err := w.enc.Encode(record); err != nil {
+		return fmt.Errorf("failed to write archive record: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered and compressed records
+func (w *ArchiveWriter) Close() error {
+	if w.compressed != nil {
+		if err := w.compressed.Close(); err != nil {
+			return fmt.Errorf("failed to finish archive compression: %w", err)
+		}
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush archive: %w", err)
+	}
+	return nil
+}
+
+// ArchiveReader reads records from an archive
+type ArchiveReader struct {
+	header       ArchiveHeader
+	dec          *json.Decoder
+	decompressed io.ReadCloser
+}
+
+// ReadArchive reads and checks the archive header from r and returns a
+// reader for the records
+func ReadArchive(r io.Reader) (*ArchiveReader, error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadBytes('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+		return nil, fmt.Errorf("failed to read archive header: %w", err)
+	}
+
+	header, ok := parseArchiveHeader(line)
+	if !ok {
+		return nil, fmt.Errorf("not a %s", ArchiveFormat)
+	}
+	if header.SchemaVersion > ArchiveSchemaVersion {
+		return nil, fmt.Errorf("archive schema version %d is newer than supported version %d", header.SchemaVersion, ArchiveSchemaVersion)
+	}
+
+	ar := &ArchiveReader{header: header}
+	records := io.Reader(br)
+	if header.Compression != "" {
+		c, ok := compressorFor(header.Compression)
+		if !ok {
+			return nil, fmt.Errorf("unknown archive compression '%s'", header.Compression)
+		}
+		decompressed, err := c.Decompress(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s archive: %w", header.Compression, err)
+		}
+		ar.decompressed = decompressed
+		records = decompressed
+	}
+
+	ar.dec = json.NewDecoder(records)
+	return ar, nil
+}
+
+// Header returns the archive header
+func (r *ArchiveReader) Header() ArchiveHeader {
+	return r.header
+}
+
+// Next returns the next record, or io.EOF after the last one
+func (r *ArchiveReader) Next() (ArchiveRecord, error) {
+	var record ArchiveRecord
+	if err := r.dec.Decode(&record); err != nil {
+		if errors.Is(err, io.EOF) {
+			return record, io.EOF
+		}
+		return record, fmt.Errorf("failed to read archive record: %w", err)
+	}
+	return record, nil
+}
+
+// Close releases the decompressor; it does not close the underlying reader
+func (r *ArchiveReader) Close() error {
+	if r.decompressed != nil {
+		return r.decompressed.Close()
+	}
+	return nil
+}
+
+// parseArchiveHeader parses line as an archive header
+func parseArchiveHeader(line []byte) (ArchiveHeader, bool) {
+	var header ArchiveHeader
+	if err := json.Unmarshal(line, &header); err != nil || header.Format != ArchiveFormat {
+		return ArchiveHeader{}, false
+	}
+	return header, true
+}
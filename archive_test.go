@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	enqueued := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []ArchiveRecord{
+		{Request: MessageRequest{ItemID: "pr-1", Priority: PriorityHigh, Topic: TopicPullRequests, ProcessingTimeout: time.Second}, EnqueuedAt: enqueued},
+		{Request: MessageRequest{ItemID: "pr-2", Priority: PriorityLow, Topic: TopicPullRequests, ObjectBody: map[string]interface{}{"diff": "x"}}},
+	}
+
+	for _, compression := range []string{"", EncodingGzip, EncodingZstd} {
+		var buf bytes.Buffer
+		w, err := WriteArchive(&buf, ArchiveHeader{Compression: compression, Metadata: map[string]string{"env": "staging"}})
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %v", compression, err)
+		}
+		for _, r := range records {
+			if err := w.Write(r); err != nil {
+				t.Fatalf("%q: expected no error, got %v", compression, err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%q: expected no error, got %v", compression, err)
+		}
+
+		r, err := ReadArchive(&buf)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %v", compression, err)
+		}
+		header := r.Header()
+		if header.SchemaVersion != ArchiveSchemaVersion || header.Compression != compression || header.Metadata["env"] != "staging" {
+			t.Errorf("%q: unexpected header %+v", compression, header)
+		}
+
+		var read []ArchiveRecord
+		for {
+			record, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%q: expected no error, got %v", compression, err)
+			}
+			read = append(read, record)
+		}
+		r.Close()
+
+		if len(read) != 2 || read[0].Request.ItemID != "pr-1" || read[0].Request.ProcessingTimeout != time.Second {
+			t.Fatalf("%q: unexpected records %+v", compression, read)
+		}
+		if !read[0].EnqueuedAt.Equal(enqueued) || !read[1].EnqueuedAt.IsZero() {
+			t.Errorf("%q: unexpected enqueue times %v, %v", compression, read[0].EnqueuedAt, read[1].EnqueuedAt)
+		}
+	}
+}
+
+func TestReadArchiveRejectsUnknownInput(t *testing.T) {
+	if _, err := ReadArchive(strings.NewReader(`{"request":{"item_id":"pr-1"}}` + "\n")); err == nil {
+		t.Error("Expected error for missing header, got nil")
+	}
+
+	newer := `{"format":"messages-worker-archive","schema_version":99}` + "\n"
+	if _, err := ReadArchive(strings.NewReader(newer)); err == nil {
+		t.Error("Expected error for newer schema version, got nil")
+	}
+}
@@ -0,0 +1,241 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Defaults for the asynchronous submission pool
+const (
+	DefaultAsyncWorkers   = 8
+	DefaultAsyncQueueSize = 1000
+)
+
+// ErrClientClosed is returned when submitting through a client that has been closed
+var ErrClientClosed = errors.New("client is closed")
+
+// MessageFuture is the pending result of an asynchronous message submission
+type MessageFuture struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	req    *MessageRequest
+	done   chan struct{}
+	resp   *MessageResponse
+	err    error
+}
+
+// Done returns a channel that is closed once the submission has completed
+func (f *MessageFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result blocks until the submission completes and returns its outcome
+func (f *MessageFuture) Result() (*MessageResponse, error) {
+	<-f.done
+	return f.resp, f.err
+}
+
+// Cancel abandons the submission if it has not completed yet
+func (f *MessageFuture) Cancel() {
+	f.cancel()
+}
+
+// complete records the outcome and releases waiters
+func (f *MessageFuture) complete(resp *MessageResponse, err error) {
+	f.resp, f.err = resp, err
+	f.cancel()
+	close(f.done)
+}
+
+// AsyncStats reports the state of the asynchronous submission pool
+type AsyncStats struct {
+	Queued    int64
+	InFlight  int64
+	Completed int64
+	Failed    int64
+	Canceled  int64
+}
+
+// asyncPool runs asynchronous submissions with bounded concurrency
+type asyncPool struct {
+	client *Client
+	jobs   chan *MessageFuture
+	wg     sync.WaitGroup
+	// abort cancels every queued and in-flight submission
+	ctx   context.Context
+	abort context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+
+	queued    atomic.Int64
+	inFlight  atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	canceled  atomic.Int64
+}
+
+// newAsyncPool starts workers goroutines consuming a queue of queueSize
+func newAsyncPool(client *Client, workers, queueSize int) *asyncPool {
+	p := &asyncPool{
+		client: client,
+		jobs:   make(chan *MessageFuture, queueSize),
+	}
+	p.ctx, p.abort = context.WithCancel(context.Background())
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// work processes queued futures until the queue is closed
+func (p *asyncPool) work() {
+	defer p.wg.Done()
+
+	for f := range p.jobs {
+		p.queued.Add(-1)
+		stop := context.AfterFunc(p.ctx, f.cancel)
+
+		if err := f.ctx.Err(); err != nil {
+			stop()
+			p.canceled.Add(1)
+			f.complete(nil, err)
+			continue
+		}
+
+		p.inFlight.Add(1)
+		resp, err := p.client.PostMessage(f.ctx, f.req)
+		p.inFlight.Add(-1)
+		stop()
+
+		switch {
+		case err == nil:
+			p.completed.Add(1)
+		case f.ctx.Err() != nil:
+			p.canceled.Add(1)
+		default:
+			p.failed.Add(1)
+		}
+		f.complete(resp, err)
+	}
+}
+
+// submit enqueues f, blocking while the queue is full
+func (p *asyncPool) submit(f *MessageFuture) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		f.complete(nil, ErrClientClosed)
+		return
+	}
+
+	p.queued.Add(1)
+	select {
+	case p.jobs <- f:
+	case <-f.ctx.Done():
+		p.queued.Add(-1)
+		p.canceled.Add(1)
+		f.complete(nil, f.ctx.Err())
+	}
+}
+
+// close stops accepting submissions and waits for queued ones to finish
+func (p *asyncPool) close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	p.abort()
+}
+
+// shutdown closes the pool, canceling the submissions that are still queued
+// or in flight when ctx is done
+func (p *asyncPool) shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.close()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		p.abort()
+		<-done
+		return fmt.Errorf("asynchronous submissions canceled: %w", ctx.Err())
+	}
+}
+
+// stats returns a snapshot of the pool counters
+func (p *asyncPool) stats() AsyncStats {
+	return AsyncStats{
+		Queued:    p.queued.Load(),
+		InFlight:  p.inFlight.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Canceled:  p.canceled.Load(),
+	}
+}
+
+// PostMessageAsync queues a message for submission by the client's worker
+// pool and returns immediately with a future for the result. It blocks only
+// while the queue is full. Cancelling ctx or the future abandons the submission
+func (c *Client) PostMessageAsync(ctx context.Context, req *MessageRequest) *MessageFuture {
+	fctx, cancel := context.WithCancel(ctx)
+	f := &MessageFuture{
+		ctx:    fctx,
+		cancel: cancel,
+		req:    req,
+		done:   make(chan struct{}),
+	}
+
+	pool, err := c.asyncPool()
+	if err != nil {
+		f.complete(nil, err)
+		return f
+	}
+
+	pool.submit(f)
+	return f
+}
+
+// AsyncStats returns metrics for the asynchronous submission pool
+func (c *Client) AsyncStats() AsyncStats {
+	c.asyncMu.Lock()
+	pool := c.async
+	c.asyncMu.Unlock()
+
+	if pool == nil {
+		return AsyncStats{}
+	}
+	return pool.stats()
+}
+
+// asyncPool returns the client's pool, starting it on first use
+func (c *Client) asyncPool() (*asyncPool, error) {
+	c.asyncMu.Lock()
+	defer c.asyncMu.Unlock()
+
+	if c.closed {
+		return nil, ErrClientClosed
+	}
+	if c.async == nil {
+		c.async = newAsyncPool(c, c.asyncWorkers, c.asyncQueueSize)
+		c.RegisterShutdownHook(ShutdownFlush, ShutdownHookFunc(c.async.shutdown))
+	}
+	return c.async, nil
+}
@@ -0,0 +1,89 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPostMessageAsync(t *testing.T) {
+	var active, maxActive atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + req.ItemID, ItemID: req.ItemID})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, AsyncWorkers: 2, AsyncQueueSize: 4})
+	ctx := context.Background()
+
+	futures := make([]*MessageFuture, 0, 10)
+	for i := 0; i < 10; i++ {
+		req := newMessageRequest(fmt.Sprintf("pr-%d", i), PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+		futures = append(futures, client.PostMessageAsync(ctx, req))
+	}
+
+	for i, f := range futures {
+		resp, err := f.Result()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if resp.ID != fmt.Sprintf("msg-pr-%d", i) {
+			t.Errorf("Expected ID 'msg-pr-%d', got '%s'", i, resp.ID)
+		}
+	}
+
+	if maxActive.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent submissions, got %d", maxActive.Load())
+	}
+	if stats := client.AsyncStats(); stats.Completed != 10 {
+		t.Errorf("Expected 10 completed submissions, got %+v", stats)
+	}
+
+	client.Close()
+	_, err := client.PostMessageAsync(ctx, futures[0].req).Result()
+	if err != ErrClientClosed {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+}
+
+func TestPostMessageAsyncCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(&Config{BaseURL: server.URL, AsyncWorkers: 1})
+	req := newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+
+	f := client.PostMessageAsync(context.Background(), req)
+	f.Cancel()
+
+	select {
+	case <-f.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected future to complete after cancel")
+	}
+	if _, err := f.Result(); err == nil {
+		t.Error("Expected error for cancelled submission, got nil")
+	}
+}
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Statuses of a transactional bulk request
+const (
+	BulkStatusCommitted  = "committed"
+	BulkStatusRolledBack = "rolled_back"
+)
+
+// ErrTransactionsUnsupported is returned by PostBulkMessagesAtomic when the
+// service ignored the transactional flag and accepted only part of the batch
+var ErrTransactionsUnsupported = errors.New("service does not support transactional bulk requests")
+
+// BulkRollbackError is returned by PostBulkMessagesAtomic when the service
+// rejected at least one message and therefore enqueued none of them
+type BulkRollbackError struct {
+	Response *BulkMessageResponse
+}
+
+func (e *BulkRollbackError) Error() string {
+	return fmt.Sprintf("bulk request rolled back: %d messages rejected", len(e.Response.Errors))
+}
+
+// Unwrap returns the rejections, so errors.As can match a BulkMessageError
+func (e *BulkRollbackError) Unwrap() []error {
+	errs := make([]error, len(e.Response.Errors))
+	for i, rejected := range e.Response.Errors {
+		errs[i] = rejected
+	}
+	return errs
+}
+
+// IsBulkRollbackError checks if an error is a rolled back transactional
+// bulk request
+func IsBulkRollbackError(err error) bool {
+	var rollback *BulkRollbackError
+	return errors.As(err, &rollback)
+}
+
+// transactionalBulkRequest is the wire form of an atomic bulk request
+type transactionalBulkRequest struct {
+	*BulkMessageRequest
+	Transactional bool `json:"transactional"`
+}
+
+// PostBulkMessagesAtomic submits related messages so that either all of
+// them are enqueued or none are. When the service rejects any message it
+// rolls the batch back and a *BulkRollbackError listing the rejections is
+// returned with the response. Services that predate transactional requests
+// may enqueue part of the batch anyway; that is reported as
+// ErrTransactionsUnsupported joined with a *BulkPartialError
+func (c *Client) PostBulkMessagesAtomic(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("bulk message request cannot be nil")
+	}
+
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	req = c.withBulkDefaults(ctx, req)
+	for i := range req.Messages {
+		if err := c.preparePayload(ctx, fmt.Sprintf("messages[%d].", i), &req.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.applyBulkBackpressure(ctx, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/bulk", transactionalBulkRequest{
+		BulkMessageRequest: req,
+		Transactional:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var bulkResp BulkMessageResponse
+	if err := c.parseResponse(resp, &bulkResp); err != nil {
+		return nil, err
+	}
+	fillCorrelationIDs(&bulkResp, req)
+
+	switch {
+	case bulkResp.Status == BulkStatusRolledBack:
+		return &bulkResp, &BulkRollbackError{Response: &bulkResp}
+	case resp.StatusCode == http.StatusMultiStatus || len(bulkResp.Errors) > 0:
+		return &bulkResp, errors.Join(ErrTransactionsUnsupported, &BulkPartialError{Response: &bulkResp})
+	}
+
+	return &bulkResp, nil
+}
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostBulkMessagesAtomic(t *testing.T) {
+	var response BulkMessageResponse
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages      []MessageRequest `json:"messages"`
+			Transactional bool             `json:"transactional"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Transactional {
+			t.Error("Expected transactional flag to be set")
+		}
+		if len(req.Messages) != 2 {
+			t.Errorf("Expected 2 messages, got %d", len(req.Messages))
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+	req := &BulkMessageRequest{Messages: []MessageRequest{
+		*newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+		*newMessageRequest("pr-1-review", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+	}}
+
+	status = http.StatusOK
+	response = BulkMessageResponse{Status: BulkStatusCommitted, Count: 2, Messages: []MessageResponse{{ID: "msg-1"}, {ID: "msg-2"}}}
+	resp, err := client.PostBulkMessagesAtomic(ctx, req)
+	if err != nil || resp.Count != 2 {
+		t.Fatalf("Expected committed batch, got %+v, %v", resp, err)
+	}
+
+	response = BulkMessageResponse{Status: BulkStatusRolledBack, Errors: []BulkMessageError{
+		{Index: 1, ItemID: "pr-1-review", Code: "invalid_callback", Reason: "host not allowed"},
+	}}
+	_, err = client.PostBulkMessagesAtomic(ctx, req)
+	var rejected BulkMessageError
+	if !IsBulkRollbackError(err) || !errors.As(err, &rejected) || rejected.ItemID != "pr-1-review" {
+		t.Errorf("Expected rollback naming the rejected message, got %v", err)
+	}
+
+	// An older service ignores the flag and enqueues part of the batch
+	status = http.StatusMultiStatus
+	response = BulkMessageResponse{Status: "partial", Messages: []MessageResponse{{ID: "msg-1"}}, Errors: response.Errors}
+	_, err = client.PostBulkMessagesAtomic(ctx, req)
+	if !errors.Is(err, ErrTransactionsUnsupported) || !IsBulkPartialError(err) {
+		t.Errorf("Expected unsupported partial error, got %v", err)
+	}
+}
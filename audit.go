@@ -0,0 +1,158 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrAuditFailed is returned, wrapping the sink's error, for requests that
+// were not sent because they could not be audited
+var ErrAuditFailed = errors.New("failed to audit request")
+
+// AuditRecord is an outgoing request as handed to an AuditSink: the method,
+// URL, headers, and body exactly as sent, with credential headers redacted
+type AuditRecord struct {
+	RecordedRequest
+	Time time.Time `json:"time"`
+}
+
+// AuditSink stores a record of every request a client sends, e.g. to an
+// append-only log for compliance
+type AuditSink interface {
+	// Audit is called before each request is sent, including retries
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to the AuditSink interface
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+// Audit calls f
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// AuditConfig enables auditing of the requests sent by a client
+type AuditConfig struct {
+	Sink AuditSink
+	// Redact lists headers to redact in addition to the credential headers
+	// a Recorder redacts
+	Redact []string
+	// BestEffort sends requests even when the sink fails, logging the
+	// failure; by default such requests fail with ErrAuditFailed
+	BestEffort bool
+}
+
+// validate checks that the config has a sink
+func (a *AuditConfig) validate() error {
+	if a.Sink == nil {
+		return fmt.Errorf("audit: sink is required")
+	}
+	return nil
+}
+
+// auditTransport hands every request to the sink before sending it
+type auditTransport struct {
+	config *AuditConfig
+	next   http.RoundTripper
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	record := AuditRecord{
+		RecordedRequest: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redactHeader(req.Header, t.config.Redact),
+		},
+		Time: t.now(),
+	}
+	record.Body, record.BodyEncoding = encodeRecordedBody(body)
+
+	if err := t.config.Sink.Audit(req.Context(), record); err != nil {
+		if !t.config.BestEffort {
+			return nil, fmt.Errorf("%w: %v", ErrAuditFailed, err)
+		}
+		if t.logger != nil {
+			t.logger.Warn("failed to audit request", "method", req.Method, "url", record.URL, "error", err)
+		}
+	}
+	return t.next.RoundTrip(req)
+}
+
+// replaySkippedHeaders are recomputed when a request is sent again
+var replaySkippedHeaders = []string{"Content-Length", "User-Agent", SignatureHeader, SignatureTimestampHeader, SignatureKeyIDHeader}
+
+// ReplayRequest sends a request captured by an AuditSink again through c,
+// and decodes the response into target, which may be nil. The request keeps
+// its method, path, query, headers, and body, but goes to c's base URL; the
+// redacted headers are left out and the client's own credentials and
+// signature apply instead
+func (c *Client) ReplayRequest(ctx context.Context, record AuditRecord, target interface{}) error {
+	u, err := url.Parse(record.URL)
+	if err != nil {
+		return fmt.Errorf("invalid audited URL: %w", err)
+	}
+	path := u.RequestURI()
+	if base, err := url.Parse(c.baseURL); err == nil && base.Path != "" && base.Path != "/" {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+
+	body, err := decodeRecordedBody(record.Body, record.BodyEncoding)
+	if err != nil {
+		return fmt.Errorf("invalid audited body: %w", err)
+	}
+	var reqBody io.Reader
+	if len(body) > 0 {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := c.newRequest(ctx, record.Method, path, reqBody)
+	if err != nil {
+		return err
+	}
+	for name, values := range record.Header {
+		if isReplaySkipped(name) || (len(values) == 1 && values[0] == RedactedValue) {
+			continue
+		}
+		req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+
+	resp, err := c.do(req)
+	c.telemetry.record(record.Method, path, resp, err)
+	if err != nil {
+		return err
+	}
+
+	return c.parseResponse(resp, target)
+}
+
+// isReplaySkipped reports whether header is left out of replayed requests
+func isReplaySkipped(header string) bool {
+	for _, name := range replaySkippedHeaders {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
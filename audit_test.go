@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// auditLog is an AuditSink that keeps records in memory
+type auditLog struct {
+	mu      sync.Mutex
+	records []AuditRecord
+	err     error
+}
+
+func (l *auditLog) Audit(ctx context.Context, record AuditRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	l.records = append(l.records, record)
+	return nil
+}
+
+func TestAuditSink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"msg-1","status":"queued","itemId":"pr-1"}`))
+	}))
+	defer server.Close()
+
+	sink := &auditLog{}
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		Team:    "billing",
+		Signing: &SigningConfig{Secret: []byte("secret"), KeyID: "k1"},
+		Audit:   &AuditConfig{Sink: sink, Redact: []string{"X-Team"}},
+	})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]int{"number": 1})
+	req.CorrelationID = "c-1"
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(sink.records))
+	}
+	record := sink.records[0]
+	if record.Method != http.MethodPost || record.URL != server.URL+"/api/v1/messages" || record.Time.IsZero() {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	for _, name := range []string{SignatureHeader, SignatureKeyIDHeader, "X-Team"} {
+		if got := record.Header.Get(name); got != RedactedValue {
+			t.Errorf("Expected %s to be redacted, got '%s'", name, got)
+		}
+	}
+	var sent MessageRequest
+	if err := json.Unmarshal([]byte(record.Body), &sent); err != nil || sent.ItemID != "pr-1" || sent.CorrelationID != "c-1" {
+		t.Errorf("Expected the body as sent, got %s (%v)", record.Body, err)
+	}
+}
+
+func TestAuditSinkFailure(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	sinkErr := errors.New("log unavailable")
+	client := NewClient(&Config{BaseURL: server.URL, Audit: &AuditConfig{Sink: &auditLog{err: sinkErr}}})
+	if _, err := client.CheckHealth(context.Background()); !errors.Is(err, ErrAuditFailed) {
+		t.Errorf("Expected ErrAuditFailed, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected an unaudited request not to be sent, got %d requests", requests)
+	}
+
+	client = NewClient(&Config{BaseURL: server.URL, Audit: &AuditConfig{Sink: &auditLog{err: sinkErr}, BestEffort: true}})
+	if _, err := client.CheckHealth(context.Background()); err != nil {
+		t.Errorf("Expected a best-effort audit failure to be ignored, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the request to be sent, got %d requests", requests)
+	}
+}
+
+func TestReplayRequest(t *testing.T) {
+	type received struct {
+		path, query, team, signature, body string
+	}
+	var got []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, received{r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Team"),
+			r.Header.Get(SignatureHeader), string(body)})
+		w.Write([]byte(`{"id":"msg-1","status":"queued","itemId":"pr-1"}`))
+	}))
+	defer server.Close()
+
+	sink := &auditLog{}
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		Team:    "billing",
+		Signing: &SigningConfig{Secret: []byte("secret")},
+		Audit:   &AuditConfig{Sink: sink},
+	})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	if _, err := client.DryRunMessage(context.Background(), req); err != nil {
+		t.Fatalf("DryRunMessage failed: %v", err)
+	}
+
+	// Replay from another deployment with its own credentials
+	other := httptest.NewServer(server.Config.Handler)
+	defer other.Close()
+	replayer := NewClient(&Config{BaseURL: other.URL, Signing: &SigningConfig{Secret: []byte("secret")}})
+	var resp MessageResponse
+	if err := replayer.ReplayRequest(context.Background(), sink.records[0], &resp); err != nil {
+		t.Fatalf("ReplayRequest failed: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	original, replayed := got[0], got[1]
+	if replayed.path != original.path || replayed.query != original.query || replayed.body != original.body {
+		t.Errorf("Expected the request to be replayed as sent, got %+v, want %+v", replayed, original)
+	}
+	if replayed.team != "billing" {
+		t.Errorf("Expected unredacted headers to be replayed, got team '%s'", replayed.team)
+	}
+	if replayed.signature == "" || replayed.signature == RedactedValue {
+		t.Errorf("Expected the replay to be signed again, got '%s'", replayed.signature)
+	}
+	if resp.ID != "msg-1" {
+		t.Errorf("Expected the response to be decoded, got %+v", resp)
+	}
+}
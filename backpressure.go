@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults for fields left unset on a BackpressurePolicy
+const (
+	DefaultBackpressureCacheTTL = 5 * time.Second
+	DefaultBackpressureDelay    = time.Second
+)
+
+// ErrBackpressure is returned, wrapped with the queue and its depth, when a
+// BackpressurePolicy rejects a submission because the queue is too deep
+var ErrBackpressure = errors.New("submission rejected: queue too deep")
+
+// BackpressureAction is what a BackpressurePolicy does with a submission to
+// a queue over its threshold
+type BackpressureAction string
+
+// Backpressure actions
+const (
+	// BackpressureReject fails the submission with ErrBackpressure
+	BackpressureReject BackpressureAction = "reject"
+	// BackpressureDowngrade submits at the next lower priority whose queue is
+	// under its threshold, and rejects when there is none
+	BackpressureDowngrade BackpressureAction = "downgrade"
+	// BackpressureDelay waits before submitting, slowing the producer down
+	BackpressureDelay BackpressureAction = "delay"
+)
+
+// BackpressureThreshold is the queue depth at which a priority sheds load
+type BackpressureThreshold struct {
+	// MaxDepth is the depth from which Action applies; 0 disables the threshold
+	MaxDepth int
+	Action   BackpressureAction
+	// Delay is the wait of BackpressureDelay; defaults to DefaultBackpressureDelay
+	Delay time.Duration
+}
+
+// BackpressurePolicy sheds load when queues are already deep. Before a
+// message is submitted, the depth of its priority's queue is compared with
+// the priority's threshold. Depths are fetched with GetQueueDepths and
+// reused for CacheTTL. If they cannot be fetched, messages are submitted
+// as if no policy were set
+type BackpressurePolicy struct {
+	Thresholds map[Priority]BackpressureThreshold
+	// CacheTTL is how long queue depths are reused; defaults to
+	// DefaultBackpressureCacheTTL
+	CacheTTL time.Duration
+}
+
+// validate checks the policy's thresholds
+func (p *BackpressurePolicy) validate() error {
+	for priority, threshold := range p.Thresholds {
+		if !priority.IsValid() {
+			return fmt.Errorf("backpressure: priority must be 'low', 'medium', or 'high', got '%s'", priority)
+		}
+		if threshold.MaxDepth < 0 {
+			return fmt.Errorf("backpressure: %s max depth cannot be negative", priority)
+		}
+		switch threshold.Action {
+		case BackpressureReject, BackpressureDowngrade, BackpressureDelay:
+		default:
+			return fmt.Errorf("backpressure: %s action must be '%s', '%s', or '%s', got '%s'",
+				priority, BackpressureReject, BackpressureDowngrade, BackpressureDelay, threshold.Action)
+		}
+	}
+	return nil
+}
+
+// backpressureState applies a policy with cached queue depths
+type backpressureState struct {
+	policy BackpressurePolicy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	depths    map[Priority]int
+	fetchedAt time.Time
+}
+
+// newBackpressure returns the state of policy, or nil when policy is nil
+func newBackpressure(policy *BackpressurePolicy, clock Clock) *backpressureState {
+	if policy == nil {
+		return nil
+	}
+	p := *policy
+	if p.CacheTTL <= 0 {
+		p.CacheTTL = DefaultBackpressureCacheTTL
+	}
+	return &backpressureState{policy: p, now: clock.Now}
+}
+
+// fork returns a state with the same policy and an empty cache
+func (b *backpressureState) fork() *backpressureState {
+	if b == nil {
+		return nil
+	}
+	return &backpressureState{policy: b.policy, now: b.now}
+}
+
+// queueDepths returns the cached depths, refreshing them when stale. It
+// returns nil when they cannot be fetched
+func (c *Client) queueDepths(ctx context.Context) map[Priority]int {
+	b := c.backpressure
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.depths != nil && b.now().Sub(b.fetchedAt) < b.policy.CacheTTL {
+		return b.depths
+	}
+	depths, err := c.GetQueueDepths(ctx)
+	if err != nil {
+		return nil
+	}
+	b.depths, b.fetchedAt = depths, b.now()
+	return depths
+}
+
+// overThreshold reports whether priority's queue is at or over its threshold
+func (b *backpressureState) overThreshold(depths map[Priority]int, priority Priority) bool {
+	threshold, ok := b.policy.Thresholds[priority]
+	return ok && threshold.MaxDepth > 0 && depths[priority] >= threshold.MaxDepth
+}
+
+// applyBackpressure applies the client's policy to the messages about to be
+// submitted together: it returns ErrBackpressure if any is rejected, lowers
+// the priority of downgraded ones in place, and waits for the longest delay
+func (c *Client) applyBackpressure(ctx context.Context, reqs ...*MessageRequest) error {
+	if c.backpressure == nil {
+		return nil
+	}
+	b := c.backpressure
+
+	depths := c.queueDepths(ctx)
+	if depths == nil {
+		return nil
+	}
+
+	var delay time.Duration
+	for _, req := range reqs {
+		if !b.overThreshold(depths, req.Priority) {
+			continue
+		}
+
+		threshold := b.policy.Thresholds[req.Priority]
+		switch threshold.Action {
+		case BackpressureReject:
+			return fmt.Errorf("%w: %s queue depth %d, threshold %d", ErrBackpressure, req.Priority, depths[req.Priority], threshold.MaxDepth)
+		case BackpressureDowngrade:
+			lower, ok := b.downgrade(depths, req.Priority)
+			if !ok {
+				return fmt.Errorf("%w: %s queue depth %d, threshold %d, and no lower queue has room",
+					ErrBackpressure, req.Priority, depths[req.Priority], threshold.MaxDepth)
+			}
+			req.Priority = lower
+		case BackpressureDelay:
+			wait := threshold.Delay
+			if wait <= 0 {
+				wait = DefaultBackpressureDelay
+			}
+			delay = max(delay, wait)
+		}
+	}
+
+	if delay == 0 {
+		return nil
+	}
+	return sleep(ctx, c.clock, delay)
+}
+
+// applyBulkBackpressure applies the client's policy to every message of req
+func (c *Client) applyBulkBackpressure(ctx context.Context, req *BulkMessageRequest) error {
+	if c.backpressure == nil {
+		return nil
+	}
+	reqs := make([]*MessageRequest, len(req.Messages))
+	for i := range req.Messages {
+		reqs[i] = &req.Messages[i]
+	}
+	return c.applyBackpressure(ctx, reqs...)
+}
+
+// downgrade returns the highest priority below priority whose queue is under
+// its threshold
+func (b *backpressureState) downgrade(depths map[Priority]int, priority Priority) (Priority, bool) {
+	order := []Priority{PriorityHigh, PriorityMedium, PriorityLow}
+	below := false
+	for _, p := range order {
+		if below && !b.overThreshold(depths, p) {
+			return p, true
+		}
+		if p == priority {
+			below = true
+		}
+	}
+	return "", false
+}
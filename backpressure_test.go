@@ -0,0 +1,130 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// backpressureServer reports fixed queue depths and records submitted priorities
+func backpressureServer(t *testing.T, depths map[Priority]int, statusCalls *atomic.Int32, submitted chan<- Priority) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/workers/status":
+			statusCalls.Add(1)
+			json.NewEncoder(w).Encode(WorkerStatusResponse{
+				LowPriority:    PriorityWorkerInfo{QueueDepth: depths[PriorityLow]},
+				MediumPriority: PriorityWorkerInfo{QueueDepth: depths[PriorityMedium]},
+				HighPriority:   PriorityWorkerInfo{QueueDepth: depths[PriorityHigh]},
+			})
+		case "/api/v1/messages":
+			var req MessageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Invalid request body: %v", err)
+			}
+			submitted <- req.Priority
+			w.Write([]byte(`{"id":"msg-1","status":"queued"}`))
+		case "/api/v1/messages/bulk":
+			var req BulkMessageRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Invalid request body: %v", err)
+			}
+			for _, msg := range req.Messages {
+				submitted <- msg.Priority
+			}
+			w.Write([]byte(`{"status":"success"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestBackpressure(t *testing.T) {
+	var statusCalls atomic.Int32
+	submitted := make(chan Priority, 10)
+	server := backpressureServer(t, map[Priority]int{PriorityHigh: 500, PriorityMedium: 50, PriorityLow: 5000}, &statusCalls, submitted)
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		Backpressure: &BackpressurePolicy{
+			Thresholds: map[Priority]BackpressureThreshold{
+				PriorityHigh:   {MaxDepth: 100, Action: BackpressureDowngrade},
+				PriorityMedium: {MaxDepth: 1000, Action: BackpressureDowngrade},
+				PriorityLow:    {MaxDepth: 1000, Action: BackpressureReject},
+			},
+		},
+	})
+	ctx := context.Background()
+
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/cb", nil)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := <-submitted; got != PriorityMedium {
+		t.Errorf("Expected high priority to be downgraded to medium, got %s", got)
+	}
+
+	_, err := client.PostMessage(ctx, newMessageRequest("pr-2", PriorityLow, TopicPullRequests, "https://example.com/cb", nil))
+	if !errors.Is(err, ErrBackpressure) {
+		t.Errorf("Expected ErrBackpressure for a deep low queue, got %v", err)
+	}
+
+	_, err = client.PostBulkMessages(ctx, &BulkMessageRequest{Messages: []MessageRequest{
+		*newMessageRequest("pr-3", PriorityHigh, TopicPullRequests, "https://example.com/cb", nil),
+		*newMessageRequest("pr-4", PriorityLow, TopicPullRequests, "https://example.com/cb", nil),
+	}})
+	if !errors.Is(err, ErrBackpressure) {
+		t.Errorf("Expected a bulk request with a rejected message to fail, got %v", err)
+	}
+	if len(submitted) != 0 {
+		t.Errorf("Expected nothing to be submitted for a rejected bulk request")
+	}
+
+	if calls := statusCalls.Load(); calls != 1 {
+		t.Errorf("Expected queue depths to be cached, fetched %d times", calls)
+	}
+}
+
+func TestBackpressureDelay(t *testing.T) {
+	var statusCalls atomic.Int32
+	submitted := make(chan Priority, 10)
+	server := backpressureServer(t, map[Priority]int{PriorityMedium: 200}, &statusCalls, submitted)
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		Backpressure: &BackpressurePolicy{
+			Thresholds: map[Priority]BackpressureThreshold{
+				PriorityMedium: {MaxDepth: 100, Action: BackpressureDelay, Delay: 50 * time.Millisecond},
+			},
+		},
+	})
+
+	start := time.Now()
+	if _, err := client.PostMessage(context.Background(), newMessageRequest("pr-1", PriorityMedium, TopicPullRequests, "https://example.com/cb", nil)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected submission to be delayed, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-2", PriorityMedium, TopicPullRequests, "https://example.com/cb", nil)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled while delayed, got %v", err)
+	}
+}
+
+func TestBackpressurePolicyValidation(t *testing.T) {
+	config := &Config{Backpressure: &BackpressurePolicy{
+		Thresholds: map[Priority]BackpressureThreshold{PriorityHigh: {MaxDepth: 10, Action: "drop"}},
+	}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected error for an unknown action")
+	}
+}
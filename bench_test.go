@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// benchmarkServer accepts every submission without decoding it
+func benchmarkServer(b *testing.B) *httptest.Server {
+	b.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		if strings.HasSuffix(r.URL.Path, "/bulk") {
+			w.Write([]byte(`{"status":"success","count":100}`))
+			return
+		}
+		w.Write([]byte(`{"status":"queued","item_id":"pr-1"}`))
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// benchmarkMessage returns a message with a pull request body of a few
+// hundred bytes, typical of production traffic
+func benchmarkMessage(i int) MessageRequest {
+	return *newMessageRequest(fmt.Sprintf("pr-%d", i), PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{
+		"repository": "ericbrisrubio/messages-worker-sdk",
+		"number":     i,
+		"title":      "Rework request encoding to reuse buffers across submissions",
+		"author":     "octocat",
+		"labels":     []string{"performance", "sdk"},
+		"additions":  120,
+		"deletions":  48,
+	})
+}
+
+func BenchmarkPostMessage(b *testing.B) {
+	client := NewClient(&Config{BaseURL: benchmarkServer(b).URL})
+	msg := benchmarkMessage(1)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.PostMessage(ctx, &msg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPostMessageBurst submits from many goroutines at once, as a
+// service does when a burst of events arrives
+func BenchmarkPostMessageBurst(b *testing.B) {
+	client := NewClient(&Config{BaseURL: benchmarkServer(b).URL})
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		msg := benchmarkMessage(1)
+		for pb.Next() {
+			if _, err := client.PostMessage(ctx, &msg); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkPostBulkMessages(b *testing.B) {
+	for _, compressor := range []Compressor{nil, GzipCompressor{}} {
+		name := "uncompressed"
+		if compressor != nil {
+			name = compressor.Encoding()
+		}
+		b.Run(name, func(b *testing.B) {
+			client := NewClient(&Config{BaseURL: benchmarkServer(b).URL, Compressor: compressor})
+			req := &BulkMessageRequest{}
+			for i := 0; i < 100; i++ {
+				req.Messages = append(req.Messages, benchmarkMessage(i))
+			}
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.PostBulkMessages(ctx, req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalBody(b *testing.B) {
+	client := NewClient(nil)
+	req := &BulkMessageRequest{}
+	for i := 0; i < 100; i++ {
+		req.Messages = append(req.Messages, benchmarkMessage(i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, buf, err := client.marshalBody(APIVersionV1, "/api/v1/messages/bulk", req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		buf.release()
+	}
+}
@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBufferSize bounds the buffers kept for reuse, so that one large
+// request does not pin its memory for the life of the process
+const maxPooledBufferSize = 1 << 20
+
+// bufferPool holds the buffers request bodies are encoded and compressed
+// into, and responses are read into
+var bufferPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// getBuffer returns an empty buffer from the pool
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool unless it has grown too large
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// errBufferReleased is returned when a request body is rebuilt after its
+// buffer went back to the pool, which would be a bug in the client
+var errBufferReleased = errors.New("request body buffer already released")
+
+// pooledBytes is a pooled buffer shared by everything sending it: the
+// prepared request and the bodies of each attempt, which the transport may
+// still be reading after the response arrived. The buffer returns to the
+// pool once all of them have released it
+type pooledBytes struct {
+	buf  *bytes.Buffer
+	refs atomic.Int32
+}
+
+// newPooledBytes wraps buf with a single reference held by the caller
+func newPooledBytes(buf *bytes.Buffer) *pooledBytes {
+	p := &pooledBytes{buf: buf}
+	p.refs.Store(1)
+	return p
+}
+
+// acquire adds a reference, unless the buffer has already been released
+func (p *pooledBytes) acquire() bool {
+	for {
+		refs := p.refs.Load()
+		if refs <= 0 {
+			return false
+		}
+		if p.refs.CompareAndSwap(refs, refs+1) {
+			return true
+		}
+	}
+}
+
+// release drops a reference, returning the buffer to the pool with the last.
+// It is safe to call on nil
+func (p *pooledBytes) release() {
+	if p != nil && p.refs.Add(-1) == 0 {
+		putBuffer(p.buf)
+	}
+}
+
+// body returns a request body reading data, a slice of the buffer, that
+// holds a reference until it is closed
+func (p *pooledBytes) body(data []byte) (io.ReadCloser, error) {
+	if !p.acquire() {
+		return nil, errBufferReleased
+	}
+	return &pooledBody{Reader: bytes.NewReader(data), owner: p}, nil
+}
+
+// pooledBody is a request body backed by a pooled buffer
+type pooledBody struct {
+	*bytes.Reader
+	owner *pooledBytes
+	once  sync.Once
+}
+
+// Close releases the body's reference to its buffer
+func (b *pooledBody) Close() error {
+	b.once.Do(b.owner.release)
+	return nil
+}
@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestPooledBytesRelease(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("payload")
+	owner := newPooledBytes(buf)
+
+	body, err := owner.body(buf.Bytes())
+	if err != nil {
+		t.Fatalf("body failed: %v", err)
+	}
+
+	// The prepared request is done, but the transport still reads the body
+	owner.release()
+	data, _ := io.ReadAll(body)
+	if !bytes.Equal(data, []byte("payload")) {
+		t.Errorf("Expected the body to stay readable until closed, got '%s'", data)
+	}
+
+	body.Close()
+	body.Close()
+	if refs := owner.refs.Load(); refs != 0 {
+		t.Errorf("Expected every reference released once, got %d", refs)
+	}
+	if _, err := owner.body(nil); err != errBufferReleased {
+		t.Errorf("Expected a released buffer to be refused, got %v", err)
+	}
+}
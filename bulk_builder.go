@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// NewBulkRequest builds a bulk request with a message per item, mapped by
+// mapFn. Each message is validated as it is added: the request holds the
+// valid ones, and a *ValidationError lists the violations of the others,
+// with fields prefixed by their index in items. Validation happens before
+// the client applies its defaults, so messages must be complete
+func NewBulkRequest[T any](items []T, mapFn func(T) MessageRequest) (*BulkMessageRequest, error) {
+	req := &BulkMessageRequest{Messages: make([]MessageRequest, 0, len(items))}
+	verr := &ValidationError{}
+	for i, item := range items {
+		req.add(mapFn(item), fmt.Sprintf("items[%d].", i), verr)
+	}
+	return req, verr.errOrNil()
+}
+
+// BulkFromChannel collects up to maxBatch messages from ch into a bulk
+// request, returning early when ch is closed or ctx is done. Messages are
+// validated like in NewBulkRequest, with fields prefixed by their position
+// in the batch, so that invalid ones are reported rather than lost. It
+// returns io.EOF once ch is closed and drained, and ctx's error when ctx is
+// done before any message arrived. Call it in a loop to submit a stream of
+// messages in batches
+func BulkFromChannel(ctx context.Context, ch <-chan MessageRequest, maxBatch int) (*BulkMessageRequest, error) {
+	if maxBatch <= 0 {
+		return nil, fmt.Errorf("max batch must be positive, got %d", maxBatch)
+	}
+
+	req := &BulkMessageRequest{}
+	verr := &ValidationError{}
+	for received := 0; received < maxBatch; received++ {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				if received == 0 {
+					return nil, io.EOF
+				}
+				return req, verr.errOrNil()
+			}
+			req.add(msg, fmt.Sprintf("messages[%d].", received), verr)
+		case <-ctx.Done():
+			if received == 0 {
+				return nil, ctx.Err()
+			}
+			return req, verr.errOrNil()
+		}
+	}
+	return req, verr.errOrNil()
+}
+
+// add appends msg to r if it is valid, and records its violations in verr
+// otherwise
+func (r *BulkMessageRequest) add(msg MessageRequest, prefix string, verr *ValidationError) {
+	violations := len(verr.Violations)
+	msg.validate(prefix, verr)
+	if len(verr.Violations) == violations {
+		r.Messages = append(r.Messages, msg)
+	}
+}
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+type prEvent struct {
+	Number int
+	Urgent bool
+}
+
+func prEventMessage(pr prEvent) MessageRequest {
+	priority := PriorityLow
+	if pr.Urgent {
+		priority = PriorityHigh
+	}
+	itemID := ""
+	if pr.Number > 0 {
+		itemID = GenerateItemID("pr")
+	}
+	return *newMessageRequest(itemID, priority, TopicPullRequests, "https://example.com/callback", pr)
+}
+
+func TestNewBulkRequest(t *testing.T) {
+	req, err := NewBulkRequest([]prEvent{{Number: 1, Urgent: true}, {Number: 2}}, prEventMessage)
+	if err != nil {
+		t.Fatalf("NewBulkRequest failed: %v", err)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Priority != PriorityHigh || req.Messages[1].Priority != PriorityLow {
+		t.Errorf("Unexpected request %+v", req)
+	}
+}
+
+func TestNewBulkRequestInvalid(t *testing.T) {
+	req, err := NewBulkRequest([]prEvent{{Number: 1}, {Number: 0}, {Number: 3}, {Number: -1}}, prEventMessage)
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if len(verr.Violations) != 2 || verr.Violations[0].Field != "items[1].item_id" || verr.Violations[1].Field != "items[3].item_id" {
+		t.Errorf("Expected violations by item index, got %+v", verr.Violations)
+	}
+	if len(req.Messages) != 2 {
+		t.Errorf("Expected the 2 valid messages to be kept, got %d", len(req.Messages))
+	}
+}
+
+func TestBulkFromChannel(t *testing.T) {
+	ch := make(chan MessageRequest, 6)
+	for i := 1; i <= 5; i++ {
+		ch <- prEventMessage(prEvent{Number: i})
+	}
+	ch <- prEventMessage(prEvent{})
+	close(ch)
+
+	ctx := context.Background()
+	var batches []*BulkMessageRequest
+	var verr *ValidationError
+	for {
+		req, err := BulkFromChannel(ctx, ch, 2)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.As(err, &verr) {
+			t.Fatalf("BulkFromChannel failed: %v", err)
+		}
+		batches = append(batches, req)
+	}
+
+	if len(batches) != 3 || len(batches[0].Messages) != 2 || len(batches[1].Messages) != 2 || len(batches[2].Messages) != 1 {
+		t.Fatalf("Expected batches of 2, 2, and 1 valid messages, got %d batches", len(batches))
+	}
+	if verr == nil || verr.Violations[0].Field != "messages[1].item_id" {
+		t.Errorf("Expected the invalid message to be reported at its batch position, got %v", verr)
+	}
+}
+
+func TestBulkFromChannelContext(t *testing.T) {
+	ch := make(chan MessageRequest, 1)
+	ch <- prEventMessage(prEvent{Number: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// A partial batch is returned when the context ends
+	req, err := BulkFromChannel(ctx, ch, 10)
+	if err != nil || len(req.Messages) != 1 {
+		t.Fatalf("Expected a partial batch, got %+v, %v", req, err)
+	}
+
+	if _, err := BulkFromChannel(ctx, ch, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context's error, got %v", err)
+	}
+	if _, err := BulkFromChannel(ctx, ch, 0); err == nil {
+		t.Error("Expected an error for a non-positive batch size")
+	}
+}
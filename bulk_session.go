@@ -0,0 +1,274 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults for bulk sessions
+const (
+	DefaultBulkSessionChunkSize    = 100
+	DefaultBulkSessionMaxRetries   = 3
+	DefaultBulkSessionRetryBackoff = 500 * time.Millisecond
+)
+
+// ErrSessionMismatch is returned when a bulk session is resumed with
+// messages that differ from those its checkpoints were recorded for
+var ErrSessionMismatch = errors.New("bulk session checkpoints do not match the messages")
+
+// Checkpoint records that a chunk of a bulk session was acknowledged
+type Checkpoint struct {
+	// Chunk is the index of the chunk in the session's messages
+	Chunk int `json:"chunk"`
+	// Digest identifies the messages of the chunk, so that resuming with
+	// different messages is detected
+	Digest  string    `json:"digest"`
+	AckedAt time.Time `json:"acked_at"`
+}
+
+// CheckpointStore holds the checkpoints of bulk sessions, e.g. in a file or
+// database, so that a session can resume in another process
+type CheckpointStore interface {
+	// Checkpoints returns every checkpoint saved for the session
+	Checkpoints(ctx context.Context, sessionID string) ([]Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, sessionID string, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore held in memory, which resumes
+// sessions within a single process
+type MemoryCheckpointStore struct {
+	mu       sync.Mutex
+	sessions map[string][]Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{sessions: make(map[string][]Checkpoint)}
+}
+
+// Checkpoints returns the checkpoints saved for the session
+func (s *MemoryCheckpointStore) Checkpoints(ctx context.Context, sessionID string) ([]Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Checkpoint(nil), s.sessions[sessionID]...), nil
+}
+
+// SaveCheckpoint records a checkpoint for the session
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, sessionID string, checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sessionID] = append(s.sessions[sessionID], checkpoint)
+	return nil
+}
+
+// BulkSessionConfig configures a BulkSession
+type BulkSessionConfig struct {
+	// ID identifies the session; running a session with the same ID again
+	// skips the chunks acknowledged by earlier runs
+	ID string
+	// ChunkSize is the number of messages sent per bulk request; defaults to
+	// DefaultBulkSessionChunkSize. It must stay the same across runs
+	ChunkSize int
+	// Store holds the session's checkpoints; defaults to an in-memory store
+	// owned by the session
+	Store CheckpointStore
+	// MaxRetries is the number of times a chunk failing with an error
+	// IsRetryable accepts is retried; defaults to
+	// DefaultBulkSessionMaxRetries, negative disables retries
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled after each;
+	// defaults to DefaultBulkSessionRetryBackoff
+	RetryBackoff time.Duration
+}
+
+// withDefaults fills unset bulk session options
+func (c BulkSessionConfig) withDefaults() BulkSessionConfig {
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = DefaultBulkSessionChunkSize
+	}
+	if c.Store == nil {
+		c.Store = NewMemoryCheckpointStore()
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultBulkSessionMaxRetries
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = DefaultBulkSessionRetryBackoff
+	}
+	return c
+}
+
+// BulkSession submits a large set of messages in chunks and checkpoints each
+// acknowledged chunk, so that a run that dies halfway can be repeated with
+// the same session ID and only submits the chunks that were not
+// acknowledged
+type BulkSession struct {
+	client *Client
+	config BulkSessionConfig
+}
+
+// NewBulkSession returns a session submitting through the client
+func (c *Client) NewBulkSession(config BulkSessionConfig) (*BulkSession, error) {
+	if config.ID == "" {
+		return nil, fmt.Errorf("session id is required")
+	}
+	return &BulkSession{client: c, config: config.withDefaults()}, nil
+}
+
+// BulkSessionResult reports the outcome of a session run
+type BulkSessionResult struct {
+	Chunks int
+	// Skipped is the number of chunks acknowledged by earlier runs
+	Skipped int
+	// Submitted is the number of chunks acknowledged in this run
+	Submitted int
+	// Failed lists the chunks that were not acknowledged; running the
+	// session again retries them
+	Failed []int
+	// Responses holds the response to every chunk submitted in this run.
+	// Messages rejected individually are reported in their Errors; their
+	// chunk is still acknowledged since resubmitting them would not help
+	Responses []*BulkMessageResponse
+}
+
+// Complete reports whether every chunk of the session has been acknowledged
+func (r *BulkSessionResult) Complete() bool {
+	return r.Skipped+r.Submitted == r.Chunks
+}
+
+// Submit sends the chunks of messages not yet acknowledged, in order. A
+// chunk failing with a retryable error is retried with backoff, unless
+// ctx's deadline would pass before the next attempt; chunks that still
+// fail are listed in the result and the remaining ones are sent anyway.
+// Each chunk carries an idempotency key derived from the session ID, so a
+// chunk acknowledged by the service just before a crash is not enqueued
+// twice by services that honor it. The error reports the failed chunks, or
+// why the run stopped early
+func (s *BulkSession) Submit(ctx context.Context, messages []MessageRequest) (*BulkSessionResult, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	checkpoints, err := s.config.Store.Checkpoints(ctx, s.config.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+
+	size := s.config.ChunkSize
+	result := &BulkSessionResult{Chunks: (len(messages) + size - 1) / size}
+	acked := make(map[int]bool, len(checkpoints))
+	for _, cp := range checkpoints {
+		if cp.Chunk >= result.Chunks {
+			return nil, fmt.Errorf("%w: chunk %d is out of range", ErrSessionMismatch, cp.Chunk)
+		}
+		chunk := messages[cp.Chunk*size : min((cp.Chunk+1)*size, len(messages))]
+		digest, err := chunkDigest(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to digest chunk %d: %w", cp.Chunk, err)
+		}
+		if cp.Digest != digest {
+			return nil, fmt.Errorf("%w: chunk %d differs", ErrSessionMismatch, cp.Chunk)
+		}
+		acked[cp.Chunk] = true
+	}
+
+	var errs []error
+	for i := 0; i < result.Chunks; i++ {
+		if acked[i] {
+			result.Skipped++
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			result.Failed = append(result.Failed, failedChunks(i, result.Chunks, acked)...)
+			return result, err
+		}
+
+		chunk := messages[i*size : min((i+1)*size, len(messages))]
+		digest, err := chunkDigest(chunk)
+		if err != nil {
+			result.Failed = append(result.Failed, i)
+			errs = append(errs, fmt.Errorf("chunk %d: %w", i, err))
+			continue
+		}
+		resp, err := s.submitChunk(ctx, i, chunk)
+		if err != nil {
+			result.Failed = append(result.Failed, i)
+			errs = append(errs, fmt.Errorf("chunk %d: %w", i, err))
+			continue
+		}
+
+		result.Submitted++
+		result.Responses = append(result.Responses, resp)
+		checkpoint := Checkpoint{Chunk: i, Digest: digest, AckedAt: s.client.clock.Now().UTC()}
+		if err := s.config.Store.SaveCheckpoint(ctx, s.config.ID, checkpoint); err != nil {
+			return result, fmt.Errorf("failed to save checkpoint for chunk %d: %w", i, err)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// submitChunk posts a chunk, retrying retryable failures while ctx's
+// deadline allows
+func (s *BulkSession) submitChunk(ctx context.Context, index int, chunk []MessageRequest) (*BulkMessageResponse, error) {
+	ctx = withIdempotencyKey(ctx, fmt.Sprintf("%s/%d", s.config.ID, index))
+	// Generated IDs and encrypted payloads are filled in once, so that every
+	// attempt sends the same body and shares its request fingerprint
+	req := s.client.withBulkDefaults(ctx, &BulkMessageRequest{Messages: chunk})
+	for i := range req.Messages {
+		if err := s.client.preparePayload(ctx, fmt.Sprintf("messages[%d].", i), &req.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := s.client.PostBulkMessages(ctx, req)
+		if IsBulkPartialError(err) {
+			return resp, nil
+		}
+		if err == nil || attempt >= s.config.MaxRetries || !IsRetryable(err) {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(s.client.clock.Now()) < backoff {
+			return nil, err
+		}
+
+		if err := sleep(ctx, s.client.clock, backoff); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// chunkDigest identifies a chunk by the wire form of its messages as the
+// caller gave them. Item IDs may be left for the client to generate, so
+// every field counts; generated values are left out, since they differ
+// between runs
+func chunkDigest(chunk []MessageRequest) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for i := range chunk {
+		if err := enc.Encode(&chunk[i]); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// failedChunks returns the chunks from start on that were not acknowledged
+func failedChunks(start, chunks int, acked map[int]bool) []int {
+	var failed []int
+	for i := start; i < chunks; i++ {
+		if !acked[i] {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
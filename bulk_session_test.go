@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bulkSessionServer records the item IDs of every accepted chunk and fails
+// the chunks whose first item ID is in fail
+type bulkSessionServer struct {
+	mu       sync.Mutex
+	accepted [][]string
+	keys     []string
+	fail     map[string]int
+}
+
+func (s *bulkSessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req BulkMessageRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	first := req.Messages[0].ItemID
+	if s.fail[first] > 0 {
+		s.fail[first]--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	ids := make([]string, len(req.Messages))
+	for i, msg := range req.Messages {
+		ids[i] = msg.ItemID
+	}
+	s.accepted = append(s.accepted, ids)
+	s.keys = append(s.keys, r.Header.Get(IdempotencyKeyHeader))
+	json.NewEncoder(w).Encode(BulkMessageResponse{Status: "success", Count: len(ids)})
+}
+
+func sessionMessages(n int) []MessageRequest {
+	messages := make([]MessageRequest, n)
+	for i := range messages {
+		messages[i] = *newMessageRequest(fmt.Sprintf("pr-%d", i), PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+	}
+	return messages
+}
+
+func TestBulkSessionResume(t *testing.T) {
+	handler := &bulkSessionServer{fail: map[string]int{"pr-4": 10}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	store := NewMemoryCheckpointStore()
+	config := BulkSessionConfig{ID: "backfill-1", ChunkSize: 2, Store: store, MaxRetries: 1, RetryBackoff: time.Millisecond}
+	messages := sessionMessages(7)
+
+	session, err := client.NewBulkSession(config)
+	if err != nil {
+		t.Fatalf("NewBulkSession failed: %v", err)
+	}
+	result, err := session.Submit(context.Background(), messages)
+	if err == nil {
+		t.Fatal("Expected the failing chunk to be reported")
+	}
+	if result.Chunks != 4 || result.Submitted != 3 || len(result.Failed) != 1 || result.Failed[0] != 2 || result.Complete() {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+	if handler.keys[0] != "backfill-1/0" {
+		t.Errorf("Expected an idempotency key per chunk, got '%s'", handler.keys[0])
+	}
+
+	// A new run with the same ID only sends the failed chunk
+	handler.fail["pr-4"] = 0
+	session, _ = client.NewBulkSession(config)
+	result, err = session.Submit(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Expected the resumed run to succeed, got %v", err)
+	}
+	if result.Skipped != 3 || result.Submitted != 1 || !result.Complete() {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(handler.accepted) != 4 || handler.accepted[3][0] != "pr-4" {
+		t.Errorf("Expected only the failed chunk to be resent, got %v", handler.accepted)
+	}
+
+	// Resuming with other messages is refused
+	if _, err := session.Submit(context.Background(), sessionMessages(8)[1:]); !errors.Is(err, ErrSessionMismatch) {
+		t.Errorf("Expected ErrSessionMismatch, got %v", err)
+	}
+}
+
+func TestBulkSessionRetriesWithinDeadline(t *testing.T) {
+	handler := &bulkSessionServer{fail: map[string]int{"pr-0": 1}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	session, _ := client.NewBulkSession(BulkSessionConfig{ID: "s", RetryBackoff: time.Millisecond})
+	if result, err := session.Submit(context.Background(), sessionMessages(3)); err != nil || !result.Complete() {
+		t.Fatalf("Expected the chunk to succeed on retry, got %+v, %v", result, err)
+	}
+
+	// A retry that would outlast the deadline is not attempted
+	handler.fail["pr-0"] = 1
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	session, _ = client.NewBulkSession(BulkSessionConfig{ID: "t", RetryBackoff: time.Minute})
+	start := time.Now()
+	result, err := session.Submit(ctx, sessionMessages(3))
+	if err == nil || len(result.Failed) != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the chunk to fail without waiting, got %+v, %v after %v", result, err, time.Since(start))
+	}
+}
+
+func TestBulkSessionDigestsGeneratedItemIDs(t *testing.T) {
+	server := httptest.NewServer(&bulkSessionServer{})
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, ItemIDGenerator: UUIDv7})
+	config := BulkSessionConfig{ID: "backfill-2", ChunkSize: 2, Store: NewMemoryCheckpointStore()}
+	messages := sessionMessages(4)
+	for i := range messages {
+		messages[i].ItemID = ""
+	}
+
+	session, _ := client.NewBulkSession(config)
+	if _, err := session.Submit(context.Background(), messages); err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	// The same messages resume although their item IDs are generated anew
+	result, err := session.Submit(context.Background(), messages)
+	if err != nil || result.Skipped != 2 {
+		t.Errorf("Expected every chunk to be skipped, got %+v, %v", result, err)
+	}
+
+	// Other messages without item IDs are still told apart
+	messages[3].CallbackURL = "https://example.com/other"
+	if _, err := session.Submit(context.Background(), messages); !errors.Is(err, ErrSessionMismatch) {
+		t.Errorf("Expected ErrSessionMismatch, got %v", err)
+	}
+}
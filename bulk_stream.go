@@ -0,0 +1,215 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Codes reported on acks for messages rejected before they were sent
+const (
+	AckCodeValidationFailed = "validation_failed"
+	AckCodeOffloadFailed    = "offload_failed"
+)
+
+// BulkMessageAck reports the outcome of a single streamed message
+type BulkMessageAck struct {
+	// Index is the position of the message in the stream
+	Index  int    `json:"index"`
+	ItemID string `json:"item_id"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	// Code and Reason are set when the message was rejected
+	Code   string `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Accepted reports whether the message was queued
+func (a BulkMessageAck) Accepted() bool {
+	return a.Code == ""
+}
+
+// BulkStreamResult summarizes a streamed bulk submission
+type BulkStreamResult struct {
+	// Sent is the number of messages written to the stream
+	Sent     int
+	Accepted int
+	Rejected int
+}
+
+// bulkStreamLine is a single NDJSON line of a streamed bulk request
+type bulkStreamLine struct {
+	Index   int             `json:"index"`
+	Message *MessageRequest `json:"message"`
+}
+
+// StreamBulkMessages submits every message received from messages as a single
+// NDJSON stream, without holding them all in memory. The stream ends when
+// messages is closed. onAck, when not nil, is called once per message as its
+// outcome arrives; messages failing client-side validation are acked locally
+// and not sent. Acks are delivered sequentially, never concurrently.
+//
+// The client timeout does not apply to streams; use ctx to bound them
+func (c *Client) StreamBulkMessages(ctx context.Context, messages <-chan MessageRequest, onAck func(BulkMessageAck)) (*BulkStreamResult, error) {
+	if messages == nil {
+		return nil, fmt.Errorf("messages channel cannot be nil")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &BulkStreamResult{}
+	var mu sync.Mutex
+	deliver := func(ack BulkMessageAck) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ack.Accepted() {
+			result.Accepted++
+		} else {
+			result.Rejected++
+		}
+		if onAck != nil {
+			onAck(ack)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	written := make(chan int, 1)
+	go func() {
+		written <- c.writeBulkStream(ctx, pw, messages, deliver)
+	}()
+
+	var err error
+	if c.transport != nil {
+		err = c.streamBulkMessagesTransport(ctx, pr, deliver)
+	} else {
+		path := "/api/v1/messages/bulk/stream"
+		var resp *http.Response
+		resp, err = c.openBulkStream(ctx, path, pr)
+		c.telemetry.record(http.MethodPost, path, resp, err)
+
+		if err == nil {
+			err = c.readBulkAcks(resp, deliver)
+		}
+	}
+
+	// Unblock the writer if the server stopped reading early
+	cancel()
+	pr.CloseWithError(context.Canceled)
+	result.Sent = <-written
+
+	return result, err
+}
+
+// writeBulkStream encodes messages to w as NDJSON until the channel is
+// closed or ctx is done, and returns how many were written
+func (c *Client) writeBulkStream(ctx context.Context, w *io.PipeWriter, messages <-chan MessageRequest, deliver func(BulkMessageAck)) int {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	index, sent := 0, 0
+	for {
+		var msg MessageRequest
+		var ok bool
+		select {
+		case <-ctx.Done():
+			w.CloseWithError(ctx.Err())
+			return sent
+		case msg, ok = <-messages:
+		}
+		if !ok {
+			if err := bw.Flush(); err != nil {
+				w.CloseWithError(err)
+				return sent
+			}
+			w.Close()
+			return sent
+		}
+
+		i := index
+		index++
+
+		req := c.withDefaults(ctx, &msg)
+		if err := c.preparePayload(ctx, "", req); err != nil {
+			code := AckCodeOffloadFailed
+			if IsValidationError(err) {
+				code = AckCodeValidationFailed
+			}
+			deliver(BulkMessageAck{Index: i, ItemID: msg.ItemID, Code: code, Reason: err.Error()})
+			continue
+		}
+		if err := req.Validate(); err != nil {
+			deliver(BulkMessageAck{Index: i, ItemID: msg.ItemID, Code: AckCodeValidationFailed, Reason: err.Error()})
+			continue
+		}
+
+		if err := enc.Encode(bulkStreamLine{Index: i, Message: req}); err != nil {
+			w.CloseWithError(err)
+			return sent
+		}
+		sent++
+
+		// Flush when the producer has nothing ready so the server sees
+		// messages promptly instead of waiting for the buffer to fill
+		if len(messages) == 0 {
+			if err := bw.Flush(); err != nil {
+				w.CloseWithError(err)
+				return sent
+			}
+		}
+	}
+}
+
+// openBulkStream sends the streaming request with body and returns the
+// response once its headers arrive
+func (c *Client) openBulkStream(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	// Streams may run far longer than a single request, so the client-wide
+	// timeout must not apply
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	c.version.observe(resp)
+	c.recordResponseMeta(resp)
+
+	return resp, nil
+}
+
+// readBulkAcks delivers each NDJSON ack in the response body
+func (c *Client) readBulkAcks(resp *http.Response, deliver func(BulkMessageAck)) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return c.translateError(resp, msg)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ack BulkMessageAck
+		if err := json.Unmarshal(scanner.Bytes(), &ack); err != nil {
+			return fmt.Errorf("failed to parse ack: %w", err)
+		}
+		deliver(ack)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read acks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStreamBulkMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/messages/bulk/stream" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("Expected NDJSON content type, got '%s'", r.Header.Get("Content-Type"))
+		}
+
+		http.NewResponseController(w).EnableFullDuplex()
+		w.WriteHeader(http.StatusOK)
+
+		enc := json.NewEncoder(w)
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line bulkStreamLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Errorf("Failed to decode line: %v", err)
+				return
+			}
+			ack := BulkMessageAck{Index: line.Index, ItemID: line.Message.ItemID, ID: "msg-" + line.Message.ItemID, Status: "queued"}
+			if line.Message.ItemID == "pr-3" {
+				ack = BulkMessageAck{Index: line.Index, ItemID: "pr-3", Code: "duplicate", Reason: "already queued"}
+			}
+			enc.Encode(ack)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+
+	messages := make(chan MessageRequest)
+	go func() {
+		defer close(messages)
+		for i := 0; i < 5; i++ {
+			req := MessageRequest{
+				ItemID:      fmt.Sprintf("pr-%d", i),
+				Priority:    PriorityMedium,
+				Topic:       TopicPullRequests,
+				CallbackURL: "https://example.com/callback",
+			}
+			if i == 1 {
+				req.CallbackURL = "not a url"
+			}
+			messages <- req
+		}
+	}()
+
+	var acks []BulkMessageAck
+	result, err := client.StreamBulkMessages(context.Background(), messages, func(ack BulkMessageAck) {
+		acks = append(acks, ack)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Sent != 4 || result.Accepted != 3 || result.Rejected != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(acks) != 5 {
+		t.Fatalf("Expected 5 acks, got %d", len(acks))
+	}
+
+	byIndex := make(map[int]BulkMessageAck)
+	for _, ack := range acks {
+		byIndex[ack.Index] = ack
+	}
+	if byIndex[1].Code != AckCodeValidationFailed {
+		t.Errorf("Expected local validation ack for index 1, got %+v", byIndex[1])
+	}
+	if byIndex[3].Accepted() || !byIndex[4].Accepted() || byIndex[4].ID != "msg-pr-4" {
+		t.Errorf("Unexpected acks: %+v", acks)
+	}
+}
+
+func TestStreamBulkMessagesRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"bulk streaming disabled"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+
+	messages := make(chan MessageRequest)
+	close(messages)
+
+	_, err := client.StreamBulkMessages(context.Background(), messages, nil)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 API error, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequestOption customizes a request made with Call
+type RequestOption func(*callOptions)
+
+// callOptions holds the settings of a Call
+type callOptions struct {
+	query  url.Values
+	header http.Header
+	retry  *RetryPolicy
+}
+
+// WithQuery adds query parameters to the request
+func WithQuery(query url.Values) RequestOption {
+	return func(o *callOptions) {
+		for key, values := range query {
+			for _, value := range values {
+				o.query.Add(key, value)
+			}
+		}
+	}
+}
+
+// WithHeader sets a header on the request. Headers the client sets itself,
+// such as User-Agent and the signature headers, take precedence
+func WithHeader(key, value string) RequestOption {
+	return func(o *callOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithRetry retries requests failing with an error IsRetryable accepts,
+// waiting between attempts as policy describes. Only use it for requests
+// that are safe to repeat
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(o *callOptions) {
+		o.retry = &policy
+	}
+}
+
+type callHeaderKey struct{}
+
+// setCallHeaders adds the headers of a Call carried by ctx to header
+func setCallHeaders(ctx context.Context, header http.Header) {
+	extra, _ := ctx.Value(callHeaderKey{}).(http.Header)
+	for key, values := range extra {
+		if header.Get(key) == "" {
+			header[key] = values
+		}
+	}
+}
+
+// Call sends a request to an endpoint the SDK has no method for yet,
+// through the same transport stack, headers, signing, API version mapping,
+// telemetry, and error handling as the typed methods. path is relative to
+// the base URL and follows the v1 layout, e.g. "/api/v1/messages/archive";
+// absolute URLs are rejected. body, when not nil, is encoded like any other
+// request body, and the response is decoded into out unless out is nil.
+// Errors are returned as *APIError like those of the typed methods
+func (c *Client) Call(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) error {
+	if method == "" {
+		return fmt.Errorf("method is required")
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid path '%s': %w", path, err)
+	}
+	if u.IsAbs() || u.Host != "" || !strings.HasPrefix(u.Path, "/") {
+		return fmt.Errorf("path '%s' must be relative to the base URL and start with '/'", path)
+	}
+
+	options := callOptions{query: u.Query(), header: make(http.Header)}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if len(options.query) > 0 {
+		path = u.EscapedPath() + "?" + options.query.Encode()
+	}
+	if len(options.header) > 0 {
+		ctx = context.WithValue(ctx, callHeaderKey{}, options.header)
+	}
+
+	prepared, err := c.prepareRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer prepared.release()
+	if options.retry == nil {
+		return c.call(ctx, prepared, out)
+	}
+
+	// Every attempt sends the body serialized once, so that retries are
+	// byte for byte the same request
+	policy := options.retry.withDefaults()
+	for attempt := 1; ; attempt++ {
+		err := c.call(ctx, prepared, out)
+		if err == nil || attempt >= policy.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		delay := policy.Delay(attempt + 1)
+		if c.logger != nil {
+			c.logger.Warn("retrying request", "method", method, "path", path,
+				"fingerprint", prepared.fingerprint, "attempt", attempt, "delay", delay, "error", err)
+		}
+
+		if err := sleep(ctx, c.clock, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// call makes a single attempt of a Call
+func (c *Client) call(ctx context.Context, prepared *preparedRequest, out interface{}) error {
+	resp, err := c.doPrepared(ctx, prepared)
+	if err != nil {
+		return err
+	}
+	return c.parseResponse(resp, out)
+}
@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/messages/msg-1/snooze" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("notify") != "true" || r.URL.Query().Get("reason") != "deploy freeze" {
+			t.Errorf("Expected query parameters, got '%s'", r.URL.RawQuery)
+		}
+		if r.Header.Get("X-Preview") != "snooze" || r.Header.Get("X-Team") != "infra" {
+			t.Errorf("Expected call and client headers, got %v", r.Header)
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"status": "snoozed", "until": body["until"]})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Team: "infra"})
+	var out struct {
+		Status string `json:"status"`
+		Until  string `json:"until"`
+	}
+	err := client.Call(context.Background(), http.MethodPost, "/api/v1/messages/msg-1/snooze?notify=true",
+		map[string]string{"until": "2026-01-01T00:00:00Z"}, &out,
+		WithQuery(url.Values{"reason": {"deploy freeze"}}),
+		WithHeader("X-Preview", "snooze"),
+	)
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if out.Status != "snoozed" || out.Until != "2026-01-01T00:00:00Z" {
+		t.Errorf("Unexpected response %+v", out)
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not_found","message":"no such endpoint"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	var apiErr *APIError
+	if err := client.Call(ctx, http.MethodGet, "/api/v1/preview", nil, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected an APIError, got %v", err)
+	}
+	for _, path := range []string{"https://evil.example.com/api/v1/preview", "//evil.example.com/api", "api/v1/preview"} {
+		if err := client.Call(ctx, http.MethodGet, path, nil, nil); err == nil || IsAPIError(err) {
+			t.Errorf("Expected '%s' to be rejected before sending, got %v", path, err)
+		}
+	}
+}
+
+func TestCallRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: BackoffFixed, InitialDelay: time.Millisecond}
+	if err := client.Call(context.Background(), http.MethodGet, "/api/v1/preview", nil, nil, WithRetry(policy)); err != nil {
+		t.Fatalf("Expected the call to succeed after retries, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	if err := client.Call(context.Background(), http.MethodGet, "/api/v1/preview", nil, nil); err == nil || attempts != 1 {
+		t.Errorf("Expected a single failing attempt without WithRetry, got %d attempts, %v", attempts, err)
+	}
+}
@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// MaxCallbackSize is the largest callback body DecodeCallback reads, in
+// bytes; it leaves room for a result as large as the submitted payload
+const MaxCallbackSize = 2 * MaxPayloadSize
+
+// CallbackEnvelope is the body the service posts to a message's callback URL
+// once a worker has processed it
+type CallbackEnvelope struct {
+	MessageID string   `json:"message_id"`
+	ItemID    string   `json:"item_id"`
+	Topic     Topic    `json:"topic,omitempty"`
+	Priority  Priority `json:"priority,omitempty"`
+	// Attempt is the processing attempt that produced the callback, from 1
+	Attempt int `json:"attempt"`
+	// Status is StatusCompleted or StatusFailed
+	Status MessageStatus `json:"status"`
+	// Result is the body returned by the worker, if any
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error describes why processing failed
+	Error    string            `json:"error,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// CorrelationID is the correlation ID the message was submitted with
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	CompletedAt   time.Time `json:"completed_at"`
+}
+
+// Succeeded reports whether the worker completed the message
+func (e *CallbackEnvelope) Succeeded() bool {
+	return e.Status == StatusCompleted
+}
+
+// Failed reports whether the worker gave up on the message
+func (e *CallbackEnvelope) Failed() bool {
+	return e.Status.IsFailure()
+}
+
+// DecodeResult unmarshals the callback's result into v. A callback without
+// a result leaves v unchanged
+func (e *CallbackEnvelope) DecodeResult(v interface{}) error {
+	if len(e.Result) == 0 || string(e.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(e.Result, v); err != nil {
+		return fmt.Errorf("failed to unmarshal callback result: %w", err)
+	}
+	return nil
+}
+
+// DecodeCallback reads the callback posted in r. The body is restored
+// afterwards, so middleware such as receiver.Persist can still read it
+func DecodeCallback(r *http.Request) (*CallbackEnvelope, error) {
+	if r.Body == nil {
+		return nil, fmt.Errorf("callback has no body")
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxCallbackSize+1))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read callback body: %w", err)
+	}
+	if len(body) > MaxCallbackSize {
+		return nil, fmt.Errorf("callback body exceeds %d bytes", MaxCallbackSize)
+	}
+	return decodeCallbackEnvelope(body)
+}
+
+// DecodeCallbackBody reads the callback posted in r and unmarshals its
+// result into a T
+func DecodeCallbackBody[T any](r *http.Request) (*CallbackEnvelope, T, error) {
+	var result T
+	envelope, err := DecodeCallback(r)
+	if err != nil {
+		return nil, result, err
+	}
+	if err := envelope.DecodeResult(&result); err != nil {
+		return envelope, result, err
+	}
+	return envelope, result, nil
+}
+
+// Envelope decodes the callback body received by an ephemeral endpoint
+func (r CallbackResult) Envelope() (*CallbackEnvelope, error) {
+	return decodeCallbackEnvelope(r.Body)
+}
+
+// decodeCallbackEnvelope unmarshals and checks a callback body
+func decodeCallbackEnvelope(body []byte) (*CallbackEnvelope, error) {
+	var envelope CallbackEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal callback: %w", err)
+	}
+	if envelope.MessageID == "" {
+		return nil, fmt.Errorf("callback has no message_id")
+	}
+	return &envelope, nil
+}
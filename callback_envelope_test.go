@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCallback(t *testing.T) {
+	body := `{"message_id":"msg-1","item_id":"pr-123","topic":"pullrequests","priority":"high",
+		"attempt":2,"status":"completed","result":{"score":7},"metadata":{"request-id":"abc"},
+		"completed_at":"2024-01-01T10:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+
+	envelope, err := DecodeCallback(req)
+	if err != nil {
+		t.Fatalf("DecodeCallback failed: %v", err)
+	}
+	if envelope.MessageID != "msg-1" || envelope.ItemID != "pr-123" || envelope.Topic != TopicPullRequests ||
+		envelope.Priority != PriorityHigh || envelope.Attempt != 2 || envelope.Metadata["request-id"] != "abc" {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+	if !envelope.Succeeded() || envelope.Failed() {
+		t.Errorf("Expected a successful callback, got status %q", envelope.Status)
+	}
+	if envelope.CompletedAt.IsZero() {
+		t.Error("Expected completed_at to be decoded")
+	}
+
+	// The body stays readable for later handlers
+	rest, err := io.ReadAll(req.Body)
+	if err != nil || string(rest) != body {
+		t.Errorf("Expected the body to be restored, got %q (%v)", rest, err)
+	}
+}
+
+func TestDecodeCallbackBody(t *testing.T) {
+	type review struct {
+		Score int `json:"score"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/callback",
+		strings.NewReader(`{"message_id":"msg-1","item_id":"pr-123","attempt":1,"status":"completed","result":{"score":7}}`))
+	envelope, result, err := DecodeCallbackBody[review](req)
+	if err != nil {
+		t.Fatalf("DecodeCallbackBody failed: %v", err)
+	}
+	if envelope.MessageID != "msg-1" || result.Score != 7 {
+		t.Errorf("Unexpected callback %+v with result %+v", envelope, result)
+	}
+
+	// A failed callback has no result
+	req = httptest.NewRequest(http.MethodPost, "/callback",
+		strings.NewReader(`{"message_id":"msg-2","item_id":"pr-124","attempt":3,"status":"failed","error":"worker crashed"}`))
+	envelope, result, err = DecodeCallbackBody[review](req)
+	if err != nil {
+		t.Fatalf("DecodeCallbackBody failed: %v", err)
+	}
+	if !envelope.Failed() || envelope.Error != "worker crashed" || result.Score != 0 {
+		t.Errorf("Unexpected failed callback %+v with result %+v", envelope, result)
+	}
+
+	// A result of the wrong shape is reported along with the envelope
+	req = httptest.NewRequest(http.MethodPost, "/callback",
+		strings.NewReader(`{"message_id":"msg-3","item_id":"pr-125","attempt":1,"status":"completed","result":"seven"}`))
+	envelope, _, err = DecodeCallbackBody[review](req)
+	if err == nil || envelope == nil || envelope.MessageID != "msg-3" {
+		t.Errorf("Expected a result error with the envelope, got %+v, %v", envelope, err)
+	}
+}
+
+func TestDecodeCallbackInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":   `not json`,
+		"no message": `{"item_id":"pr-123","status":"completed"}`,
+		"too large":  `{"message_id":"msg-1","result":"` + strings.Repeat("a", MaxCallbackSize) + `"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+			if _, err := DecodeCallback(req); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestCallbackResultEnvelope(t *testing.T) {
+	result := CallbackResult{Body: []byte(`{"message_id":"msg-1","item_id":"pr-123","attempt":1,"status":"failed","error":"timeout"}`)}
+	envelope, err := result.Envelope()
+	if err != nil {
+		t.Fatalf("Envelope failed: %v", err)
+	}
+	if envelope.MessageID != "msg-1" || envelope.Error != "timeout" {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+}
@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// CallbackConfig sets the callback URL of messages submitted without one
+type CallbackConfig struct {
+	// URLTemplate is expanded into the callback URL of messages that have
+	// none once topic defaults apply, e.g.
+	// "https://ci.example.com/hooks/{topic}/{item_id}". The placeholders
+	// {topic}, {item_id}, {priority}, and {correlation_id} are replaced with
+	// the message's values, path-escaped
+	URLTemplate string
+	// Strict requires expanded callback URLs to be absolute HTTPS URLs;
+	// messages whose URL is not fail validation
+	Strict bool
+}
+
+// callbackPlaceholder matches the placeholders of a callback URL template
+var callbackPlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// callbackPlaceholders are the placeholders a template may use
+var callbackPlaceholders = map[string]bool{
+	"{topic}":          true,
+	"{item_id}":        true,
+	"{priority}":       true,
+	"{correlation_id}": true,
+}
+
+// validate checks that the template only uses known placeholders and
+// expands into a usable callback URL
+func (c *CallbackConfig) validate() error {
+	if c.URLTemplate == "" {
+		return fmt.Errorf("callback: url template is required")
+	}
+	for _, placeholder := range callbackPlaceholder.FindAllString(c.URLTemplate, -1) {
+		if !callbackPlaceholders[placeholder] {
+			return fmt.Errorf("callback: unknown placeholder %s in url template", placeholder)
+		}
+	}
+
+	sample := c.expand(&MessageRequest{
+		ItemID:        "item",
+		Priority:      PriorityMedium,
+		Topic:         TopicPullRequests,
+		CorrelationID: "correlation",
+	})
+	if err := c.check(sample); err != nil {
+		return fmt.Errorf("callback: url template %v", err)
+	}
+	return nil
+}
+
+// expand renders the template for req
+func (c *CallbackConfig) expand(req *MessageRequest) string {
+	return strings.NewReplacer(
+		"{topic}", url.PathEscape(string(req.Topic)),
+		"{item_id}", url.PathEscape(req.ItemID),
+		"{priority}", url.PathEscape(string(req.Priority)),
+		"{correlation_id}", url.PathEscape(req.CorrelationID),
+	).Replace(c.URLTemplate)
+}
+
+// check validates an expanded callback URL, requiring HTTPS in strict mode
+func (c *CallbackConfig) check(raw string) error {
+	if err := validateCallbackURL(raw); err != nil {
+		return err
+	}
+	if c.Strict {
+		u, _ := url.Parse(raw)
+		if !u.IsAbs() || u.Scheme != "https" {
+			return fmt.Errorf("must be an absolute https URL in strict mode")
+		}
+	}
+	return nil
+}
+
+// expandCallbackURL fills the callback URL of req from the client's
+// template when it has none. A URL that fails the template's checks is
+// reported when req is validated
+func (c *Client) expandCallbackURL(req *MessageRequest) {
+	if c.callbacks == nil || c.callbacks.URLTemplate == "" || req.CallbackURL != "" {
+		return
+	}
+	req.CallbackURL = c.callbacks.expand(req)
+	req.callbackErr = c.callbacks.check(req.CallbackURL)
+}
@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallbackURLTemplate(t *testing.T) {
+	var received []MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		w.Write([]byte(`{"id":"msg-1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:  server.URL,
+		Callback: &CallbackConfig{URLTemplate: "https://ci.example.com/hooks/{topic}/{item_id}?p={priority}&c={correlation_id}"},
+	})
+	client.SetTopicDefaults("deployments", TopicDefaults{CallbackURL: "https://deployer.example.com/callback"})
+
+	ctx := context.Background()
+	req := newMessageRequest("octo/repo#7", PriorityHigh, TopicPullRequests, "", nil)
+	req.CorrelationID = "c-1"
+	if _, err := client.PostMessage(ctx, req); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if _, err := client.PostHighPriorityMessage(ctx, "pr-2", "https://example.com/explicit", nil); err != nil {
+		t.Fatalf("PostHighPriorityMessage failed: %v", err)
+	}
+	if _, err := client.Topic("deployments").PostHighPriorityMessage(ctx, "deploy-1", "", nil); err != nil {
+		t.Fatalf("PostHighPriorityMessage failed: %v", err)
+	}
+
+	if want := "https://ci.example.com/hooks/pullrequests/octo%2Frepo%237?p=high&c=c-1"; received[0].CallbackURL != want {
+		t.Errorf("Expected callback URL '%s', got '%s'", want, received[0].CallbackURL)
+	}
+	if received[1].CallbackURL != "https://example.com/explicit" {
+		t.Errorf("Expected an explicit callback URL to be kept, got '%s'", received[1].CallbackURL)
+	}
+	if received[2].CallbackURL != "https://deployer.example.com/callback" {
+		t.Errorf("Expected topic defaults to take precedence, got '%s'", received[2].CallbackURL)
+	}
+	if req.CallbackURL != "" {
+		t.Error("Expected caller's request to be untouched")
+	}
+}
+
+func TestCallbackURLTemplateStrict(t *testing.T) {
+	client := NewClient(&Config{
+		BaseURL:  "http://localhost",
+		Callback: &CallbackConfig{URLTemplate: "http://ci.example.com/hooks/{item_id}", Strict: true},
+	})
+	_, err := client.PostMessage(context.Background(), newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "", nil))
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Violations[0].Field != "callback_url" {
+		t.Errorf("Expected a callback_url violation, got %v", err)
+	}
+}
+
+func TestCallbackConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		config CallbackConfig
+		valid  bool
+	}{
+		"valid":                {CallbackConfig{URLTemplate: "https://ci.example.com/hooks/{topic}/{item_id}", Strict: true}, true},
+		"http when not strict": {CallbackConfig{URLTemplate: "http://ci.example.com/hooks/{item_id}"}, true},
+		"http when strict":     {CallbackConfig{URLTemplate: "http://ci.example.com/hooks/{item_id}", Strict: true}, false},
+		"relative":             {CallbackConfig{URLTemplate: "/hooks/{item_id}"}, false},
+		"unknown placeholder":  {CallbackConfig{URLTemplate: "https://ci.example.com/hooks/{repo}"}, false},
+		"empty":                {CallbackConfig{}, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := &Config{BaseURL: "http://localhost", Callback: &tt.config}
+			if err := config.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
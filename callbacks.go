@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CallbackDelivery is a single attempt by the service to deliver a
+// message's callback
+type CallbackDelivery struct {
+	Attempt int `json:"attempt"`
+	// StatusCode is the callback endpoint's response status; zero when no
+	// response was received, e.g. on a connection error or timeout
+	StatusCode  int    `json:"status_code"`
+	LatencyMs   int64  `json:"latency_ms"`
+	AttemptedAt string `json:"attempted_at"`
+	Error       string `json:"error,omitempty"`
+	// Manual is true for attempts requested with RedeliverCallback
+	Manual bool `json:"manual,omitempty"`
+}
+
+// Delivered reports whether the callback endpoint accepted the attempt
+func (d CallbackDelivery) Delivered() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// Latency returns how long the attempt took
+func (d CallbackDelivery) Latency() time.Duration {
+	return time.Duration(d.LatencyMs) * time.Millisecond
+}
+
+// callbackDeliveriesResponse represents the response listing delivery attempts
+type callbackDeliveriesResponse struct {
+	Deliveries []CallbackDelivery `json:"deliveries"`
+}
+
+// GetCallbackDeliveries lists every attempt to deliver the callback of a
+// message, oldest first
+func (c *Client) GetCallbackDeliveries(ctx context.Context, messageID string) ([]CallbackDelivery, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/messages/"+url.PathEscape(messageID)+"/callbacks", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries callbackDeliveriesResponse
+	if err := c.parseResponse(resp, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries.Deliveries, nil
+}
+
+// RedeliverResponse represents the result of a redelivery request
+type RedeliverResponse struct {
+	Status    string `json:"status"`
+	MessageID string `json:"message_id"`
+	// Attempt is the number the scheduled delivery will be listed under
+	Attempt int `json:"attempt"`
+}
+
+// RedeliverCallback makes the service deliver the callback of a message
+// again, e.g. after the callback endpoint recovered from an outage. The
+// delivery is scheduled, not awaited; follow it with GetCallbackDeliveries
+func (c *Client) RedeliverCallback(ctx context.Context, messageID string) (*RedeliverResponse, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/"+url.PathEscape(messageID)+"/callbacks/redeliver", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var redeliverResp RedeliverResponse
+	if err := c.parseResponse(resp, &redeliverResp); err != nil {
+		return nil, err
+	}
+
+	return &redeliverResp, nil
+}
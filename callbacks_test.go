@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallbackDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/messages/msg-1/callbacks":
+			w.Write([]byte(`{"deliveries":[
+				{"attempt":1,"status_code":0,"latency_ms":10000,"attempted_at":"2024-01-01T10:00:00Z","error":"timeout"},
+				{"attempt":2,"status_code":503,"latency_ms":120,"attempted_at":"2024-01-01T10:05:00Z"},
+				{"attempt":3,"status_code":200,"latency_ms":85,"attempted_at":"2024-01-01T11:00:00Z","manual":true}
+			]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/messages/msg-1/callbacks/redeliver":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"status":"scheduled","message_id":"msg-1","attempt":4}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	deliveries, err := client.GetCallbackDeliveries(ctx, "msg-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("Expected 3 deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].Delivered() || deliveries[1].Delivered() || !deliveries[2].Delivered() {
+		t.Errorf("Unexpected delivery outcomes: %+v", deliveries)
+	}
+	if deliveries[0].Latency() != 10*time.Second || deliveries[0].Error != "timeout" || !deliveries[2].Manual {
+		t.Errorf("Unexpected delivery details: %+v", deliveries)
+	}
+
+	redelivery, err := client.RedeliverCallback(ctx, "msg-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if redelivery.Status != "scheduled" || redelivery.Attempt != 4 {
+		t.Errorf("Unexpected redelivery: %+v", redelivery)
+	}
+
+	if _, err := client.RedeliverCallback(ctx, ""); err == nil {
+		t.Error("Expected error for empty message id")
+	}
+}
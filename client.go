@@ -6,21 +6,170 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Client represents the messages-worker SDK client
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	timeout    time.Duration
+	baseURL              string
+	httpClient           *http.Client
+	streamClient         *http.Client
+	timeout              time.Duration
+	healthCheckTimeout   time.Duration
+	compressor           Compressor
+	compressionThreshold int
+	codec                Codec
+	codecRejected        atomic.Bool
+	hooks                []ResponseHook
+	maxResponseSize      int64
+	decoding             DecodingMode
+	logger               *slog.Logger
+	clock                Clock
+	payloadStore         PayloadStore
+	encrypter            PayloadEncrypter
+	itemIDGenerator      IDGenerator
+	itemIDPrefix         string
+	dedupWindow          time.Duration
+	dedupCache           *dedupCache
+	callbackConfig       *EphemeralCallbackConfig
+	callbacks            *CallbackConfig
+	userAgent            string
+	team                 string
+	costCenter           string
+	tenant               string
+	// derived is set on clients made by ForTenant, which share their
+	// parent's connections and telemetry
+	derived bool
+
+	topicDefaultsMu sync.RWMutex
+	topicDefaults   map[Topic]TopicDefaults
+
+	apiVersion      *apiVersionState
+	version         *serviceVersion
+	errorTranslator ErrorTranslator
+	telemetry       *telemetry
+	transport       messageTransport
+	backpressure    *backpressureState
+	statusCache     *statusCache
+
+	asyncWorkers   int
+	asyncQueueSize int
+	asyncMu        sync.Mutex
+	async          *asyncPool
+	closed         bool
+
+	shutdown shutdownRegistry
 }
 
 // Config holds configuration options for the client
 type Config struct {
 	BaseURL string
 	Timeout time.Duration
+	// HealthCheckTimeout bounds CheckHealth, IsHealthy, CheckReadiness, and
+	// CheckLiveness independently of Timeout, so that probes fail fast during
+	// an outage. Defaults to DefaultHealthCheckTimeout
+	HealthCheckTimeout time.Duration
+	// Compressor compresses request bodies when set; responses are
+	// decompressed with any registered compressor regardless
+	Compressor Compressor
+	// CompressionThreshold is the smallest marshaled request body, in bytes,
+	// that is compressed; smaller bodies are sent as is. Zero compresses
+	// every body
+	CompressionThreshold int
+	// PayloadStore, when set, receives ObjectBody content larger than
+	// MaxPayloadSize; the message then carries a *PayloadReference instead
+	PayloadStore PayloadStore
+	// PayloadEncrypter, when set, encrypts the ObjectBody of every message
+	// before it is submitted, or offloaded to the PayloadStore, and marks the
+	// message with EncryptionMetadataKey
+	PayloadEncrypter PayloadEncrypter
+	// ItemIDGenerator, when set, generates the ItemID of messages submitted
+	// without one, e.g. sdk.UUIDv7; the generated ID is returned in the
+	// response. ItemIDPrefix is prepended to generated IDs
+	ItemIDGenerator IDGenerator
+	ItemIDPrefix    string
+	// DedupWindow limits duplicate checks, including those of
+	// PostMessageIdempotent, to messages submitted within the window; zero
+	// checks every message the service retains
+	DedupWindow time.Duration
+	// DedupCache makes PostMessage skip resubmissions of recently submitted
+	// messages; nil disables it
+	DedupCache *DedupCacheConfig
+	// EphemeralCallback configures endpoints started by NewEphemeralCallback
+	EphemeralCallback *EphemeralCallbackConfig
+	// Callback expands a callback URL template for messages submitted
+	// without a callback URL, after topic defaults
+	Callback *CallbackConfig
+	// Codec encodes request and response bodies in place of JSON, e.g. for
+	// MessagePack or protobuf. It is negotiated through Content-Type and
+	// Accept and used with APIVersionV1 only; if the service rejects it with
+	// 415 the client falls back to JSON. Defaults to JSONCodec
+	Codec Codec
+	// ResponseHooks rewrite raw response bodies, in order, before they are unmarshaled
+	ResponseHooks []ResponseHook
+	// MaxResponseSize bounds the response bodies read, in bytes; larger ones
+	// fail with ErrResponseTooLarge. Defaults to DefaultMaxResponseSize
+	MaxResponseSize int64
+	// Decoding selects how responses that do not match the SDK types are
+	// handled; DecodeLenient by default, DecodeStrict fails on them
+	Decoding DecodingMode
+	// Logger receives SDK warnings, such as responses that do not match the
+	// SDK types in lenient mode; nil disables them
+	Logger *slog.Logger
+	// UserAgentSuffix is appended to DefaultUserAgent so that the service can
+	// tell callers apart; see WithUserAgentSuffix
+	UserAgentSuffix string
+	// Team and CostCenter tag every request for cost attribution; messages
+	// may override them individually
+	Team       string
+	CostCenter string
+	// Tenant scopes every request to a tenant of a multi-tenant service by
+	// sending it in TenantHeader; see ForTenant
+	Tenant string
+	// APIVersion selects the endpoint generation, APIVersionV1 by default.
+	// APIVersionDetect asks the service on first use
+	APIVersion APIVersion
+	// ServiceVersion pins the service version used to interpret error
+	// responses; when empty it is detected from response headers
+	ServiceVersion string
+	// ErrorTranslator overrides the version-based error translation for
+	// the responses it returns an *APIError for
+	ErrorTranslator ErrorTranslator
+	// DialTimeout bounds establishing a single connection
+	DialTimeout time.Duration
+	// DialFallbackDelay is how long to wait on the preferred address family
+	// of a dual-stack host before also trying the other one; a negative
+	// value disables the fallback
+	DialFallbackDelay time.Duration
+	// GRPC, when set, sends message submission and message events over the
+	// service's gRPC API; see WithGRPC
+	GRPC *GRPCConfig
+	// Signing enables HMAC signing of every request; its Secret is required
+	Signing *SigningConfig
+	// Recorder records API exchanges to a fixture file, or replays them
+	// without contacting the service; see NewRecorder
+	Recorder *Recorder
+	// Audit hands a copy of every request to a sink before it is sent
+	Audit *AuditConfig
+	// Telemetry opts in to anonymized SDK usage reporting; nil disables it
+	Telemetry *TelemetryConfig
+	// AsyncWorkers bounds concurrent PostMessageAsync submissions
+	AsyncWorkers int
+	// AsyncQueueSize is the number of async submissions that may wait for a worker
+	AsyncQueueSize int
+	// Backpressure sheds load when queues are already deep; nil submits
+	// regardless of queue depth
+	Backpressure *BackpressurePolicy
+	// StatusCache configures the cache behind GetWorkerStatusCached; nil
+	// uses DefaultStatusCacheTTL without stale-while-revalidate
+	StatusCache *StatusCacheConfig
+	// Clock drives retries, batching, polling, caches, and schedules;
+	// defaults to SystemClock. See sdktest.FakeClock for tests
+	Clock Clock
 }
 
 // DefaultConfig returns a default configuration
@@ -41,44 +190,319 @@ func NewClient(config *Config) *Client {
 		config.Timeout = 30 * time.Second
 	}
 
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = DefaultHealthCheckTimeout
+	}
+
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = DefaultDialTimeout
+	}
+
+	if config.DialFallbackDelay == 0 {
+		config.DialFallbackDelay = DefaultDialFallbackDelay
+	}
+
+	if config.APIVersion == "" {
+		config.APIVersion = APIVersionV1
+	}
+
+	if config.Decoding == "" {
+		config.Decoding = DecodeLenient
+	}
+
+	if config.Codec == nil {
+		config.Codec = JSONCodec{}
+	}
+
+	if config.MaxResponseSize <= 0 {
+		config.MaxResponseSize = DefaultMaxResponseSize
+	}
+
+	if config.AsyncWorkers <= 0 {
+		config.AsyncWorkers = DefaultAsyncWorkers
+	}
+
+	if config.AsyncQueueSize <= 0 {
+		config.AsyncQueueSize = DefaultAsyncQueueSize
+	}
+
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+
+	// Event streams stay open indefinitely, so their client has no overall
+	// timeout; the wait for the response headers is bounded instead
+	streamTransport := newTransport(config)
+	streamTransport.ResponseHeaderTimeout = config.Timeout
+
 	return &Client{
 		baseURL: config.BaseURL,
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: wrapTransport(config, newTransport(config)),
+		},
+		streamClient: &http.Client{
+			Transport: wrapTransport(config, streamTransport),
 		},
-		timeout: config.Timeout,
+		timeout:              config.Timeout,
+		healthCheckTimeout:   config.HealthCheckTimeout,
+		compressor:           config.Compressor,
+		compressionThreshold: config.CompressionThreshold,
+		codec:                config.Codec,
+		hooks:                config.ResponseHooks,
+		maxResponseSize:      config.MaxResponseSize,
+		decoding:             config.Decoding,
+		logger:               config.Logger,
+		clock:                config.Clock,
+		payloadStore:         config.PayloadStore,
+		encrypter:            config.PayloadEncrypter,
+		itemIDGenerator:      config.ItemIDGenerator,
+		itemIDPrefix:         config.ItemIDPrefix,
+		dedupWindow:          config.DedupWindow,
+		dedupCache:           newDedupCache(config.DedupCache, config.Clock),
+		callbackConfig:       config.EphemeralCallback,
+		callbacks:            config.Callback,
+		userAgent:            userAgent(config.UserAgentSuffix),
+		team:                 config.Team,
+		costCenter:           config.CostCenter,
+		tenant:               config.Tenant,
+
+		apiVersion:      &apiVersionState{configured: config.APIVersion},
+		version:         &serviceVersion{version: config.ServiceVersion, pinned: config.ServiceVersion != ""},
+		errorTranslator: config.ErrorTranslator,
+		telemetry:       newTelemetry(config.Telemetry, config.Clock),
+		transport:       newGRPCTransport(config.GRPC, userAgent(config.UserAgentSuffix)),
+		backpressure:    newBackpressure(config.Backpressure, config.Clock),
+		statusCache:     newStatusCache(config.StatusCache, config.Clock),
+
+		asyncWorkers:   config.AsyncWorkers,
+		asyncQueueSize: config.AsyncQueueSize,
 	}
 }
 
+// Validate reports configuration that would make every request fail
+func (c *Config) Validate() error {
+	switch c.Decoding {
+	case "", DecodeLenient, DecodeStrict:
+	default:
+		return fmt.Errorf("decoding mode must be '%s' or '%s', got '%s'", DecodeLenient, DecodeStrict, c.Decoding)
+	}
+	if c.Signing != nil {
+		if err := c.Signing.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Backpressure != nil {
+		if err := c.Backpressure.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Audit != nil {
+		if err := c.Audit.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Callback != nil {
+		if err := c.Callback.validate(); err != nil {
+			return err
+		}
+	}
+	if c.Tenant != "" {
+		if err := validateTenant(c.Tenant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewClientWithDefaults creates a new client with default configuration
 func NewClientWithDefaults() *Client {
 	return NewClient(DefaultConfig())
 }
 
+// Close waits for queued asynchronous submissions to finish and releases idle
+// connections held by the client; it is safe to call more than once
+func (c *Client) Close() error {
+	c.asyncMu.Lock()
+	c.closed = true
+	pool := c.async
+	c.asyncMu.Unlock()
+
+	if pool != nil {
+		pool.close()
+	}
+	if c.derived {
+		return nil
+	}
+
+	c.telemetry.close()
+	c.httpClient.CloseIdleConnections()
+	c.streamClient.CloseIdleConnections()
+	if c.transport != nil {
+		return c.transport.close()
+	}
+	return nil
+}
+
 // doRequest performs an HTTP request with the given method, path, and body
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	prepared, err := c.prepareRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer prepared.release()
+	return c.doPrepared(ctx, prepared)
+}
+
+// doPrepared sends a prepared request, falling back to an uncompressed and
+// then a JSON body when the service rejects the content-coding or codec
+func (c *Client) doPrepared(ctx context.Context, p *preparedRequest) (*http.Response, error) {
+	ctx = withFingerprint(ctx, p.fingerprint)
+	data, buf, contentType := p.data, p.buf, p.contentType
+
+	compressor := c.compressor
+	if len(data) < c.compressionThreshold {
+		compressor = nil
+	}
+
+	resp, err := c.send(ctx, p.method, p.path, data, buf, contentType, compressor)
+
+	// The server does not understand our content-coding, so fall back to
+	// sending the body uncompressed
+	if err == nil && resp.StatusCode == http.StatusUnsupportedMediaType && compressor != nil && data != nil {
+		resp.Body.Close()
+		resp, err = c.send(ctx, p.method, p.path, data, buf, contentType, nil)
+	}
+
+	// Nor our codec, so fall back to JSON for this and later requests
+	if err == nil && resp.StatusCode == http.StatusUnsupportedMediaType && contentType != ContentTypeJSON && data != nil {
+		resp.Body.Close()
+		c.codecRejected.Store(true)
+		data, contentType, buf, err = c.marshalBody(p.version, p.path, p.body)
+		if err == nil {
+			resp, err = c.send(ctx, p.method, p.path, data, buf, contentType, nil)
+			buf.release()
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	c.telemetry.record(p.method, p.path, resp, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// newRequest creates a request for path, mapped to the client's API version,
+// carrying the headers common to every call
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	version, err := c.resolveAPIVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+mapperFor(version).mapPath(path), body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.team != "" {
+		req.Header.Set("X-Team", c.team)
+	}
+	if c.costCenter != "" {
+		req.Header.Set("X-Cost-Center", c.costCenter)
+	}
+	if err := c.setTenantHeader(req); err != nil {
+		return nil, err
+	}
+	setMetadataHeaders(ctx, req.Header)
+	setIdempotencyHeader(ctx, req.Header)
+	setFingerprintHeader(ctx, req.Header)
+	setCallHeaders(ctx, req.Header)
+
+	return req, nil
+}
+
+// newBufferedRequest creates a request sending data, reading it from buf
+// when data is a slice of that pooled buffer
+func (c *Client) newBufferedRequest(ctx context.Context, method, path string, data []byte, buf *pooledBytes) (*http.Request, error) {
+	if data == nil {
+		return c.newRequest(ctx, method, path, nil)
+	}
+	if buf == nil {
+		return c.newRequest(ctx, method, path, bytes.NewReader(data))
 	}
 
+	body, err := buf.body(data)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return buf.body(data)
+	}
+	return req, nil
+}
+
+// send builds and executes a single HTTP request with a body of the given
+// content type, compressing it with compressor when it is not nil. buf is
+// the pooled buffer data was encoded into, if any
+func (c *Client) send(ctx context.Context, method, path string, data []byte, buf *pooledBytes, contentType string, compressor Compressor) (*http.Response, error) {
+	payload := data
+	if data != nil && compressor != nil {
+		compressed := getBuffer()
+		if err := compressTo(compressed, compressor, data); err != nil {
+			putBuffer(compressed)
+			return nil, err
+		}
+		payload, buf = compressed.Bytes(), newPooledBytes(compressed)
+		// The request body holds its own reference
+		defer buf.release()
+	}
+
+	req, err := c.newBufferedRequest(ctx, method, path, payload, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		req.Header.Set("Content-Type", contentType)
+		if compressor != nil {
+			req.Header.Set("Content-Encoding", compressor.Encoding())
+		}
+	}
+	req.Header.Set("Accept", c.accept())
+	req.Header.Set("Accept-Encoding", acceptEncoding())
+
+	return c.do(req)
+}
+
+// do executes req and decodes a compressed response body
+func (c *Client) do(req *http.Request) (*http.Response, error) {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	c.version.observe(resp)
+	c.recordResponseMeta(resp)
+
+	if resp.ContentLength != 0 {
+		decoded, ok, err := decodeResponseBody(resp.Body, resp.Header.Get("Content-Encoding"))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resp.Body = decoded
+			resp.Header.Del("Content-Encoding")
+			resp.ContentLength = -1
+		}
+	}
 
 	return resp, nil
 }
@@ -87,19 +511,48 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if err := checkTenant(resp); err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 400 && target != nil && c.streamsResponses() {
+		if c.isCodecResponse(resp) {
+			return c.decodeCodecResponse(resp, target)
+		}
+		return c.decodeResponseStream(resp, target)
+	}
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return err
+	}
+	if c.isCodecResponse(resp) {
+		body, err = c.transcodeToJSON(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resp.Request != nil {
+		body, err = mapperFor(c.APIVersion()).decodeResponse(resp.Request.URL.RequestURI(), body)
+		if err != nil {
+			return err
+		}
+	}
+
+	body, err = applyResponseHooks(c.hooks, resp, body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return err
 	}
 
 	if resp.StatusCode >= 400 {
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    string(body),
-		}
+		return c.translateError(resp, body)
 	}
 
 	if target != nil {
+		if err := c.checkResponseSchema(resp, body, target); err != nil {
+			return err
+		}
 		if err := json.Unmarshal(body, target); err != nil {
 			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
@@ -111,11 +564,29 @@ func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 // APIError represents an error returned by the API
 type APIError struct {
 	StatusCode int
-	Message    string
+	// Code is the service's machine-readable error code, when provided
+	Code    string
+	Message string
+	// Fingerprint identifies the request that failed; see RequestFingerprint
+	Fingerprint string
+	// Meta holds the response's request ID and rate limit
+	Meta ResponseMeta
+}
+
+// Is lets a 413 response match ErrPayloadTooLarge
+func (e *APIError) Is(target error) bool {
+	return target == ErrPayloadTooLarge && e.StatusCode == http.StatusRequestEntityTooLarge
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	msg := fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+	if e.Code != "" {
+		msg = fmt.Sprintf("API error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	if e.Meta.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.Meta.RequestID)
+	}
+	return msg
 }
 
 // IsAPIError checks if an error is an API error
@@ -123,4 +594,3 @@ func IsAPIError(err error) bool {
 	_, ok := err.(*APIError)
 	return ok
 }
-
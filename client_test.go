@@ -3,6 +3,8 @@ package sdk
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -430,3 +432,493 @@ func TestIsHealthy(t *testing.T) {
 		t.Error("Expected service to be unhealthy")
 	}
 }
+
+func TestPostBulkMessagesPartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := BulkMessageResponse{
+			Status: "partial",
+			Count:  1,
+			Messages: []MessageResponse{
+				{ID: "msg-1", Status: "published", ItemID: "test-1"},
+			},
+			Errors: []BulkMessageError{
+				{Index: 1, ItemID: "test-2", Code: "invalid_body", Reason: "object_body too large"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	req := &BulkMessageRequest{
+		Messages: []MessageRequest{
+			{ItemID: "test-1", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback1"},
+			{ItemID: "test-2", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback2"},
+		},
+	}
+
+	resp, err := client.PostBulkMessages(context.Background(), req)
+	if !IsBulkPartialError(err) {
+		t.Fatalf("Expected BulkPartialError, got %v", err)
+	}
+	if resp == nil {
+		t.Fatal("Expected response alongside partial error")
+	}
+	if len(resp.Succeeded()) != 1 || resp.Succeeded()[0].ItemID != "test-1" {
+		t.Errorf("Expected test-1 to succeed, got %+v", resp.Succeeded())
+	}
+	if len(resp.Failed()) != 1 || resp.Failed()[0].Index != 1 {
+		t.Errorf("Expected message at index 1 to fail, got %+v", resp.Failed())
+	}
+}
+
+func TestPostBulkMessagesPartialFailureRepeatedItemIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := BulkMessageResponse{
+			Status: "partial",
+			Count:  2,
+			Messages: []MessageResponse{
+				{ID: "msg-1", Status: "published", ItemID: "test-1"},
+				{ItemID: "test-1"},
+				{ID: "msg-3", Status: "published", ItemID: "test-3"},
+			},
+			Errors: []BulkMessageError{
+				{Index: 1, ItemID: "test-1", Code: "invalid_body", Reason: "object_body too large"},
+			},
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	resp, err := client.PostBulkMessages(context.Background(), &BulkMessageRequest{
+		Messages: []MessageRequest{
+			{ItemID: "test-1", CorrelationID: "first", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"},
+			{ItemID: "test-1", CorrelationID: "second", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"},
+			{ItemID: "test-3", CorrelationID: "third", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"},
+		},
+	})
+	if !IsBulkPartialError(err) {
+		t.Fatalf("Expected BulkPartialError, got %v", err)
+	}
+
+	// Failures are matched by index, so the accepted message sharing the
+	// rejected one's item ID still counts as succeeded
+	succeeded := resp.Succeeded()
+	if len(succeeded) != 2 || succeeded[0].ID != "msg-1" || succeeded[1].ID != "msg-3" {
+		t.Errorf("Expected messages 0 and 2 to succeed, got %+v", succeeded)
+	}
+	if resp.Messages[0].CorrelationID != "first" || resp.Messages[2].CorrelationID != "third" {
+		t.Errorf("Expected correlation IDs matched by index, got %+v", resp.Messages)
+	}
+}
+
+func TestWorkerStatusConsistency(t *testing.T) {
+	status := &WorkerStatusResponse{
+		TotalWorkers: 5,
+		LowPriority: PriorityWorkerInfo{
+			Count:   1,
+			Workers: []WorkerInfo{{ID: "low-1"}, {ID: "low-2"}},
+		},
+		HighPriority: PriorityWorkerInfo{
+			Count:   1,
+			Workers: []WorkerInfo{{ID: "high-1"}},
+		},
+		AllWorkers: []WorkerInfo{{ID: "low-1"}, {ID: "high-1"}},
+	}
+
+	if status.Consistent() {
+		t.Error("Expected drifted status to be inconsistent")
+	}
+	if problems := status.Inconsistencies(); len(problems) != 4 {
+		t.Errorf("Expected 4 inconsistencies, got %d: %v", len(problems), problems)
+	}
+
+	fixed := status.Reconcile()
+	if !fixed.Consistent() {
+		t.Errorf("Expected reconciled status to be consistent, got %v", fixed.Inconsistencies())
+	}
+	if fixed.TotalWorkers != 3 {
+		t.Errorf("Expected total workers 3, got %d", fixed.TotalWorkers)
+	}
+	if len(fixed.AllWorkers) != 3 {
+		t.Errorf("Expected 3 workers in all_workers, got %d", len(fixed.AllWorkers))
+	}
+	if status.TotalWorkers != 5 {
+		t.Error("Expected Reconcile to leave the original response untouched")
+	}
+}
+
+func TestCostAttributionTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Team") != "platform" {
+			t.Errorf("Expected X-Team 'platform', got '%s'", r.Header.Get("X-Team"))
+		}
+		if r.Header.Get("X-Cost-Center") != "cc-100" {
+			t.Errorf("Expected X-Cost-Center 'cc-100', got '%s'", r.Header.Get("X-Cost-Center"))
+		}
+
+		var req BulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Messages[0].Team != "platform" || req.Messages[0].CostCenter != "cc-100" {
+			t.Errorf("Expected client defaults on first message, got %+v", req.Messages[0])
+		}
+		if req.Messages[1].Team != "search" {
+			t.Errorf("Expected per-message team 'search', got '%s'", req.Messages[1].Team)
+		}
+		json.NewEncoder(w).Encode(BulkMessageResponse{Count: 2})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Team: "platform", CostCenter: "cc-100"})
+	req := &BulkMessageRequest{
+		Messages: []MessageRequest{
+			{ItemID: "pr-1", Priority: PriorityLow, Topic: TopicPullRequests, CallbackURL: "https://example.com/cb"},
+			{ItemID: "pr-2", Priority: PriorityLow, Topic: TopicPullRequests, CallbackURL: "https://example.com/cb", Team: "search"},
+		},
+	}
+
+	if _, err := client.PostBulkMessages(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if req.Messages[0].Team != "" {
+		t.Error("Expected caller's request to be left untouched")
+	}
+}
+
+func TestMessageRequestProcessingTimeout(t *testing.T) {
+	req := MessageRequest{
+		ItemID:            "pr-1",
+		Priority:          PriorityHigh,
+		Topic:             TopicPullRequests,
+		CallbackURL:       "https://example.com/callback",
+		ProcessingTimeout: 90 * time.Second,
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(string(data), `"processing_timeout_ms":90000`) {
+		t.Errorf("Expected processing_timeout_ms in %s", data)
+	}
+
+	var decoded MessageRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.ProcessingTimeout != 90*time.Second || decoded.ItemID != "pr-1" {
+		t.Errorf("Expected round trip to preserve fields, got %+v", decoded)
+	}
+
+	req.ProcessingTimeout = -time.Second
+	if err := req.Validate(); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for negative timeout, got %v", err)
+	}
+
+	detail := MessageDetail{Status: StatusTimedOut}
+	if !detail.TimedOut() || !detail.IsTerminal() {
+		t.Error("Expected timed_out to be a terminal timeout status")
+	}
+}
+
+func TestMessageRequestTTL(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if err := client.SetTopicDefaults(TopicPullRequests, TopicDefaults{TTL: time.Hour}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received["ttl_ms"] != float64(time.Hour.Milliseconds()) {
+		t.Errorf("Expected topic default ttl_ms, got %v", received["ttl_ms"])
+	}
+
+	req.TTL = 5 * time.Minute
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received["ttl_ms"] != float64(300000) {
+		t.Errorf("Expected message ttl_ms to win, got %v", received["ttl_ms"])
+	}
+
+	req.TTL = -time.Second
+	if err := req.Validate(); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for negative TTL, got %v", err)
+	}
+
+	detail := MessageDetail{Status: StatusExpired, Topic: TopicPullRequests}
+	if !detail.Expired() || !detail.IsTerminal() {
+		t.Error("Expected expired to be a terminal status")
+	}
+	event := MessageEvent{Type: MessageEventExpired}
+	if !event.Expired() || event.TimedOut() {
+		t.Error("Expected expired event to be reported as expired only")
+	}
+
+	throttle := NewCallbackThrottle(client, ThrottleOptions{})
+	throttle.ObserveMessage(&detail)
+	if _, samples := throttle.FailureRate(TopicPullRequests); samples != 0 {
+		t.Error("Expected expired messages not to count as callback failures")
+	}
+}
+
+func TestPostOrderedMessage(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", ItemID: received.ItemID})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+
+	if _, err := client.PostOrderedMessage(context.Background(), "octo/repo", req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received.GroupID != "octo/repo" {
+		t.Errorf("Expected group_id to be sent, got '%s'", received.GroupID)
+	}
+	if req.GroupID != "" {
+		t.Error("Expected caller's request to be left untouched")
+	}
+
+	if _, err := client.PostOrderedMessage(context.Background(), "", req); err == nil {
+		t.Error("Expected error for empty group id")
+	}
+	for _, groupID := range []string{" octo/repo", "octo\nrepo", strings.Repeat("g", MaxGroupIDLength+1)} {
+		if _, err := client.PostOrderedMessage(context.Background(), groupID, req); !IsValidationError(err) {
+			t.Errorf("Expected ValidationError for group %q, got %v", groupID, err)
+		}
+	}
+
+	// Grouped messages share a shard regardless of their item IDs
+	router, _ := NewShardRouter([]Shard{{Client: client}, {Client: client}, {Client: client}}, nil)
+	first, _ := router.Route(&MessageRequest{ItemID: "pr-1", GroupID: "octo/repo"})
+	for i := 2; i < 20; i++ {
+		if index, _ := router.Route(&MessageRequest{ItemID: fmt.Sprintf("pr-%d", i), GroupID: "octo/repo"}); index != first {
+			t.Fatalf("Expected grouped messages on shard %d, got %d", first, index)
+		}
+	}
+}
+
+func TestPauseAndResumeWorkers(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected method 'POST', got '%s'", r.Method)
+		}
+		paths = append(paths, r.URL.Path)
+		json.NewEncoder(w).Encode(PauseWorkersResponse{Status: "success", Affected: 2, Paused: strings.Contains(r.URL.Path, "pause")})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	resp, err := client.PauseWorkers(ctx, "high")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !resp.Paused || resp.Affected != 2 {
+		t.Errorf("Unexpected pause response: %+v", resp)
+	}
+
+	if _, err := client.ResumeWorkers(ctx, "high"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.PauseWorker(ctx, "high-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"/api/v1/workers/pause/high", "/api/v1/workers/resume/high", "/api/v1/workers/high-1/pause"}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected paths %v, got %v", expected, paths)
+	}
+
+	if _, err := client.PauseWorkers(ctx, "urgent"); err == nil {
+		t.Error("Expected error for invalid priority, got nil")
+	}
+}
+
+func TestPerWorkerManagement(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/workers/high-1":
+			json.NewEncoder(w).Encode(WorkerDetail{
+				WorkerInfo:     WorkerInfo{ID: "high-1", Status: "running"},
+				Processed:      42,
+				Failed:         3,
+				CurrentMessage: "msg-7",
+				LastError:      "callback returned 500",
+				UptimeMs:       90000,
+			})
+		case r.Method == "POST" && r.URL.Path == "/api/v1/workers/high-1/restart":
+			json.NewEncoder(w).Encode(WorkerDetail{WorkerInfo: WorkerInfo{ID: "high-9", Status: "running"}})
+		case r.Method == "DELETE" && r.URL.Path == "/api/v1/workers/high-1":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	detail, err := client.GetWorker(ctx, "high-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if detail.Processed != 42 || detail.Failed != 3 || detail.CurrentMessage != "msg-7" {
+		t.Errorf("Unexpected worker detail: %+v", detail)
+	}
+	if detail.Uptime() != 90*time.Second {
+		t.Errorf("Expected uptime 90s, got %v", detail.Uptime())
+	}
+
+	restarted, err := client.RestartWorker(ctx, "high-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if restarted.ID != "high-9" {
+		t.Errorf("Expected restarted worker 'high-9', got '%s'", restarted.ID)
+	}
+
+	if err := client.RemoveWorker(ctx, "high-1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.RemoveWorker(ctx, "gone"); err != nil {
+		t.Errorf("Expected removing a missing worker to succeed, got %v", err)
+	}
+
+	if _, err := client.GetWorker(ctx, ""); err == nil {
+		t.Error("Expected error for empty worker id, got nil")
+	}
+	if len(calls) != 4 {
+		t.Errorf("Expected 4 calls, got %v", calls)
+	}
+}
+
+func TestGetServiceInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			t.Errorf("Expected path '/info', got '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`{"version":"2.3.1","git_sha":"abc123","uptime_ms":3600000,"api_versions":["v1","v2"],"queue_backend":{"type":"rabbitmq","connected":true,"status":"ok"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	info, err := client.GetServiceInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if info.Version != "2.3.1" || info.GitSHA != "abc123" || info.Uptime() != time.Hour {
+		t.Errorf("Unexpected service info: %+v", info)
+	}
+	if !info.QueueBackend.Connected || info.QueueBackend.Type != "rabbitmq" {
+		t.Errorf("Unexpected queue backend: %+v", info.QueueBackend)
+	}
+	if !info.SupportsAPIVersion(APIVersionV2) {
+		t.Error("Expected v2 to be supported")
+	}
+}
+
+func TestCheckReadiness(t *testing.T) {
+	ready := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health/ready":
+			if ready {
+				w.Write([]byte(`{"status":"ok","components":[{"name":"broker","status":"ok"},{"name":"database","status":"ok"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":"degraded","components":[{"name":"broker","status":"ok"},{"name":"callback_dispatcher","status":"degraded","message":"queue backlog"}]}`))
+		case "/health/live":
+			w.Write([]byte("OK"))
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	report, err := client.CheckReadiness(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report.Ready() || len(report.Degraded()) != 0 {
+		t.Errorf("Expected ready report, got %+v", report)
+	}
+
+	ready = false
+	report, err = client.CheckReadiness(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Ready() {
+		t.Error("Expected report not to be ready")
+	}
+	degraded := report.Degraded()
+	if len(degraded) != 1 || degraded[0].Name != "callback_dispatcher" || degraded[0].Message != "queue backlog" {
+		t.Errorf("Unexpected degraded components: %+v", degraded)
+	}
+	if broker, ok := report.Component("broker"); !ok || !broker.Healthy() {
+		t.Errorf("Expected healthy broker, got %+v", broker)
+	}
+
+	live, err := client.CheckLiveness(ctx)
+	if err != nil || live.Status != "OK" {
+		t.Errorf("Expected live service, got %+v, %v", live, err)
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 30 * time.Second, HealthCheckTimeout: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	start := time.Now()
+	if client.IsHealthy(ctx) {
+		t.Error("Expected hanging service to be unhealthy")
+	}
+	if _, err := client.CheckReadiness(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected health checks to use their own timeout, took %v", elapsed)
+	}
+
+	if defaulted := NewClient(&Config{BaseURL: server.URL}); defaulted.healthCheckTimeout != DefaultHealthCheckTimeout {
+		t.Errorf("Expected default health check timeout, got %v", defaulted.healthCheckTimeout)
+	}
+}
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for the client's time-based features:
+// retries and backoff, batching intervals, polling, caches, throttles, and
+// schedules. The default is SystemClock; tests can substitute a fake clock,
+// such as sdktest.FakeClock, to advance time deterministically. Context
+// deadlines always follow real time
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event created by a Clock, like *time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, reporting whether it was active
+	Stop() bool
+	// Reset changes the timer to fire after d, reporting whether it was
+	// active
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, like *time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// SystemClock is the Clock backed by the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
+
+type systemTicker struct{ *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// sleep waits for d on clock, returning ctx's error if ctx ends first
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
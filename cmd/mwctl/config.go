@@ -0,0 +1,13 @@
+package main
+
+import sdk "github.com/ericbrisrubio/messages-worker-sdk"
+
+// loadConfig reads a config file with sdk.LoadConfig and returns the client
+// configuration of profile, or of the top-level settings when profile is empty
+func loadConfig(path, profile string) (*sdk.Config, error) {
+	file, err := sdk.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Config(profile)
+}
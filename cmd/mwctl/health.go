@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+)
+
+// health checks the service's health, optionally waiting for it to become
+// healthy, e.g. in a deploy script
+func health(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("health", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cf clientFlags
+	cf.register(flags)
+	wait := flags.Bool("wait", false, "retry until the service is healthy or -timeout elapses")
+	interval := flags.Duration("interval", 2*time.Second, "delay between checks with -wait")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(stderr, "health: -interval must be positive")
+		return exitUsage
+	}
+
+	client, code, ok := cf.client(stderr)
+	if !ok {
+		return code
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cf.timeout)
+	defer cancel()
+
+	resp, err := client.CheckHealth(ctx)
+	for err != nil && *wait {
+		select {
+		case <-ctx.Done():
+			return failed(stderr, fmt.Errorf("service not healthy after %s: %w", cf.timeout, err))
+		case <-time.After(*interval):
+		}
+		resp, err = client.CheckHealth(ctx)
+	}
+	if err != nil {
+		return failed(stderr, err)
+	}
+
+	cf.print(stdout, resp, func(w io.Writer) {
+		fmt.Fprintln(w, "STATUS")
+		fmt.Fprintln(w, resp.Status)
+	})
+	return exitOK
+}
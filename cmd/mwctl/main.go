@@ -0,0 +1,70 @@
+// Command mwctl operates a messages-worker service through the SDK
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes, stable for use in CI scripts
+const (
+	exitOK          = 0
+	exitFailed      = 1
+	exitUsage       = 2
+	exitConfig      = 3
+	exitUnavailable = 4
+)
+
+const usage = `Usage: mwctl <command> [flags]
+
+Commands:
+  post              Submit a single message
+  bulk              Submit the messages of a JSON file
+  workers status    Show worker counts and queue depths per priority
+  workers scale     Add or remove workers, e.g. workers scale high +2
+  health            Check service health; -wait retries until healthy
+  config validate   Load a config file and check it against its environment
+
+Commands that call the service accept -url, or -config and -profile, to
+select it and -o json for machine-readable output. Run a command with -h
+for its flags.
+
+Exit codes: 0 success, 1 rejected by the service, 2 usage or invalid input,
+3 invalid config file, 4 service unreachable.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command given by args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "post":
+		return post(args[1:], stdout, stderr)
+	case "bulk":
+		return bulk(args[1:], stdout, stderr)
+	case "workers":
+		return workers(args[1:], stdout, stderr)
+	case "health":
+		return health(args[1:], stdout, stderr)
+	case "config":
+		if len(args) < 2 || args[1] != "validate" {
+			fmt.Fprint(stderr, usage)
+			return exitUsage
+		}
+		return configValidate(args[2:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command '%s'\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sdk.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MW_SECRET", "s3cret")
+	path := writeConfig(t, `
+base_url: http://localhost:8083
+timeout: 10s
+team: platform
+profiles:
+  prod:
+    base_url: https://messages-worker.example.com
+    api_version: v2
+    signing:
+      key_id: key-1
+      secret: ${MW_SECRET}
+`)
+
+	config, err := loadConfig(path, "prod")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.BaseURL != "https://messages-worker.example.com" || config.Team != "platform" || config.Timeout.Seconds() != 10 {
+		t.Errorf("Expected profile merged over top-level settings, got %+v", config)
+	}
+	if config.APIVersion != sdk.APIVersionV2 || string(config.Signing.Secret) != "s3cret" {
+		t.Errorf("Expected signing secret from environment, got %+v", config.Signing)
+	}
+
+	if _, err := loadConfig(path, "staging"); err == nil || !strings.Contains(err.Error(), "available: prod") {
+		t.Errorf("Expected missing profile error, got %v", err)
+	}
+
+	bad := writeConfig(t, "base_url: localhost\napi_version: v3\nsigning:\n  key_id: key-1\n")
+	_, err = loadConfig(bad, "")
+	for _, want := range []string{"base_url", "api_version", "secret"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error mentioning %s, got %v", want, err)
+		}
+	}
+
+	unknown := writeConfig(t, "base_url: http://localhost\nbaseurl: typo\n")
+	if _, err := loadConfig(unknown, ""); err == nil || !strings.Contains(err.Error(), "baseurl") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte("OK"))
+		case "/api/v1/workers/status":
+			w.WriteHeader(http.StatusForbidden)
+		case "/info":
+			json.NewEncoder(w).Encode(sdk.ServiceInfo{Version: "2.4.0", APIVersions: []sdk.APIVersion{sdk.APIVersionV1}})
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"config", "validate", "-f", writeConfig(t, "base_url: "+server.URL+"\n")}, &stdout, &stderr)
+	if code != exitFailed {
+		t.Errorf("Expected exit code %d, got %d", exitFailed, code)
+	}
+	out := stdout.String()
+	for _, want := range []string{"PASS config", "PASS connectivity", "FAIL credentials", "hint:", "PASS api_version"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", want, out)
+		}
+	}
+
+	if code := run([]string{"config", "validate"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("Expected usage exit code, got %d", code)
+	}
+}
+
+func TestPostAndBulk(t *testing.T) {
+	var posted sdk.MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/messages":
+			json.NewDecoder(r.Body).Decode(&posted)
+			json.NewEncoder(w).Encode(sdk.MessageResponse{ID: "msg-1", ItemID: posted.ItemID, Status: "queued"})
+		case "/api/v1/messages/bulk":
+			var req sdk.BulkMessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusMultiStatus)
+			json.NewEncoder(w).Encode(sdk.BulkMessageResponse{
+				Messages: []sdk.MessageResponse{{ID: "msg-2", ItemID: req.Messages[0].ItemID, Status: "queued"}},
+				Errors:   []sdk.BulkMessageError{{Index: 1, ItemID: req.Messages[1].ItemID, Reason: "unknown repository"}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"post", "-url", server.URL, "-o", "json", "-item", "pr-1", "-priority", "high",
+		"-callback", "https://example.com/callback", "-body", `{"number":7}`, "-group", "octo/repo"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	var resp sdk.MessageResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil || resp.ID != "msg-1" {
+		t.Errorf("Expected JSON output, got %s", stdout.String())
+	}
+	if posted.Priority != sdk.PriorityHigh || posted.GroupID != "octo/repo" || posted.ObjectBody == nil {
+		t.Errorf("Expected flags to be submitted, got %+v", posted)
+	}
+
+	if code := run([]string{"post", "-url", server.URL}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("Expected usage exit code for a message without a callback, got %d", code)
+	}
+
+	path := filepath.Join(t.TempDir(), "messages.json")
+	os.WriteFile(path, []byte(`[
+		{"item_id": "pr-2", "priority": "low", "topic": "pullrequests", "callback_url": "https://example.com/callback"},
+		{"item_id": "pr-3", "priority": "low", "topic": "pullrequests", "callback_url": "https://example.com/callback"}
+	]`), 0o600)
+	stdout.Reset()
+	code = run([]string{"bulk", "-url", server.URL, "-f", path}, &stdout, &stderr)
+	if code != exitFailed {
+		t.Errorf("Expected exit code %d for a partial failure, got %d", exitFailed, code)
+	}
+	if out := stdout.String(); !strings.Contains(out, "msg-2") || !strings.Contains(out, "rejected: unknown repository") {
+		t.Errorf("Expected accepted and rejected rows, got:\n%s", out)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dry_run") != "true" {
+			t.Errorf("Expected a dry run, got %s", r.URL)
+		}
+		switch r.URL.Path {
+		case "/api/v1/messages":
+			w.Write([]byte(`{"item_id":"pr-1","valid":true,"queue":"pullrequests-high","priority":"high"}`))
+		case "/api/v1/messages/bulk":
+			w.Write([]byte(`{"valid":false,"results":[
+				{"item_id":"pr-2","valid":false,"violations":[{"field":"callback_url","message":"host is not allowed"}]}
+			]}`))
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"post", "-url", server.URL, "-dry-run", "-item", "pr-1", "-priority", "high",
+		"-callback", "https://example.com/callback"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "pullrequests-high") {
+		t.Errorf("Expected the routing to be printed, got:\n%s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "messages.json")
+	os.WriteFile(path, []byte(`[{"item_id": "pr-2", "priority": "low", "topic": "pullrequests", "callback_url": "https://example.com/callback"}]`), 0o600)
+	stdout.Reset()
+	code = run([]string{"bulk", "-url", server.URL, "-dry-run", "-f", path}, &stdout, &stderr)
+	if code != exitFailed {
+		t.Errorf("Expected exit code %d for an invalid message, got %d", exitFailed, code)
+	}
+	if out := stdout.String(); !strings.Contains(out, "callback_url: host is not allowed") {
+		t.Errorf("Expected the violation to be printed, got:\n%s", out)
+	}
+}
+
+func TestWorkersAndHealth(t *testing.T) {
+	var scaled string
+	healthChecks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/workers/status":
+			json.NewEncoder(w).Encode(sdk.WorkerStatusResponse{
+				TotalWorkers: 3,
+				HighPriority: sdk.PriorityWorkerInfo{Count: 2, QueueDepth: 40},
+				LowPriority:  sdk.PriorityWorkerInfo{Count: 1},
+			})
+		case "/api/v1/workers/scale/high":
+			scaled = r.URL.RawQuery
+			json.NewEncoder(w).Encode(sdk.ScaleWorkersResponse{Status: "success", Priority: "high", Count: 2, Action: "added"})
+		case "/health":
+			healthChecks++
+			if healthChecks < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("OK"))
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"workers", "status", "-url", server.URL}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "high      2        40") || !strings.Contains(out, "total     3") {
+		t.Errorf("Expected status table, got:\n%s", out)
+	}
+
+	if code := run([]string{"workers", "scale", "-url", server.URL, "high", "+2"}, &stdout, &stderr); code != exitOK {
+		t.Errorf("Expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+	if !strings.Contains(scaled, "2") {
+		t.Errorf("Expected scale request for 2 workers, got query '%s'", scaled)
+	}
+	if code := run([]string{"workers", "scale", "high", "lots"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("Expected usage exit code, got %d", code)
+	}
+
+	if code := run([]string{"health", "-url", server.URL}, &stdout, &stderr); code != exitFailed {
+		t.Errorf("Expected exit code %d for an unhealthy service, got %d", exitFailed, code)
+	}
+	if code := run([]string{"health", "-url", server.URL, "-wait", "-interval", "1ms"}, &stdout, &stderr); code != exitOK {
+		t.Errorf("Expected -wait to retry until healthy, got %d: %s", code, stderr.String())
+	}
+
+	if code := run([]string{"health", "-url", "http://127.0.0.1:1"}, &stdout, &stderr); code != exitUnavailable {
+		t.Errorf("Expected exit code %d for an unreachable service, got %d", exitUnavailable, code)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// post submits a single message built from flags
+func post(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("post", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cf clientFlags
+	cf.register(flags)
+	itemID := flags.String("item", "", "item ID; generated when empty")
+	priority := flags.String("priority", string(sdk.PriorityMedium), "priority: low, medium, or high")
+	topic := flags.String("topic", string(sdk.TopicPullRequests), "topic")
+	callback := flags.String("callback", "", "callback URL")
+	body := flags.String("body", "", "object body as JSON")
+	bodyFile := flags.String("body-file", "", "file holding the object body as JSON; - reads stdin")
+	group := flags.String("group", "", "ordering group")
+	ttl := flags.Duration("ttl", 0, "time to live; zero never expires")
+	dryRun := flags.Bool("dry-run", false, "validate against the service and show the routing without enqueueing")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *body != "" && *bodyFile != "" {
+		fmt.Fprintln(stderr, "post: -body and -body-file are mutually exclusive")
+		return exitUsage
+	}
+
+	req := &sdk.MessageRequest{
+		ItemID:      *itemID,
+		Priority:    sdk.Priority(*priority),
+		Topic:       sdk.Topic(*topic),
+		CallbackURL: *callback,
+		GroupID:     *group,
+		TTL:         *ttl,
+	}
+	if req.ItemID == "" {
+		req.ItemID = sdk.GenerateItemID("mwctl")
+	}
+
+	raw := []byte(*body)
+	if *bodyFile != "" {
+		var err error
+		if raw, err = readInput(*bodyFile); err != nil {
+			fmt.Fprintf(stderr, "post: %v\n", err)
+			return exitUsage
+		}
+	}
+	if len(raw) > 0 {
+		if !json.Valid(raw) {
+			fmt.Fprintln(stderr, "post: object body is not valid JSON")
+			return exitUsage
+		}
+		req.ObjectBody = json.RawMessage(raw)
+	}
+
+	client, code, ok := cf.client(stderr)
+	if !ok {
+		return code
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cf.timeout)
+	defer cancel()
+
+	if *dryRun {
+		result, err := client.DryRunMessage(ctx, req)
+		if err != nil {
+			return failed(stderr, err)
+		}
+		cf.print(stdout, result, func(w io.Writer) {
+			printDryRun(w, []sdk.DryRunResult{*result})
+		})
+		if !result.Valid {
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	resp, err := client.PostMessage(ctx, req)
+	if err != nil {
+		return failed(stderr, err)
+	}
+
+	cf.print(stdout, resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tITEM\tSTATUS")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", resp.ID, resp.ItemID, resp.Status)
+	})
+	return exitOK
+}
+
+// bulk submits the messages of a JSON file in a single bulk request
+func bulk(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bulk", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cf clientFlags
+	cf.register(flags)
+	path := flags.String("f", "", "JSON file of messages, either an array or {\"messages\": [...]}; - reads stdin")
+	atomic := flags.Bool("atomic", false, "enqueue all messages or none")
+	dryRun := flags.Bool("dry-run", false, "validate against the service and show the routing without enqueueing")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(stderr, "bulk: -f is required")
+		return exitUsage
+	}
+
+	data, err := readInput(*path)
+	if err != nil {
+		fmt.Fprintf(stderr, "bulk: %v\n", err)
+		return exitUsage
+	}
+	req, err := parseBulkFile(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "bulk: %s: %v\n", *path, err)
+		return exitUsage
+	}
+
+	client, code, ok := cf.client(stderr)
+	if !ok {
+		return code
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cf.timeout)
+	defer cancel()
+
+	if *dryRun {
+		result, err := client.DryRunBulkMessages(ctx, req)
+		if err != nil {
+			return failed(stderr, err)
+		}
+		cf.print(stdout, result, func(w io.Writer) {
+			printDryRun(w, result.Results)
+		})
+		if !result.Valid {
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	submit := client.PostBulkMessages
+	if *atomic {
+		submit = client.PostBulkMessagesAtomic
+	}
+	resp, err := submit(ctx, req)
+	if resp == nil {
+		return failed(stderr, err)
+	}
+
+	// Partial failures still print the accepted messages
+	cf.print(stdout, resp, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tITEM\tSTATUS")
+		for _, m := range resp.Succeeded() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", m.ID, m.ItemID, m.Status)
+		}
+		for _, e := range resp.Failed() {
+			fmt.Fprintf(w, "-\t%s\trejected: %s\n", e.ItemID, e.Reason)
+		}
+	})
+	if err != nil {
+		return failed(stderr, err)
+	}
+	return exitOK
+}
+
+// printDryRun writes a row per dry-run result, followed by its violations
+func printDryRun(w io.Writer, results []sdk.DryRunResult) {
+	fmt.Fprintln(w, "ITEM\tVALID\tQUEUE\tPRIORITY")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", r.ItemID, r.Valid, r.Queue, r.Priority)
+		for _, v := range r.Violations {
+			fmt.Fprintf(w, "\t\t%s: %s\n", v.Field, v.Message)
+		}
+	}
+}
+
+// parseBulkFile accepts a JSON array of messages or a bulk request object
+func parseBulkFile(data []byte) (*sdk.BulkMessageRequest, error) {
+	var req sdk.BulkMessageRequest
+	if err := json.Unmarshal(data, &req.Messages); err == nil {
+		return &req, nil
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("expected an array of messages or {\"messages\": [...]}: %w", err)
+	}
+	return &req, nil
+}
+
+// readInput reads a file, or stdin when path is -
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// configValidate loads a config file and runs the SDK self-check against the
+// environment it points at, printing a diagnostic per check
+func configValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	path := flags.String("f", "", "config file (YAML or JSON)")
+	profile := flags.String("profile", "", "profile to validate; defaults to the top-level settings")
+	timeout := flags.Duration("timeout", 30*time.Second, "bound on the whole validation")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *path == "" {
+		fmt.Fprintln(stderr, "config validate: -f is required")
+		return exitUsage
+	}
+
+	config, err := loadConfig(*path, *profile)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL config: %v\n", err)
+		return exitConfig
+	}
+	fmt.Fprintf(stdout, "PASS config: %s loaded\n", *path)
+
+	client := sdk.NewClient(config)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report := client.SelfCheck(ctx)
+	for _, check := range report.Checks {
+		fmt.Fprintf(stdout, "%s %s: %s\n", checkLabel(check.Status), check.Name, check.Detail)
+		if check.Hint != "" {
+			fmt.Fprintf(stdout, "     hint: %s\n", check.Hint)
+		}
+	}
+
+	if !report.OK() {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// checkLabel returns the fixed-width label printed for a check status
+func checkLabel(status string) string {
+	switch status {
+	case sdk.CheckPassed:
+		return "PASS"
+	case sdk.CheckFailed:
+		return "FAIL"
+	case sdk.CheckSkipped:
+		return "SKIP"
+	default:
+		return strings.ToUpper(status)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+const workersUsage = `Usage: mwctl workers <command> [flags]
+
+Commands:
+  status                    Show worker counts and queue depths per priority
+  scale <priority> <+n|-n>  Add or remove workers of a priority
+`
+
+// workers dispatches the workers subcommands
+func workers(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, workersUsage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "status":
+		return workersStatus(args[1:], stdout, stderr)
+	case "scale":
+		return workersScale(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "unknown workers command '%s'\n\n%s", args[0], workersUsage)
+		return exitUsage
+	}
+}
+
+// workersStatus prints worker counts and queue depths per priority
+func workersStatus(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("workers status", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cf clientFlags
+	cf.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	client, code, ok := cf.client(stderr)
+	if !ok {
+		return code
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cf.timeout)
+	defer cancel()
+
+	status, err := client.GetWorkerStatus(ctx)
+	if err != nil {
+		return failed(stderr, err)
+	}
+
+	cf.print(stdout, status, func(w io.Writer) {
+		fmt.Fprintln(w, "PRIORITY\tWORKERS\tQUEUE DEPTH")
+		for _, row := range []struct {
+			name string
+			info sdk.PriorityWorkerInfo
+		}{
+			{"high", status.HighPriority},
+			{"medium", status.MediumPriority},
+			{"low", status.LowPriority},
+		} {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", row.name, row.info.Count, row.info.QueueDepth)
+		}
+		fmt.Fprintf(w, "total\t%d\t\n", status.TotalWorkers)
+	})
+	return exitOK
+}
+
+// workersScale adds or removes workers, e.g. "scale high +2"
+func workersScale(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("workers scale", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cf clientFlags
+	cf.register(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprint(stderr, workersUsage)
+		return exitUsage
+	}
+
+	priority := flags.Arg(0)
+	count, err := strconv.Atoi(flags.Arg(1))
+	if err != nil || count == 0 {
+		fmt.Fprintf(stderr, "workers scale: count must be a non-zero integer such as +2 or -1, got '%s'\n", flags.Arg(1))
+		return exitUsage
+	}
+
+	client, code, ok := cf.client(stderr)
+	if !ok {
+		return code
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cf.timeout)
+	defer cancel()
+
+	resp, err := client.ScaleWorkers(ctx, priority, count)
+	if err != nil {
+		return failed(stderr, err)
+	}
+
+	cf.print(stdout, resp, func(w io.Writer) {
+		fmt.Fprintln(w, "PRIORITY\tACTION\tCOUNT\tMESSAGE")
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", resp.Priority, resp.Action, resp.Count, resp.Message)
+	})
+	return exitOK
+}
@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// ContentTypeJSON is the media type of JSON bodies, the default wire encoding
+const ContentTypeJSON = "application/json"
+
+// Codec serializes request and response bodies in a wire encoding other
+// than JSON, such as MessagePack or protobuf. Codecs see the SDK's request
+// and response types, so they must honor the same field names as their json
+// tags
+type Codec interface {
+	// ContentType returns the media type sent in Content-Type and Accept,
+	// e.g. "application/msgpack"
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec implements Codec with encoding/json
+type JSONCodec struct{}
+
+// ContentType returns "application/json"
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+// Marshal encodes v as JSON
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON data into v
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// usesCodec reports whether bodies exchanged with version are encoded with
+// the configured codec. The v2 mapping rewrites JSON, so it always uses
+// JSON, as does a client whose codec the service has rejected
+func (c *Client) usesCodec(version APIVersion) bool {
+	return c.codec.ContentType() != ContentTypeJSON && version == APIVersionV1 && !c.codecRejected.Load()
+}
+
+// marshalBody encodes a request body for path and returns it with its
+// content type. JSON bodies are encoded into a pooled buffer, which is
+// returned as well; the caller must release it once the body has been sent
+func (c *Client) marshalBody(version APIVersion, path string, body interface{}) ([]byte, string, *pooledBytes, error) {
+	if c.usesCodec(version) {
+		// Codecs see the same wire form as JSON, since fields such as
+		// durations are encoded by MarshalJSON, which other codecs skip
+		data, err := c.codec.Marshal(wireForm(body))
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		return data, c.codec.ContentType(), nil, nil
+	}
+
+	buf := getBuffer()
+	if err := json.NewEncoder(buf).Encode(wireForm(body)); err != nil {
+		putBuffer(buf)
+		return nil, "", nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	// The mapper may return the encoded body as is, so the buffer is kept
+	// either way
+	data, err := mapperFor(version).encodeRequest(path, bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	if err != nil {
+		putBuffer(buf)
+		return nil, "", nil, err
+	}
+	return data, ContentTypeJSON, newPooledBytes(buf), nil
+}
+
+// accept returns the Accept header of API requests. The codec is offered
+// only when responses can be decoded without the raw JSON
+func (c *Client) accept() string {
+	if c.usesCodec(c.APIVersion()) && c.streamsResponses() {
+		return c.codec.ContentType() + ", " + ContentTypeJSON + ";q=0.9"
+	}
+	return ContentTypeJSON
+}
+
+// isCodecResponse reports whether resp is encoded with the configured codec
+func (c *Client) isCodecResponse(resp *http.Response) bool {
+	if c.codec.ContentType() == ContentTypeJSON {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == c.codec.ContentType()
+}
+
+// decodeCodecResponse decodes a successful codec response into target
+func (c *Client) decodeCodecResponse(resp *http.Response, target interface{}) error {
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return err
+	}
+	if err := c.codec.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// transcodeToJSON re-encodes a codec body as JSON, for the code paths that
+// work on raw JSON such as error translation
+func (c *Client) transcodeToJSON(body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+
+	var value interface{}
+	if err := c.codec.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", c.codec.ContentType(), err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", c.codec.ContentType(), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,211 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// framedCodec is a stand-in binary codec: JSON behind a fixed prefix
+type framedCodec struct{}
+
+const framedContentType = "application/x-framed"
+
+var framedPrefix = []byte("FRAMED:")
+
+func (framedCodec) ContentType() string { return framedContentType }
+
+func (framedCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return append(append([]byte(nil), framedPrefix...), data...), err
+}
+
+func (framedCodec) Unmarshal(data []byte, v interface{}) error {
+	if !bytes.HasPrefix(data, framedPrefix) {
+		return fmt.Errorf("missing frame")
+	}
+	return json.Unmarshal(data[len(framedPrefix):], v)
+}
+
+func TestCodecNegotiation(t *testing.T) {
+	supported := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		codec := r.Header.Get("Content-Type") == framedContentType
+		if codec && !supported {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		var req MessageRequest
+		var err error
+		if codec {
+			err = framedCodec{}.Unmarshal(body, &req)
+		} else {
+			err = json.Unmarshal(body, &req)
+		}
+		if err != nil {
+			t.Errorf("Failed to decode %s request: %v", r.Header.Get("Content-Type"), err)
+		}
+
+		var resp interface{} = MessageResponse{ID: "msg-1", ItemID: req.ItemID}
+		status := http.StatusOK
+		if req.ItemID == "boom" {
+			resp, status = map[string]string{"error": "boom rejected"}, http.StatusBadRequest
+		}
+		if strings.HasPrefix(r.Header.Get("Accept"), framedContentType) {
+			w.Header().Set("Content-Type", framedContentType)
+			w.WriteHeader(status)
+			data, _ := framedCodec{}.Marshal(resp)
+			w.Write(data)
+			return
+		}
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Codec: framedCodec{}})
+	ctx := context.Background()
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+
+	resp, err := client.PostMessage(ctx, req)
+	if err != nil || resp.ItemID != "pr-1" {
+		t.Fatalf("Expected codec round trip, got %+v, %v", resp, err)
+	}
+
+	// Error bodies in the codec are still translated
+	req.ItemID = "boom"
+	var apiErr *APIError
+	if _, err := client.PostMessage(ctx, req); !errors.As(err, &apiErr) || !strings.Contains(apiErr.Message, "boom rejected") {
+		t.Errorf("Expected translated codec error, got %v", err)
+	}
+
+	// A service without the codec answers 415 and the client falls back to JSON
+	supported = false
+	req.ItemID = "pr-2"
+	if resp, err := client.PostMessage(ctx, req); err != nil || resp.ItemID != "pr-2" {
+		t.Fatalf("Expected JSON fallback, got %+v, %v", resp, err)
+	}
+	if client.accept() != ContentTypeJSON {
+		t.Error("Expected the client to stop offering a rejected codec")
+	}
+}
+
+func TestMarshalBodyMatchesJSON(t *testing.T) {
+	client := NewClient(nil)
+	msg := benchmarkMessage(1)
+	msg.CallbackURL = "https://example.com/callback?a=1&b=<2>"
+	msg.TTL = 90 * time.Second
+	msg.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second}
+
+	for _, body := range []interface{}{&msg, &BulkMessageRequest{Messages: []MessageRequest{msg, benchmarkMessage(2)}}, &BulkMessageRequest{}} {
+		want, _ := json.Marshal(body)
+		data, contentType, buf, err := client.marshalBody(APIVersionV1, "/api/v1/messages", body)
+		if err != nil {
+			t.Fatalf("marshalBody failed: %v", err)
+		}
+		if contentType != ContentTypeJSON || !bytes.Equal(data, want) {
+			t.Errorf("Expected the same JSON as json.Marshal:\n%s\ngot:\n%s", want, data)
+		}
+		buf.release()
+	}
+}
+
+// tagCodec stands in for codecs such as msgpack that honor json tags but not
+// json.Marshaler: it converts structs field by field and frames the result
+type tagCodec struct{ framedCodec }
+
+func (tagCodec) Marshal(v interface{}) ([]byte, error) {
+	return framedCodec{}.Marshal(tagValue(reflect.ValueOf(v)))
+}
+
+// tagValue converts v to maps, slices, and scalars by its json tags
+func tagValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return tagValue(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = tagValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			out[fmt.Sprint(key.Interface())] = tagValue(v.MapIndex(key))
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if field.Anonymous && name == "" {
+				if inner, ok := tagValue(v.Field(i)).(map[string]interface{}); ok {
+					for k, val := range inner {
+						out[k] = val
+					}
+				}
+				continue
+			}
+			if strings.Contains(opts, "omitempty") && v.Field(i).IsZero() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			out[name] = tagValue(v.Field(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
+
+func TestCodecSendsWireForm(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := (framedCodec{}).Unmarshal(body, &received); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", ItemID: "pr-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Codec: tagCodec{}})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	req.TTL = 90 * time.Second
+	req.ProcessingTimeout = 30 * time.Second
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	if received["ttl_ms"] != float64(90000) || received["processing_timeout_ms"] != float64(30000) {
+		t.Errorf("Expected the durations in milliseconds, got %v", received)
+	}
+}
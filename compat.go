@@ -0,0 +1,159 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ServiceVersionHeader is the response header carrying the service version
+const ServiceVersionHeader = "X-Service-Version"
+
+// ErrorTranslator converts an error response into an *APIError. Returning
+// nil leaves the response to the version-based translation
+type ErrorTranslator interface {
+	TranslateError(resp *http.Response, body []byte) *APIError
+}
+
+// ErrorTranslatorFunc adapts a function to the ErrorTranslator interface
+type ErrorTranslatorFunc func(resp *http.Response, body []byte) *APIError
+
+// TranslateError calls f(resp, body)
+func (f ErrorTranslatorFunc) TranslateError(resp *http.Response, body []byte) *APIError {
+	return f(resp, body)
+}
+
+// legacyStatusCodes maps error codes of pre-2.0 services, which reported
+// most failures as 400 or 500, to the status codes used by current services
+var legacyStatusCodes = map[string]int{
+	"not_found":       http.StatusNotFound,
+	"invalid_request": http.StatusBadRequest,
+	"conflict":        http.StatusConflict,
+	"rate_limited":    http.StatusTooManyRequests,
+	"queue_full":      http.StatusServiceUnavailable,
+	"payload_too_big": http.StatusRequestEntityTooLarge,
+}
+
+// CurrentErrorTranslator understands the error format of 2.x services:
+// {"error": {"code": "...", "message": "..."}}
+var CurrentErrorTranslator ErrorTranslator = ErrorTranslatorFunc(func(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+
+	var envelope struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error.Message != "" {
+		apiErr.Code = envelope.Error.Code
+		apiErr.Message = envelope.Error.Message
+	}
+
+	return apiErr
+})
+
+// LegacyErrorTranslator understands the error format of pre-2.0 services,
+// {"error": "...", "error_code": "..."} or plain text, and normalizes their
+// status codes
+var LegacyErrorTranslator ErrorTranslator = ErrorTranslatorFunc(func(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+
+	var legacy struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"error_code"`
+	}
+	if err := json.Unmarshal(body, &legacy); err == nil && legacy.Error != "" {
+		apiErr.Message = legacy.Error
+		apiErr.Code = legacy.ErrorCode
+	}
+
+	if status, ok := legacyStatusCodes[apiErr.Code]; ok {
+		apiErr.StatusCode = status
+	}
+
+	return apiErr
+})
+
+// serviceVersion tracks the service version, either configured or detected
+// from response headers
+type serviceVersion struct {
+	mu      sync.RWMutex
+	version string
+	pinned  bool
+}
+
+// get returns the known service version
+func (v *serviceVersion) get() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.version
+}
+
+// observe records the version advertised by a response unless one was configured
+func (v *serviceVersion) observe(resp *http.Response) {
+	detected := resp.Header.Get(ServiceVersionHeader)
+	if detected == "" {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if !v.pinned {
+		v.version = detected
+	}
+}
+
+// majorVersion returns the major component of a version such as "v1.4.2",
+// or -1 when it cannot be parsed
+func majorVersion(version string) int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// errorTranslatorFor selects the translator for a service version; unknown
+// versions are treated as current
+func errorTranslatorFor(version string) ErrorTranslator {
+	if major := majorVersion(version); major >= 0 && major < 2 {
+		return LegacyErrorTranslator
+	}
+	return CurrentErrorTranslator
+}
+
+// translateError builds the APIError for an error response. A custom
+// translator returning nil falls back to the version-based translation, so
+// the result is never a nil *APIError
+func (c *Client) translateError(resp *http.Response, body []byte) error {
+	var apiErr *APIError
+	if c.errorTranslator != nil {
+		apiErr = c.errorTranslator.TranslateError(resp, body)
+	}
+	if apiErr == nil {
+		version := resp.Header.Get(ServiceVersionHeader)
+		if version == "" {
+			version = c.version.get()
+		}
+		apiErr = errorTranslatorFor(version).TranslateError(resp, body)
+	}
+
+	if apiErr.Fingerprint == "" && resp.Request != nil {
+		apiErr.Fingerprint = resp.Request.Header.Get(FingerprintHeader)
+	}
+	if apiErr.Meta.StatusCode == 0 {
+		apiErr.Meta = c.responseMetaFrom(resp)
+	}
+	return apiErr
+}
+
+// ServiceVersion returns the service version configured or last detected
+// from response headers, or "" if it is not known yet
+func (c *Client) ServiceVersion() string {
+	return c.version.get()
+}
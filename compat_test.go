@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorTranslationByServiceVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		status     int
+		body       string
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{"current", "2.3.0", http.StatusConflict, `{"error":{"code":"duplicate","message":"already queued"}}`, http.StatusConflict, "duplicate", "already queued"},
+		{"legacy status remap", "v1.9.1", http.StatusInternalServerError, `{"error":"queue is full","error_code":"queue_full"}`, http.StatusServiceUnavailable, "queue_full", "queue is full"},
+		{"legacy plain text", "1.0", http.StatusBadRequest, "bad priority\n", http.StatusBadRequest, "", "bad priority"},
+		{"unknown version", "", http.StatusBadGateway, "upstream down", http.StatusBadGateway, "", "upstream down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.version != "" {
+					w.Header().Set(ServiceVersionHeader, tt.version)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(&Config{BaseURL: server.URL})
+			_, err := client.GetWorkerStatus(context.Background())
+			apiErr, ok := err.(*APIError)
+			if !ok {
+				t.Fatalf("Expected APIError, got %v", err)
+			}
+			if apiErr.StatusCode != tt.wantStatus || apiErr.Code != tt.wantCode || apiErr.Message != tt.wantMsg {
+				t.Errorf("Expected %d/%s/%s, got %d/%s/%s", tt.wantStatus, tt.wantCode, tt.wantMsg, apiErr.StatusCode, apiErr.Code, apiErr.Message)
+			}
+			if client.ServiceVersion() != tt.version {
+				t.Errorf("Expected detected version '%s', got '%s'", tt.version, client.ServiceVersion())
+			}
+		})
+	}
+}
+
+func TestPinnedServiceVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"not here","error_code":"not_found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, ServiceVersion: "1.2.0"})
+	_, err := client.GetWorkerStatus(context.Background())
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected legacy not_found to map to 404, got %v", err)
+	}
+}
+
+func TestCustomErrorTranslatorFallsBack(t *testing.T) {
+	status := http.StatusConflict
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ServiceVersionHeader, "2.3.0")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":{"code":"duplicate","message":"already queued"}}`))
+	}))
+	defer server.Close()
+
+	// The translator only handles 503s and leaves the rest to the default
+	client := NewClient(&Config{BaseURL: server.URL, ErrorTranslator: ErrorTranslatorFunc(func(resp *http.Response, body []byte) *APIError {
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			return nil
+		}
+		return &APIError{StatusCode: resp.StatusCode, Code: "unavailable", Message: "maintenance"}
+	})})
+
+	_, err := client.GetWorkerStatus(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr == nil || apiErr.Code != "duplicate" || err.Error() == "" {
+		t.Fatalf("Expected the default translation, got %#v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	_, err = client.GetWorkerStatus(context.Background())
+	if !errors.As(err, &apiErr) || apiErr.Code != "unavailable" {
+		t.Errorf("Expected the custom translation, got %v", err)
+	}
+}
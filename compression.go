@@ -0,0 +1,191 @@
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content-coding tokens for the built-in compressors
+const (
+	EncodingGzip   = "gzip"
+	EncodingZstd   = "zstd"
+	EncodingSnappy = "snappy"
+)
+
+// Compressor compresses request bodies and decompresses response bodies
+// for a single HTTP content-coding
+type Compressor interface {
+	// Encoding returns the content-coding token sent in Content-Encoding
+	Encoding() string
+	// Compress wraps w so that everything written to it is compressed
+	Compress(w io.Writer) (io.WriteCloser, error)
+	// Decompress wraps r so that reading from it yields decompressed data
+	Decompress(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCompressor implements Compressor using gzip
+type GzipCompressor struct {
+	// Level is the gzip compression level; zero means the default level
+	Level int
+}
+
+// Encoding returns "gzip"
+func (g GzipCompressor) Encoding() string { return EncodingGzip }
+
+// Compress returns a gzip writer wrapping w
+func (g GzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if g.Level == 0 {
+		return gzip.NewWriter(w), nil
+	}
+	return gzip.NewWriterLevel(w, g.Level)
+}
+
+// Decompress returns a gzip reader wrapping r
+func (g GzipCompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// ZstdCompressor implements Compressor using zstd
+type ZstdCompressor struct{}
+
+// Encoding returns "zstd"
+func (ZstdCompressor) Encoding() string { return EncodingZstd }
+
+// Compress returns a zstd encoder wrapping w
+func (ZstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+// Decompress returns a zstd decoder wrapping r
+func (ZstdCompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+// SnappyCompressor implements Compressor using the snappy framing format
+type SnappyCompressor struct{}
+
+// Encoding returns "snappy"
+func (SnappyCompressor) Encoding() string { return EncodingSnappy }
+
+// Compress returns a snappy writer wrapping w
+func (SnappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+// Decompress returns a snappy reader wrapping r
+func (SnappyCompressor) Decompress(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]Compressor{
+		EncodingGzip:   GzipCompressor{},
+		EncodingZstd:   ZstdCompressor{},
+		EncodingSnappy: SnappyCompressor{},
+	}
+)
+
+// RegisterCompressor makes a compressor available for decoding responses and
+// advertises it in Accept-Encoding; it replaces any compressor with the same encoding
+func RegisterCompressor(c Compressor) {
+	if c == nil {
+		return
+	}
+
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[strings.ToLower(c.Encoding())] = c
+}
+
+// compressorFor returns the registered compressor for the given encoding
+func compressorFor(encoding string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[strings.ToLower(strings.TrimSpace(encoding))]
+	return c, ok
+}
+
+// acceptEncoding returns the Accept-Encoding header value for all registered compressors
+func acceptEncoding() string {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+
+	encodings := make([]string, 0, len(compressors))
+	for encoding := range compressors {
+		encodings = append(encodings, encoding)
+	}
+	sort.Strings(encodings)
+	return strings.Join(encodings, ", ")
+}
+
+// compress compresses data with the given compressor
+func compress(c Compressor, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := compressTo(&buf, c, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compressTo compresses data with the given compressor into dst
+func compressTo(dst *bytes.Buffer, c Compressor, data []byte) error {
+	w, err := c.Compress(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s writer: %w", c.Encoding(), err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return nil
+}
+
+// decompressedBody wraps a response body with its decompressor so that
+// closing it releases both
+type decompressedBody struct {
+	io.Reader
+	decoder io.Closer
+	body    io.Closer
+}
+
+func (d *decompressedBody) Close() error {
+	d.decoder.Close()
+	return d.body.Close()
+}
+
+// decodeResponseBody replaces resp.Body with a decompressing reader when the
+// response carries a Content-Encoding handled by a registered compressor
+func decodeResponseBody(body io.ReadCloser, encoding string) (io.ReadCloser, bool, error) {
+	if encoding == "" || strings.EqualFold(encoding, "identity") {
+		return body, false, nil
+	}
+
+	c, ok := compressorFor(encoding)
+	if !ok {
+		return body, false, nil
+	}
+
+	dec, err := c.Decompress(body)
+	if err != nil {
+		body.Close()
+		return nil, false, fmt.Errorf("failed to decode %s response: %w", encoding, err)
+	}
+
+	return &decompressedBody{Reader: dec, decoder: dec, body: body}, true, nil
+}
@@ -0,0 +1,134 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCompressorRoundTrip(t *testing.T) {
+	data := []byte(`{"item_id":"pr-123","object_body":{"diff":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}}`)
+
+	for _, c := range []Compressor{GzipCompressor{}, ZstdCompressor{}, SnappyCompressor{}} {
+		compressed, err := compress(c, data)
+		if err != nil {
+			t.Fatalf("%s: failed to compress: %v", c.Encoding(), err)
+		}
+
+		r, err := c.Decompress(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatalf("%s: failed to create decompressor: %v", c.Encoding(), err)
+		}
+		out, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("%s: failed to decompress: %v", c.Encoding(), err)
+		}
+		if string(out) != string(data) {
+			t.Errorf("%s: expected round trip to preserve data, got '%s'", c.Encoding(), out)
+		}
+	}
+}
+
+func TestPostMessageCompressed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != EncodingZstd {
+			t.Errorf("Expected Content-Encoding 'zstd', got '%s'", r.Header.Get("Content-Encoding"))
+		}
+
+		dec, err := ZstdCompressor{}.Decompress(r.Body)
+		if err != nil {
+			t.Fatalf("Failed to create decoder: %v", err)
+		}
+		defer dec.Close()
+
+		var req MessageRequest
+		if err := json.NewDecoder(dec).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+
+		// Respond with a gzip encoded body
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", EncodingGzip)
+		w.WriteHeader(http.StatusCreated)
+		gz, _ := GzipCompressor{}.Compress(w)
+		json.NewEncoder(gz).Encode(MessageResponse{ID: "msg-1", ItemID: req.ItemID})
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:    server.URL,
+		Timeout:    5 * time.Second,
+		Compressor: ZstdCompressor{},
+	})
+
+	resp, err := client.PostMessageWithDefaults(context.Background(), "pr-1", "https://example.com/callback", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.ID != "msg-1" || resp.ItemID != "pr-1" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestCompressionFallbackOnUnsupportedMediaType(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Content-Encoding") != "" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:    server.URL,
+		Compressor: GzipCompressor{},
+	})
+
+	if _, err := client.PostMessageWithDefaults(context.Background(), "pr-1", "https://example.com/callback", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestCompressionThreshold(t *testing.T) {
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:              server.URL,
+		Compressor:           GzipCompressor{},
+		CompressionThreshold: 1024,
+	})
+	ctx := context.Background()
+
+	if _, err := client.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/callback", map[string]string{"diff": "small"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	large := map[string]string{"diff": string(bytes.Repeat([]byte("a"), 2048))}
+	if _, err := client.PostMessageWithDefaults(ctx, "pr-2", "https://example.com/callback", large); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(encodings) != 2 || encodings[0] != "" || encodings[1] != EncodingGzip {
+		t.Errorf("Expected only the large body to be gzipped, got %q", encodings)
+	}
+}
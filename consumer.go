@@ -0,0 +1,426 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults for pulling messages
+const (
+	DefaultVisibilityTimeout   = 30 * time.Second
+	DefaultConsumerBatchSize   = 10
+	DefaultConsumerConcurrency = 1
+	// DefaultConsumerWaitTime keeps long polls well below the default
+	// Config.Timeout of 30 seconds
+	DefaultConsumerWaitTime     = 10 * time.Second
+	DefaultConsumerErrorBackoff = time.Second
+)
+
+// MaxPullMessages is the largest number of messages a single pull returns
+const MaxPullMessages = 100
+
+// ErrDiscardMessage is returned by a ConsumerHandler to nack a message
+// without requeueing it, so that the service dead-letters it instead of
+// delivering it again
+var ErrDiscardMessage = errors.New("discard message")
+
+// ErrConsumerLoopStarted is returned by ConsumerLoop.Run when the loop has
+// already been run; create a new loop to consume again
+var ErrConsumerLoopStarted = errors.New("consumer loop has already been run")
+
+// PulledMessage is a message leased to a consumer. It stays invisible to
+// other consumers until VisibleUntil, after which the service delivers it
+// again unless it was acknowledged
+type PulledMessage struct {
+	ID         string            `json:"id"`
+	ItemID     string            `json:"item_id"`
+	Priority   Priority          `json:"priority"`
+	Topic      Topic             `json:"topic"`
+	ObjectBody json.RawMessage   `json:"object_body"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	GroupID    string            `json:"group_id,omitempty"`
+	// Receipt identifies this lease in AckMessage, NackMessage, and
+	// ExtendVisibility; a message delivered again gets a new receipt
+	Receipt string `json:"receipt"`
+	// Attempts counts deliveries of the message, including this one
+	Attempts     int    `json:"attempts"`
+	VisibleUntil string `json:"visible_until"`
+	Tenant       string `json:"tenant,omitempty"`
+	// CorrelationID is the correlation ID the message was submitted with
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// DecodeBody unmarshals the message's object body into v
+func (m *PulledMessage) DecodeBody(v interface{}) error {
+	return json.Unmarshal(m.ObjectBody, v)
+}
+
+// pullRequest represents the request body for pulling messages
+type pullRequest struct {
+	Priority            Priority `json:"priority"`
+	MaxMessages         int      `json:"max_messages"`
+	VisibilityTimeoutMs int64    `json:"visibility_timeout_ms"`
+	WaitMs              int64    `json:"wait_ms,omitempty"`
+}
+
+// pullResponse represents the response listing pulled messages
+type pullResponse struct {
+	Messages []PulledMessage `json:"messages"`
+}
+
+// receiptRequest represents the request body of ack, nack, and visibility
+// requests
+type receiptRequest struct {
+	Receipt             string `json:"receipt"`
+	Requeue             *bool  `json:"requeue,omitempty"`
+	VisibilityTimeoutMs int64  `json:"visibility_timeout_ms,omitempty"`
+}
+
+// PullMessages leases up to max queued messages of a priority, hiding them
+// from other consumers for visibilityTimeout. It returns immediately, with
+// no messages when the queue is empty; ConsumerLoop long-polls instead. A
+// zero visibilityTimeout uses DefaultVisibilityTimeout
+func (c *Client) PullMessages(ctx context.Context, priority Priority, max int, visibilityTimeout time.Duration) ([]PulledMessage, error) {
+	return c.pullMessages(ctx, priority, max, visibilityTimeout, 0)
+}
+
+// pullMessages leases messages, waiting up to wait for one to arrive
+func (c *Client) pullMessages(ctx context.Context, priority Priority, max int, visibilityTimeout, wait time.Duration) ([]PulledMessage, error) {
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+	if max <= 0 || max > MaxPullMessages {
+		return nil, fmt.Errorf("max must be between 1 and %d", MaxPullMessages)
+	}
+	if visibilityTimeout < 0 {
+		return nil, fmt.Errorf("visibility timeout cannot be negative")
+	}
+	if visibilityTimeout == 0 {
+		visibilityTimeout = DefaultVisibilityTimeout
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/pull", pullRequest{
+		Priority:            priority,
+		MaxMessages:         max,
+		VisibilityTimeoutMs: visibilityTimeout.Milliseconds(),
+		WaitMs:              wait.Milliseconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pulled pullResponse
+	if err := c.parseResponse(resp, &pulled); err != nil {
+		return nil, err
+	}
+
+	return pulled.Messages, nil
+}
+
+// AckMessage marks a pulled message as processed, removing it from the queue
+func (c *Client) AckMessage(ctx context.Context, receipt string) error {
+	return c.postReceipt(ctx, "/api/v1/messages/ack", receiptRequest{Receipt: receipt})
+}
+
+// NackMessage releases a pulled message. With requeue it becomes visible to
+// consumers again right away; without it the service dead-letters it
+func (c *Client) NackMessage(ctx context.Context, receipt string, requeue bool) error {
+	return c.postReceipt(ctx, "/api/v1/messages/nack", receiptRequest{Receipt: receipt, Requeue: &requeue})
+}
+
+// ExtendVisibility keeps a pulled message hidden from other consumers for
+// visibilityTimeout from now, e.g. while a long job is still running
+func (c *Client) ExtendVisibility(ctx context.Context, receipt string, visibilityTimeout time.Duration) error {
+	if visibilityTimeout <= 0 {
+		return fmt.Errorf("visibility timeout must be positive")
+	}
+	return c.postReceipt(ctx, "/api/v1/messages/visibility", receiptRequest{
+		Receipt:             receipt,
+		VisibilityTimeoutMs: visibilityTimeout.Milliseconds(),
+	})
+}
+
+// postReceipt sends a request acting on a lease
+func (c *Client) postReceipt(ctx context.Context, path string, body receiptRequest) error {
+	if body.Receipt == "" {
+		return fmt.Errorf("receipt is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+
+	return c.parseResponse(resp, nil)
+}
+
+// ConsumerHandler processes a pulled message. Returning nil acknowledges
+// it, ErrDiscardMessage nacks it without requeueing, and any other error
+// nacks it for redelivery
+type ConsumerHandler func(ctx context.Context, msg *PulledMessage) error
+
+// ConsumerOptions controls how a ConsumerLoop pulls and leases messages
+type ConsumerOptions struct {
+	// Priority is the queue to consume; required
+	Priority Priority
+	// BatchSize is the largest number of messages pulled at once; defaults
+	// to DefaultConsumerBatchSize
+	BatchSize int
+	// Concurrency is the number of messages handled at the same time;
+	// defaults to DefaultConsumerConcurrency
+	Concurrency int
+	// VisibilityTimeout is the lease taken on each message; defaults to
+	// DefaultVisibilityTimeout. Leases are extended every half timeout while
+	// the handler runs, so handlers may take longer than the timeout
+	VisibilityTimeout time.Duration
+	// WaitTime is how long a pull waits for a message to arrive; defaults to
+	// DefaultConsumerWaitTime. Keep it below Config.Timeout
+	WaitTime time.Duration
+	// ErrorBackoff is the pause after a failed pull; defaults to
+	// DefaultConsumerErrorBackoff
+	ErrorBackoff time.Duration
+	// OnError, if set, is called with errors that do not stop the loop: failed
+	// pulls, acks, nacks, and lease extensions
+	OnError func(err error)
+}
+
+// withDefaults fills unset consumer options
+func (o ConsumerOptions) withDefaults() ConsumerOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultConsumerBatchSize
+	}
+	if o.BatchSize > MaxPullMessages {
+		o.BatchSize = MaxPullMessages
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConsumerConcurrency
+	}
+	if o.VisibilityTimeout <= 0 {
+		o.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if o.WaitTime <= 0 {
+		o.WaitTime = DefaultConsumerWaitTime
+	}
+	if o.ErrorBackoff <= 0 {
+		o.ErrorBackoff = DefaultConsumerErrorBackoff
+	}
+	return o
+}
+
+// ConsumerLoop pulls messages and hands them to a handler, extending each
+// lease while the handler runs and acknowledging or nacking the message
+// when it returns. It pulls only as many messages as it has free handlers,
+// so leases are not held by messages waiting in memory
+type ConsumerLoop struct {
+	client  *Client
+	opts    ConsumerOptions
+	handler ConsumerHandler
+
+	mu       sync.Mutex
+	started  bool
+	stopping chan struct{}
+	stopped  chan struct{}
+}
+
+// NewConsumerLoop creates a loop that consumes through client
+func NewConsumerLoop(client *Client, opts ConsumerOptions, handler ConsumerHandler) *ConsumerLoop {
+	return &ConsumerLoop{
+		client:   client,
+		opts:     opts.withDefaults(),
+		handler:  handler,
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Run consumes messages until ctx is done or Drain is called. Either way it
+// stops pulling, lets the handlers finish the messages they hold, and then
+// returns; handlers see a context that is not canceled by ctx, so in-flight
+// messages are settled rather than abandoned. Run returns nil after Drain
+// and ctx's error otherwise. A loop runs once; calling Run again returns
+// ErrConsumerLoopStarted
+func (l *ConsumerLoop) Run(ctx context.Context) error {
+	if !l.opts.Priority.IsValid() {
+		return fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+	if l.handler == nil {
+		return fmt.Errorf("handler is required")
+	}
+	l.mu.Lock()
+	if l.started {
+		l.mu.Unlock()
+		return ErrConsumerLoopStarted
+	}
+	l.started = true
+	l.mu.Unlock()
+	defer close(l.stopped)
+	defer l.client.RegisterShutdownHook(ShutdownStopIntake, ShutdownHookFunc(l.Drain))()
+
+	// Pulls are canceled by either ctx or Drain
+	pullCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.stopping:
+			cancel()
+		case <-pullCtx.Done():
+		}
+	}()
+
+	// Handlers outlive ctx so that in-flight messages can be acked
+	handlerCtx := context.WithoutCancel(ctx)
+
+	slots := make(chan struct{}, l.opts.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		// Wait for at least one free handler before pulling
+		select {
+		case slots <- struct{}{}:
+		case <-pullCtx.Done():
+			return l.result(ctx)
+		}
+		free := 1
+	fill:
+		for free < l.opts.BatchSize {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		messages, err := l.client.pullMessages(pullCtx, l.opts.Priority, free, l.opts.VisibilityTimeout, l.opts.WaitTime)
+		// Return the slots that did not get a message
+		for i := len(messages); i < free; i++ {
+			<-slots
+		}
+		if err != nil {
+			if pullCtx.Err() != nil {
+				return l.result(ctx)
+			}
+			l.reportError(fmt.Errorf("failed to pull messages: %w", err))
+			sleep(pullCtx, l.client.clock, l.opts.ErrorBackoff)
+			continue
+		}
+
+		for i := range messages {
+			msg := &messages[i]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				l.handle(handlerCtx, msg)
+			}()
+		}
+	}
+}
+
+// Drain stops the loop from pulling and waits until the messages being
+// handled have been settled and Run has returned, or until ctx is done
+func (l *ConsumerLoop) Drain(ctx context.Context) error {
+	l.mu.Lock()
+	select {
+	case <-l.stopping:
+	default:
+		close(l.stopping)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// result is what Run returns once it stops
+func (l *ConsumerLoop) result(ctx context.Context) error {
+	select {
+	case <-l.stopping:
+		return nil
+	default:
+		return ctx.Err()
+	}
+}
+
+// handle runs the handler on msg while extending its lease, then settles it
+func (l *ConsumerLoop) handle(ctx context.Context, msg *PulledMessage) {
+	handlerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	extended := make(chan struct{})
+	go func() {
+		defer close(extended)
+		l.extendLease(handlerCtx, cancel, msg.Receipt)
+	}()
+
+	err := l.handler(handlerCtx, msg)
+	cancel()
+	<-extended
+
+	switch {
+	case err == nil:
+		err = l.client.AckMessage(ctx, msg.Receipt)
+		if err != nil {
+			err = fmt.Errorf("failed to ack message %s: %w", msg.ID, err)
+		}
+	case errors.Is(err, ErrDiscardMessage):
+		err = l.client.NackMessage(ctx, msg.Receipt, false)
+		if err != nil {
+			err = fmt.Errorf("failed to discard message %s: %w", msg.ID, err)
+		}
+	default:
+		err = l.client.NackMessage(ctx, msg.Receipt, true)
+		if err != nil {
+			err = fmt.Errorf("failed to requeue message %s: %w", msg.ID, err)
+		}
+	}
+	if err != nil {
+		l.reportError(err)
+	}
+}
+
+// extendLease extends the lease on receipt every half visibility timeout
+// until ctx is done. If the service rejects an extension the lease is lost,
+// so the handler's context is canceled: the message will be delivered again
+func (l *ConsumerLoop) extendLease(ctx context.Context, cancel context.CancelFunc, receipt string) {
+	ticker := l.client.clock.NewTicker(l.opts.VisibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		err := l.client.ExtendVisibility(ctx, receipt, l.opts.VisibilityTimeout)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+		l.reportError(fmt.Errorf("failed to extend lease: %w", err))
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound ||
+			apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusGone) {
+			cancel()
+			return
+		}
+	}
+}
+
+// reportError passes an error that does not stop the loop to OnError
+func (l *ConsumerLoop) reportError(err error) {
+	if l.opts.OnError != nil {
+		l.opts.OnError(err)
+	}
+}
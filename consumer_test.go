@@ -0,0 +1,170 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPullAckNack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/messages/pull"):
+			var req pullRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Priority != PriorityHigh || req.MaxMessages != 5 || req.VisibilityTimeoutMs != 60000 || req.WaitMs != 0 {
+				t.Errorf("Unexpected pull request: %+v", req)
+			}
+			json.NewEncoder(w).Encode(pullResponse{Messages: []PulledMessage{{
+				ID: "msg-1", ItemID: "pr-1", Priority: PriorityHigh, Receipt: "r-1", Attempts: 1,
+				ObjectBody: json.RawMessage(`{"number":7}`),
+			}}})
+		case strings.HasSuffix(r.URL.Path, "/messages/ack"):
+			var req receiptRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Receipt != "r-1" {
+				t.Errorf("Expected receipt r-1, got %q", req.Receipt)
+			}
+			w.Write([]byte(`{"status":"acknowledged"}`))
+		case strings.HasSuffix(r.URL.Path, "/messages/nack"):
+			var req receiptRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Requeue == nil || *req.Requeue {
+				t.Errorf("Expected requeue false to be sent, got %v", req.Requeue)
+			}
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"error":"lease expired"}`))
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	messages, err := client.PullMessages(ctx, PriorityHigh, 5, time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(messages) != 1 || messages[0].Receipt != "r-1" {
+		t.Fatalf("Unexpected messages: %+v", messages)
+	}
+	var body struct{ Number int }
+	if err := messages[0].DecodeBody(&body); err != nil || body.Number != 7 {
+		t.Errorf("Expected decoded body, got %+v, %v", body, err)
+	}
+
+	if err := client.AckMessage(ctx, "r-1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	var apiErr *APIError
+	if err := client.NackMessage(ctx, "r-1", false); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusGone {
+		t.Errorf("Expected expired lease error, got %v", err)
+	}
+
+	if _, err := client.PullMessages(ctx, PriorityHigh, MaxPullMessages+1, 0); err == nil {
+		t.Error("Expected error for max above MaxPullMessages")
+	}
+	if err := client.AckMessage(ctx, ""); err == nil {
+		t.Error("Expected error for empty receipt")
+	}
+}
+
+func TestConsumerLoop(t *testing.T) {
+	var mu sync.Mutex
+	queue := []string{"ok", "retry", "discard", "slow"}
+	settled := make(map[string]string)
+	extended := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			MaxMessages int    `json:"max_messages"`
+			WaitMs      int64  `json:"wait_ms"`
+			Receipt     string `json:"receipt"`
+			Requeue     *bool  `json:"requeue"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/messages/pull"):
+			if req.WaitMs == 0 {
+				t.Error("Expected consumer to long-poll")
+			}
+			var resp pullResponse
+			for len(queue) > 0 && len(resp.Messages) < req.MaxMessages {
+				resp.Messages = append(resp.Messages, PulledMessage{ID: queue[0], Receipt: queue[0]})
+				queue = queue[1:]
+			}
+			json.NewEncoder(w).Encode(resp)
+		case strings.HasSuffix(r.URL.Path, "/messages/ack"):
+			settled[req.Receipt] = "ack"
+		case strings.HasSuffix(r.URL.Path, "/messages/nack"):
+			settled[req.Receipt] = "nack"
+			if *req.Requeue {
+				settled[req.Receipt] = "requeue"
+			}
+		case strings.HasSuffix(r.URL.Path, "/messages/visibility"):
+			extended[req.Receipt]++
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	slowStarted := make(chan struct{})
+	loop := NewConsumerLoop(client, ConsumerOptions{
+		Priority:          PriorityLow,
+		Concurrency:       4,
+		VisibilityTimeout: 20 * time.Millisecond,
+		WaitTime:          time.Millisecond,
+	}, func(ctx context.Context, msg *PulledMessage) error {
+		switch msg.ID {
+		case "retry":
+			return errors.New("downstream unavailable")
+		case "discard":
+			return ErrDiscardMessage
+		case "slow":
+			close(slowStarted)
+			time.Sleep(60 * time.Millisecond)
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- loop.Run(ctx) }()
+
+	<-slowStarted
+	// Drain waits for the slow handler rather than abandoning its lease
+	if err := loop.Drain(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected Run to return nil after Drain, got %v", err)
+	}
+	if err := loop.Run(ctx); !errors.Is(err, ErrConsumerLoopStarted) {
+		t.Errorf("Expected a second Run to be refused, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := map[string]string{"ok": "ack", "retry": "requeue", "discard": "nack", "slow": "ack"}
+	for id, want := range expected {
+		if settled[id] != want {
+			t.Errorf("Expected %s to be settled with %s, got %q", id, want, settled[id])
+		}
+	}
+	if extended["slow"] == 0 {
+		t.Error("Expected the slow message's lease to be extended")
+	}
+}
@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+var uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestCorrelationIDGenerated(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		// The service does not echo the correlation ID
+		w.Write([]byte(`{"id":"msg-1","status":"queued","itemId":"pr-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	resp, err := client.PostMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	if !uuidV7Pattern.MatchString(received.CorrelationID) {
+		t.Errorf("Expected a generated UUIDv7 correlation ID, got '%s'", received.CorrelationID)
+	}
+	if resp.CorrelationID != received.CorrelationID {
+		t.Errorf("Expected response correlation ID '%s', got '%s'", received.CorrelationID, resp.CorrelationID)
+	}
+	if req.CorrelationID != "" {
+		t.Error("Expected caller's request to be untouched")
+	}
+}
+
+func TestCorrelationIDExplicit(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id":"msg-1","status":"queued","itemId":"pr-1","correlationId":"order-42"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	req.CorrelationID = "order-42"
+	resp, err := client.PostMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if received.CorrelationID != "order-42" || resp.CorrelationID != "order-42" {
+		t.Errorf("Expected the explicit correlation ID to be kept, sent '%s' and got '%s'", received.CorrelationID, resp.CorrelationID)
+	}
+}
+
+func TestCorrelationIDBulk(t *testing.T) {
+	var received BulkMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"status":"success","count":2,"messages":[
+			{"id":"msg-2","itemId":"pr-2","correlationId":"echoed"},
+			{"id":"msg-1","itemId":"pr-1"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	resp, err := client.PostBulkMessages(context.Background(), &BulkMessageRequest{Messages: []MessageRequest{
+		*newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+		*newMessageRequest("pr-2", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+	}})
+	if err != nil {
+		t.Fatalf("PostBulkMessages failed: %v", err)
+	}
+
+	first, second := received.Messages[0].CorrelationID, received.Messages[1].CorrelationID
+	if !uuidV7Pattern.MatchString(first) || !uuidV7Pattern.MatchString(second) || first == second {
+		t.Errorf("Expected distinct generated correlation IDs, got '%s' and '%s'", first, second)
+	}
+	if resp.Messages[0].CorrelationID != "echoed" {
+		t.Errorf("Expected the echoed correlation ID to be kept, got '%s'", resp.Messages[0].CorrelationID)
+	}
+	if resp.Messages[1].CorrelationID != first {
+		t.Errorf("Expected correlation ID '%s' for pr-1, got '%s'", first, resp.Messages[1].CorrelationID)
+	}
+}
+
+func TestCorrelationIDV2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"message_id":"msg-1","status":"queued","item_id":"pr-1","correlation_id":"echoed"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionV2})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	resp, err := client.PostMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if resp.ID != "msg-1" || resp.CorrelationID != "echoed" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// DefaultDedupCacheWindow is how long a submission is remembered when
+// DedupCacheConfig does not set a Window
+const DefaultDedupCacheWindow = time.Minute
+
+// DedupCacheConfig enables the client-side deduplication cache of
+// PostMessage: a message with the item ID and topic of one submitted within
+// Window is not posted again, and the first submission's response is
+// returned with Deduplicated set. Unlike Config.DedupWindow, which scopes
+// checks made by the service, the cache answers without a request, e.g.
+// when an upstream webhook delivers the same event twice in a few seconds
+type DedupCacheConfig struct {
+	// Window is how long a successful submission is remembered; defaults to
+	// DefaultDedupCacheWindow
+	Window time.Duration
+	// Store holds the remembered submissions, e.g. in Redis to deduplicate
+	// across processes; defaults to an in-memory store owned by the client
+	Store DedupStore
+}
+
+// DedupEntry is a submission remembered by the deduplication cache
+type DedupEntry struct {
+	Response    MessageResponse `json:"response"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	// ExpiresAt is when the entry stops deduplicating; stores may drop it
+	// from then on
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DedupStore holds the entries of the deduplication cache. Keys combine the
+// tenant, topic, and item ID of a message
+type DedupStore interface {
+	// Get returns the entry stored under key, or nil if there is none
+	Get(ctx context.Context, key string) (*DedupEntry, error)
+	Put(ctx context.Context, key string, entry DedupEntry) error
+}
+
+// MemoryDedupStore is a DedupStore held in memory, which deduplicates within
+// a single process
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	entries map[string]DedupEntry
+	// pruneAt is the number of entries at which expired ones are dropped
+	pruneAt int
+}
+
+// NewMemoryDedupStore returns an empty in-memory deduplication store
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{entries: make(map[string]DedupEntry)}
+}
+
+// Get returns the entry stored under key
+func (s *MemoryDedupStore) Get(ctx context.Context, key string) (*DedupEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Put stores entry under key, dropping entries that expired before it was
+// submitted whenever the store has doubled in size since the last pass
+func (s *MemoryDedupStore) Put(ctx context.Context, key string, entry DedupEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry
+	if len(s.entries) >= s.pruneAt {
+		for k, e := range s.entries {
+			if !e.ExpiresAt.After(entry.SubmittedAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.pruneAt = 2 * len(s.entries)
+	}
+	return nil
+}
+
+// Len returns the number of entries held, including expired ones not yet dropped
+func (s *MemoryDedupStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// dedupCache deduplicates the submissions of a client and its tenant clients
+type dedupCache struct {
+	window time.Duration
+	store  DedupStore
+	now    func() time.Time
+
+	mu       sync.Mutex
+	inflight map[string]*dedupCall
+}
+
+// dedupCall is a submission that concurrent duplicates wait for
+type dedupCall struct {
+	done chan struct{}
+	resp *MessageResponse
+	err  error
+}
+
+// newDedupCache returns a cache configured by config, or nil when config is nil
+func newDedupCache(config *DedupCacheConfig, clock Clock) *dedupCache {
+	if config == nil {
+		return nil
+	}
+	cache := &dedupCache{
+		window:   DefaultDedupCacheWindow,
+		store:    config.Store,
+		now:      clock.Now,
+		inflight: make(map[string]*dedupCall),
+	}
+	if config.Window > 0 {
+		cache.window = config.Window
+	}
+	if cache.store == nil {
+		cache.store = NewMemoryDedupStore()
+	}
+	return cache
+}
+
+// dedupKey identifies req within the cache
+func dedupKey(tenant string, req *MessageRequest) string {
+	return tenant + "/" + string(req.Topic) + "/" + req.ItemID
+}
+
+// do returns the remembered response for key or calls post, once for all
+// concurrent callers with the same key. Only successful submissions are
+// remembered. A store that fails is bypassed, since a missed duplicate is
+// better than a lost message
+func (d *dedupCache) do(ctx context.Context, key string, post func() (*MessageResponse, error)) (*MessageResponse, error) {
+	if entry := d.lookup(ctx, key); entry != nil {
+		return deduplicated(&entry.Response), nil
+	}
+
+	d.mu.Lock()
+	// A submission may have finished and been stored since the check above,
+	// after which it is no longer in flight
+	if entry := d.lookup(ctx, key); entry != nil {
+		d.mu.Unlock()
+		return deduplicated(&entry.Response), nil
+	}
+	if call, ok := d.inflight[key]; ok {
+		d.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err == nil {
+			return deduplicated(call.resp), nil
+		}
+		// The first submission failed, so this one is not a duplicate
+		return post()
+	}
+	call := &dedupCall{done: make(chan struct{})}
+	d.inflight[key] = call
+	d.mu.Unlock()
+
+	resp, err := post()
+	if err == nil {
+		// Keep a copy, since the caller owns resp
+		shared := *resp
+		shared.Metadata = maps.Clone(resp.Metadata)
+		call.resp = &shared
+
+		now := d.now()
+		d.store.Put(ctx, key, DedupEntry{Response: shared, SubmittedAt: now, ExpiresAt: now.Add(d.window)})
+	}
+	call.err = err
+
+	d.mu.Lock()
+	delete(d.inflight, key)
+	d.mu.Unlock()
+	close(call.done)
+
+	return resp, err
+}
+
+// lookup returns the live entry stored under key, or nil when there is none
+// or the store fails
+func (d *dedupCache) lookup(ctx context.Context, key string) *DedupEntry {
+	entry, err := d.store.Get(ctx, key)
+	if err != nil || entry == nil || !d.now().Before(entry.ExpiresAt) {
+		return nil
+	}
+	return entry
+}
+
+// deduplicated returns a copy of resp marked as deduplicated
+func deduplicated(resp *MessageResponse) *MessageResponse {
+	out := *resp
+	out.Metadata = maps.Clone(resp.Metadata)
+	out.Deduplicated = true
+	return &out
+}
@@ -0,0 +1,219 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDedupCache(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		n := posts.Add(1)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + string(rune('0'+n)), ItemID: req.ItemID, Topic: req.Topic})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, DedupCache: &DedupCacheConfig{Window: 30 * time.Second}})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client.dedupCache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	first, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if first.Deduplicated {
+		t.Error("Expected the first submission not to be deduplicated")
+	}
+
+	now = now.Add(10 * time.Second)
+	dup, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if !dup.Deduplicated || dup.ID != first.ID {
+		t.Errorf("Expected the first response marked as deduplicated, got %+v", dup)
+	}
+
+	// The same item ID in another topic is a different message
+	if resp, _ := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, "deployments", "https://example.com/callback", map[string]interface{}{})); resp == nil || resp.Deduplicated {
+		t.Errorf("Expected a submission to another topic to be posted, got %+v", resp)
+	}
+	// Tenants do not share entries
+	if resp, _ := client.ForTenant("acme").PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{})); resp == nil || resp.Deduplicated {
+		t.Errorf("Expected a submission for another tenant to be posted, got %+v", resp)
+	}
+
+	now = now.Add(30 * time.Second)
+	again, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if again.Deduplicated {
+		t.Error("Expected a submission after the window to be posted")
+	}
+	if got := posts.Load(); got != 4 {
+		t.Errorf("Expected 4 posts, got %d", got)
+	}
+}
+
+func TestDedupCacheConcurrent(t *testing.T) {
+	var posts atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", ItemID: "pr-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, DedupCache: &DedupCacheConfig{}})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var deduplicated atomic.Int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+			if err != nil {
+				t.Errorf("PostMessage failed: %v", err)
+				return
+			}
+			if resp.Deduplicated {
+				deduplicated.Add(1)
+			}
+		}()
+	}
+	for posts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Give the duplicates time to find the submission in flight
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if posts.Load() != 1 || deduplicated.Load() != 4 {
+		t.Errorf("Expected 1 post and 4 deduplicated responses, got %d and %d", posts.Load(), deduplicated.Load())
+	}
+}
+
+func TestDedupCacheSubmissionFinishingBeforeLock(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", ItemID: "pr-1"})
+	}))
+	defer server.Close()
+
+	store := &pausingDedupStore{MemoryDedupStore: NewMemoryDedupStore(), paused: make(chan struct{}), resume: make(chan struct{})}
+	client := NewClient(&Config{BaseURL: server.URL, DedupCache: &DedupCacheConfig{Store: store}})
+	ctx := context.Background()
+
+	// The first caller misses the store, then a second submission completes
+	// before the first caller checks for one in flight
+	first := make(chan *MessageResponse, 1)
+	go func() {
+		resp, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+		if err != nil {
+			t.Errorf("PostMessage failed: %v", err)
+		}
+		first <- resp
+	}()
+	<-store.paused
+
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{})); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	close(store.resume)
+
+	if resp := <-first; resp == nil || !resp.Deduplicated {
+		t.Errorf("Expected the first caller to find the stored submission, got %+v", resp)
+	}
+	if got := posts.Load(); got != 1 {
+		t.Errorf("Expected 1 post, got %d", got)
+	}
+}
+
+// pausingDedupStore holds its first Get after the lookup until resume is
+// closed, returning what it found then
+type pausingDedupStore struct {
+	*MemoryDedupStore
+	paused chan struct{}
+	resume chan struct{}
+	held   atomic.Bool
+}
+
+func (s *pausingDedupStore) Get(ctx context.Context, key string) (*DedupEntry, error) {
+	entry, err := s.MemoryDedupStore.Get(ctx, key)
+	if s.held.CompareAndSwap(false, true) {
+		close(s.paused)
+		<-s.resume
+	}
+	return entry, err
+}
+
+func TestDedupCacheSkipsFailures(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if posts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-2", ItemID: "pr-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, DedupCache: &DedupCacheConfig{Store: failingDedupStore{}}})
+	ctx := context.Background()
+
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{})); err == nil {
+		t.Fatal("Expected the first submission to fail")
+	}
+	resp, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	if err != nil || resp.Deduplicated {
+		t.Errorf("Expected a retry after a failure to be posted, got %+v, %v", resp, err)
+	}
+}
+
+// failingDedupStore is a store that is unavailable
+type failingDedupStore struct{}
+
+func (failingDedupStore) Get(ctx context.Context, key string) (*DedupEntry, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func (failingDedupStore) Put(ctx context.Context, key string, entry DedupEntry) error {
+	return errors.New("store unavailable")
+}
+
+func TestMemoryDedupStorePrunes(t *testing.T) {
+	store := NewMemoryDedupStore()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 8; i++ {
+		store.Put(ctx, string(rune('a'+i)), DedupEntry{SubmittedAt: start, ExpiresAt: start.Add(time.Minute)})
+	}
+	later := start.Add(2 * time.Minute)
+	for i := 0; i < 8; i++ {
+		store.Put(ctx, string(rune('A'+i)), DedupEntry{SubmittedAt: later, ExpiresAt: later.Add(time.Minute)})
+	}
+
+	if got := store.Len(); got > 8 {
+		t.Errorf("Expected expired entries to be dropped, got %d entries", got)
+	}
+	if entry, _ := store.Get(ctx, "H"); entry == nil {
+		t.Error("Expected a live entry to be kept")
+	}
+}
@@ -0,0 +1,193 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Defaults for draining workers
+const (
+	DefaultDrainTimeout      = 5 * time.Minute
+	DefaultDrainPollInterval = 2 * time.Second
+)
+
+// Worker statuses reported while draining
+const (
+	WorkerStatusDraining = "draining"
+	WorkerStatusDrained  = "drained"
+)
+
+// DrainOptions controls how workers are drained before removal
+type DrainOptions struct {
+	// Count is the number of workers to drain; zero drains every worker of the priority
+	Count int
+	// Timeout bounds the wait for in-flight messages; workers still busy
+	// afterwards are removed anyway. Defaults to DefaultDrainTimeout
+	Timeout time.Duration
+	// PollInterval is the delay between status checks; defaults to DefaultDrainPollInterval
+	PollInterval time.Duration
+}
+
+// WorkerDrainResult reports how a single worker was drained
+type WorkerDrainResult struct {
+	WorkerID string
+	// Drained is true when the worker finished its in-flight messages before the timeout
+	Drained bool
+	Removed bool
+	// Duration is the time from the drain request until the worker was drained or the timeout hit
+	Duration time.Duration
+	Error    string
+}
+
+// DrainReport summarizes a DrainWorkers call
+type DrainReport struct {
+	Priority string
+	Workers  []WorkerDrainResult
+	TimedOut bool
+	// StatusError is the last failed status check, when checks were still
+	// failing as the wait ended
+	StatusError string
+}
+
+// drainWorkersResponse represents the response from starting a drain
+type drainWorkersResponse struct {
+	Status  string   `json:"status"`
+	Workers []string `json:"workers"`
+}
+
+// DrainWorkers gracefully removes workers of a priority: the service stops
+// dispatching to the targeted workers, DrainWorkers waits until their
+// in-flight messages complete or the timeout elapses, and then removes them.
+// Status checks failing with a retryable error are retried until the
+// timeout; a check failing otherwise ends the wait early. Either way the
+// workers are still removed, and the failure is recorded in the report
+func (c *Client) DrainWorkers(ctx context.Context, priority string, opts DrainOptions) (*DrainReport, error) {
+	if err := validateWorkerPriority(priority); err != nil {
+		return nil, err
+	}
+
+	if opts.Count < 0 {
+		return nil, fmt.Errorf("count cannot be negative")
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultDrainTimeout
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultDrainPollInterval
+	}
+
+	path := "/api/v1/workers/drain/" + priority
+	if opts.Count > 0 {
+		path += fmt.Sprintf("?count=%d", opts.Count)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var started drainWorkersResponse
+	if err := c.parseResponse(resp, &started); err != nil {
+		return nil, err
+	}
+
+	report := &DrainReport{Priority: priority}
+	results := make(map[string]*WorkerDrainResult, len(started.Workers))
+	for _, id := range started.Workers {
+		report.Workers = append(report.Workers, WorkerDrainResult{WorkerID: id})
+	}
+	for i := range report.Workers {
+		results[report.Workers[i].WorkerID] = &report.Workers[i]
+	}
+
+	begin := c.clock.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	var statusErr error
+	err = c.poll(waitCtx, PollOptions{Interval: opts.PollInterval}, func() (bool, error) {
+		status, err := c.GetWorkerStatus(waitCtx)
+		if err != nil {
+			// A check cut short by the timeout is not a failure of its own
+			if waitCtx.Err() != nil {
+				return false, err
+			}
+			statusErr = err
+			if IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		statusErr = nil
+
+		present := make(map[string]string, len(status.AllWorkers))
+		for _, w := range status.AllWorkers {
+			present[w.ID] = w.Status
+		}
+
+		pending := 0
+		for id, result := range results {
+			if result.Drained {
+				continue
+			}
+			// A worker that disappeared has nothing left in flight
+			if workerStatus, ok := present[id]; !ok || workerStatus == WorkerStatusDrained {
+				result.Drained = true
+				result.Duration = c.clock.Now().Sub(begin)
+				continue
+			}
+			pending++
+		}
+		return pending == 0, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+		if waitCtx.Err() != nil {
+			report.TimedOut = true
+		}
+	}
+	if statusErr != nil {
+		report.StatusError = statusErr.Error()
+	}
+
+	for i := range report.Workers {
+		result := &report.Workers[i]
+		if !result.Drained {
+			result.Duration = c.clock.Now().Sub(begin)
+		}
+		if err := c.removeWorker(ctx, result.WorkerID); err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		result.Removed = true
+	}
+
+	return report, nil
+}
+
+// removeWorker removes a single worker by ID
+func (c *Client) removeWorker(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/workers/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.parseResponse(resp, nil); err != nil {
+		// Already gone counts as removed
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDrainWorkers(t *testing.T) {
+	var mu sync.Mutex
+	polls := 0
+	var removed []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/api/v1/workers/drain/high":
+			if r.URL.Query().Get("count") != "2" {
+				t.Errorf("Expected count '2', got '%s'", r.URL.Query().Get("count"))
+			}
+			json.NewEncoder(w).Encode(drainWorkersResponse{Status: "draining", Workers: []string{"high-1", "high-2"}})
+		case r.URL.Path == "/api/v1/workers/status":
+			polls++
+			high1 := WorkerStatusDraining
+			if polls >= 2 {
+				high1 = WorkerStatusDrained
+			}
+			json.NewEncoder(w).Encode(WorkerStatusResponse{AllWorkers: []WorkerInfo{
+				{ID: "high-1", Status: high1},
+				{ID: "high-2", Status: WorkerStatusDraining},
+			}})
+		case r.Method == http.MethodDelete:
+			removed = append(removed, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	report, err := client.DrainWorkers(context.Background(), "high", DrainOptions{
+		Count:        2,
+		Timeout:      50 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !report.TimedOut {
+		t.Error("Expected drain to time out waiting for high-2")
+	}
+	if len(report.Workers) != 2 {
+		t.Fatalf("Expected 2 worker results, got %d", len(report.Workers))
+	}
+	if !report.Workers[0].Drained || report.Workers[1].Drained {
+		t.Errorf("Expected only high-1 to drain, got %+v", report.Workers)
+	}
+	if !report.Workers[0].Removed || !report.Workers[1].Removed {
+		t.Errorf("Expected both workers to be removed, got %+v", report.Workers)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Expected 2 removals, got %v", removed)
+	}
+}
+
+func TestDrainWorkersStatusErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantDrained bool
+		wantError   bool
+	}{
+		{"transient failure is retried", http.StatusServiceUnavailable, true, false},
+		{"permanent failure still removes", http.StatusForbidden, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			polls := 0
+			var removed []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch {
+				case r.URL.Path == "/api/v1/workers/drain/low":
+					json.NewEncoder(w).Encode(drainWorkersResponse{Status: "draining", Workers: []string{"low-1"}})
+				case r.URL.Path == "/api/v1/workers/status":
+					polls++
+					if polls == 1 {
+						w.WriteHeader(tt.status)
+						return
+					}
+					json.NewEncoder(w).Encode(WorkerStatusResponse{AllWorkers: []WorkerInfo{{ID: "low-1", Status: WorkerStatusDrained}}})
+				case r.Method == http.MethodDelete:
+					removed = append(removed, r.URL.Path)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&Config{BaseURL: server.URL})
+			report, err := client.DrainWorkers(context.Background(), "low", DrainOptions{
+				Timeout:      time.Second,
+				PollInterval: 5 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if report.Workers[0].Drained != tt.wantDrained || (report.StatusError != "") != tt.wantError {
+				t.Errorf("Unexpected report %+v", report)
+			}
+			if len(removed) != 1 || !report.Workers[0].Removed {
+				t.Errorf("Expected the worker to be removed, got %v", removed)
+			}
+		})
+	}
+}
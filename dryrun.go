@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DryRunResult is the service's verdict on a message it was asked to
+// validate without enqueueing
+type DryRunResult struct {
+	ItemID string `json:"item_id"`
+	Valid  bool   `json:"valid"`
+	// Violations lists the server-side rules the message breaks
+	Violations []FieldViolation `json:"violations,omitempty"`
+	// Queue, Priority, and Topic are where the message would be routed,
+	// after the service applied its own defaults
+	Queue    string   `json:"queue,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+	Topic    Topic    `json:"topic,omitempty"`
+}
+
+// Err returns the violations as a *ValidationError, or nil when the message
+// is valid
+func (r *DryRunResult) Err() error {
+	if r.Valid {
+		return nil
+	}
+	verr := &ValidationError{Violations: r.Violations}
+	if len(verr.Violations) == 0 {
+		verr.add("message", "rejected by the service")
+	}
+	return verr
+}
+
+// BulkDryRunResult is the service's verdict on a bulk request it was asked
+// to validate without enqueueing
+type BulkDryRunResult struct {
+	Valid   bool           `json:"valid"`
+	Results []DryRunResult `json:"results"`
+}
+
+// Invalid returns the results of the messages the service would reject
+func (r *BulkDryRunResult) Invalid() []DryRunResult {
+	var invalid []DryRunResult
+	for _, result := range r.Results {
+		if !result.Valid {
+			invalid = append(invalid, result)
+		}
+	}
+	return invalid
+}
+
+// dryRunQuery is the validate-only flag of submission endpoints
+const dryRunQuery = "?dry_run=true"
+
+// isDryRun reports whether path asks for validation only; dry-run results
+// are not message responses, so they are decoded as sent
+func isDryRun(path string) bool {
+	return strings.HasSuffix(path, dryRunQuery)
+}
+
+// DryRunMessage checks req against the service's rules and reports where it
+// would be routed, without enqueueing it. The request gets the same defaults
+// as in PostMessage and is validated client-side first, so a *ValidationError
+// means it never reached the service. Payloads are encrypted with the
+// client's PayloadEncrypter but not offloaded to its PayloadStore, and
+// backpressure does not apply
+func (c *Client) DryRunMessage(ctx context.Context, req *MessageRequest) (*DryRunResult, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	req = c.withDefaults(ctx, req)
+	if err := c.encryptPayload(ctx, "", req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages"+dryRunQuery, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result DryRunResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DryRunBulkMessages checks every message of req like DryRunMessage, without
+// enqueueing any
+func (c *Client) DryRunBulkMessages(ctx context.Context, req *BulkMessageRequest) (*BulkDryRunResult, error) {
+	if req == nil {
+		return nil, fmt.Errorf("bulk message request cannot be nil")
+	}
+
+	if len(req.Messages) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	req = c.withBulkDefaults(ctx, req)
+	for i := range req.Messages {
+		if err := c.encryptPayload(ctx, fmt.Sprintf("messages[%d].", i), &req.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/bulk"+dryRunQuery, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result BulkDryRunResult
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
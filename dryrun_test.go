@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDryRunMessage(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/messages" || r.URL.Query().Get("dry_run") != "true" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"item_id":"pr-1","valid":true,"queue":"pullrequests-high","priority":"high","topic":"pullrequests"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	client.SetTopicDefaults(TopicPullRequests, TopicDefaults{Priority: PriorityHigh})
+	req := newMessageRequest("pr-1", "", TopicPullRequests, "https://example.com/callback", map[string]int{"number": 1})
+	result, err := client.DryRunMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DryRunMessage failed: %v", err)
+	}
+
+	if received.Priority != PriorityHigh {
+		t.Errorf("Expected topic defaults to apply, got priority '%s'", received.Priority)
+	}
+	if !result.Valid || result.Queue != "pullrequests-high" || result.Priority != PriorityHigh || result.ItemID != "pr-1" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if err := result.Err(); err != nil {
+		t.Errorf("Expected no error for a valid message, got %v", err)
+	}
+}
+
+func TestDryRunMessageInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"item_id":"pr-1","valid":false,"violations":[{"field":"object_body.repo","message":"is required"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]int{"number": 1})
+	result, err := client.DryRunMessage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DryRunMessage failed: %v", err)
+	}
+
+	var verr *ValidationError
+	if !errors.As(result.Err(), &verr) || len(verr.Violations) != 1 || verr.Violations[0].Field != "object_body.repo" {
+		t.Errorf("Expected the service's violations, got %v", result.Err())
+	}
+}
+
+func TestDryRunMessageClientValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	_, err := client.DryRunMessage(context.Background(), newMessageRequest("", "urgent", TopicPullRequests, "", nil))
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request, got %d", requests)
+	}
+}
+
+func TestDryRunBulkMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/messages/bulk" || r.URL.Query().Get("dry_run") != "true" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"data":{"valid":false,"results":[
+			{"item_id":"pr-1","valid":true,"queue":"pullrequests-high","priority":"high","topic":"pullrequests"},
+			{"item_id":"pr-2","valid":false,"violations":[{"field":"callback_url","message":"host is not allowed"}]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionV2})
+	result, err := client.DryRunBulkMessages(context.Background(), &BulkMessageRequest{Messages: []MessageRequest{
+		*newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+		*newMessageRequest("pr-2", PriorityHigh, TopicPullRequests, "https://internal.example.com/callback", nil),
+	}})
+	if err != nil {
+		t.Fatalf("DryRunBulkMessages failed: %v", err)
+	}
+
+	if result.Valid || len(result.Results) != 2 || result.Results[0].Queue != "pullrequests-high" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	invalid := result.Invalid()
+	if len(invalid) != 1 || invalid[0].ItemID != "pr-2" {
+		t.Errorf("Expected pr-2 to be invalid, got %+v", invalid)
+	}
+}
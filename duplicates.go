@@ -0,0 +1,215 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// IdempotencyKeyHeader carries the item ID of idempotent submissions, letting
+// the service reject a concurrent duplicate with 409 Conflict
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrDuplicateMessage is matched, through errors.Is, by the
+// *DuplicateMessageError returned by PostMessageIdempotent
+var ErrDuplicateMessage = errors.New("message already exists")
+
+// DuplicateMessageError is returned by PostMessageIdempotent when a message
+// with the same item ID already exists
+type DuplicateMessageError struct {
+	ItemID string
+	// MessageID is the ID of the existing message; it is empty when the
+	// service rejected the submission but the existing message could not be
+	// looked up
+	MessageID string
+	Status    MessageStatus
+	// SameContent reports whether the existing message has the same body
+	SameContent bool
+}
+
+func (e *DuplicateMessageError) Error() string {
+	if e.MessageID == "" {
+		return fmt.Sprintf("message for item '%s' already exists", e.ItemID)
+	}
+	return fmt.Sprintf("message for item '%s' already exists as %s (%s)", e.ItemID, e.MessageID, e.Status)
+}
+
+// Is lets the error match ErrDuplicateMessage
+func (e *DuplicateMessageError) Is(target error) bool {
+	return target == ErrDuplicateMessage
+}
+
+// DuplicateCandidate identifies a message whose existence is being checked
+type DuplicateCandidate struct {
+	ItemID string `json:"item_id"`
+	Topic  Topic  `json:"topic"`
+	// ContentHash is the SHA-256 of the marshaled ObjectBody, letting the
+	// service tell a resubmission apart from a changed message
+	ContentHash string `json:"content_hash,omitempty"`
+}
+
+// DuplicateMessage describes a candidate that already exists on the service
+type DuplicateMessage struct {
+	// Index is the position of the candidate in the checked slice
+	Index     int           `json:"index"`
+	ItemID    string        `json:"item_id"`
+	MessageID string        `json:"message_id"`
+	Status    MessageStatus `json:"status"`
+	// SameContent reports whether the existing message has the same body
+	SameContent bool `json:"same_content"`
+}
+
+// DuplicateReport lists which candidates already exist
+type DuplicateReport struct {
+	Duplicates []DuplicateMessage `json:"duplicates"`
+}
+
+// IsDuplicate reports whether the candidate at index i already exists
+func (r *DuplicateReport) IsDuplicate(i int) bool {
+	for _, d := range r.Duplicates {
+		if d.Index == i {
+			return true
+		}
+	}
+	return false
+}
+
+// Unique returns the messages of reqs that are not reported as duplicates,
+// preserving their order; reqs must be the slice that was checked
+func (r *DuplicateReport) Unique(reqs []MessageRequest) []MessageRequest {
+	duplicate := make(map[int]bool, len(r.Duplicates))
+	for _, d := range r.Duplicates {
+		duplicate[d.Index] = true
+	}
+
+	unique := make([]MessageRequest, 0, len(reqs))
+	for i, req := range reqs {
+		if !duplicate[i] {
+			unique = append(unique, req)
+		}
+	}
+	return unique
+}
+
+// duplicatesRequest is the body of a duplicate check
+type duplicatesRequest struct {
+	Candidates []DuplicateCandidate `json:"candidates"`
+	// WindowMs limits the check to messages submitted that recently
+	WindowMs int64 `json:"window_ms,omitempty"`
+}
+
+// CheckDuplicates asks the service which of reqs already exist, queued or
+// processed, so that backfills can skip resubmitting them. Only item IDs,
+// topics, and content hashes are sent, not the message bodies. With
+// Config.DedupWindow set, only messages submitted within the window count
+func (c *Client) CheckDuplicates(ctx context.Context, reqs []MessageRequest) (*DuplicateReport, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("no messages provided")
+	}
+
+	body := duplicatesRequest{
+		Candidates: make([]DuplicateCandidate, len(reqs)),
+		WindowMs:   c.dedupWindow.Milliseconds(),
+	}
+	for i, req := range reqs {
+		if req.ItemID == "" {
+			return nil, fmt.Errorf("messages[%d]: item_id is required", i)
+		}
+		if req.Topic == "" {
+			return nil, fmt.Errorf("messages[%d]: topic is required", i)
+		}
+
+		hash, err := contentHash(req.ObjectBody)
+		if err != nil {
+			return nil, fmt.Errorf("messages[%d]: %w", i, err)
+		}
+		body.Candidates[i] = DuplicateCandidate{ItemID: req.ItemID, Topic: req.Topic, ContentHash: hash}
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/duplicates", body)
+	if err != nil {
+		return nil, err
+	}
+
+	var report DuplicateReport
+	if err := c.parseResponse(resp, &report); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// PostMessageIdempotent submits req unless a message with the same item ID
+// already exists, making retries of a failed submission safe. It first
+// checks for an existing message and then submits with the item ID as
+// IdempotencyKeyHeader, so that a duplicate submitted between the check and
+// the submission is rejected by the service. An existing message is
+// reported as a *DuplicateMessageError, which matches ErrDuplicateMessage
+func (c *Client) PostMessageIdempotent(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	req = c.withDefaults(ctx, req)
+	dup, err := c.findDuplicate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if dup != nil {
+		return nil, dup
+	}
+
+	resp, err := c.PostMessage(withIdempotencyKey(ctx, req.ItemID), req)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		// Lost a race with another submission; report the winner if it can be found
+		if dup, _ := c.findDuplicate(ctx, req); dup != nil {
+			return nil, dup
+		}
+		return nil, &DuplicateMessageError{ItemID: req.ItemID}
+	}
+	return resp, err
+}
+
+// findDuplicate returns the existing message for req's item ID, if any
+func (c *Client) findDuplicate(ctx context.Context, req *MessageRequest) (*DuplicateMessageError, error) {
+	report, err := c.CheckDuplicates(ctx, []MessageRequest{*req})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range report.Duplicates {
+		if d.Index == 0 {
+			return &DuplicateMessageError{ItemID: req.ItemID, MessageID: d.MessageID, Status: d.Status, SameContent: d.SameContent}, nil
+		}
+	}
+	return nil, nil
+}
+
+type idempotencyKey struct{}
+
+// withIdempotencyKey returns a context whose requests carry key as
+// IdempotencyKeyHeader
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// setIdempotencyHeader adds the context's idempotency key to header
+func setIdempotencyHeader(ctx context.Context, header http.Header) {
+	if key, _ := ctx.Value(idempotencyKey{}).(string); key != "" {
+		header.Set(IdempotencyKeyHeader, key)
+	}
+}
+
+// contentHash returns the hex SHA-256 of the marshaled object body
+func contentHash(objectBody interface{}) (string, error) {
+	data, err := json.Marshal(objectBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal object body: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
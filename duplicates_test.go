@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckDuplicates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/messages/duplicates" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req duplicatesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if len(req.Candidates) != 3 {
+			t.Fatalf("Expected 3 candidates, got %d", len(req.Candidates))
+		}
+		if req.Candidates[0].ContentHash == "" || req.Candidates[0].ContentHash == req.Candidates[1].ContentHash {
+			t.Errorf("Expected distinct content hashes, got %+v", req.Candidates)
+		}
+
+		json.NewEncoder(w).Encode(DuplicateReport{Duplicates: []DuplicateMessage{
+			{Index: 1, ItemID: "pr-2", MessageID: "msg-2", Status: "queued", SameContent: true},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	reqs := []MessageRequest{
+		{ItemID: "pr-1", Topic: TopicPullRequests, ObjectBody: map[string]int{"n": 1}},
+		{ItemID: "pr-2", Topic: TopicPullRequests, ObjectBody: map[string]int{"n": 2}},
+		{ItemID: "pr-3", Topic: TopicPullRequests},
+	}
+
+	report, err := client.CheckDuplicates(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !report.IsDuplicate(1) || report.IsDuplicate(0) {
+		t.Errorf("Unexpected duplicates: %+v", report.Duplicates)
+	}
+
+	unique := report.Unique(reqs)
+	if len(unique) != 2 || unique[0].ItemID != "pr-1" || unique[1].ItemID != "pr-3" {
+		t.Errorf("Unexpected unique messages: %+v", unique)
+	}
+
+	if _, err := client.CheckDuplicates(context.Background(), []MessageRequest{{Topic: TopicPullRequests}}); err == nil {
+		t.Error("Expected error for missing item_id, got nil")
+	}
+}
+
+func TestPostMessageIdempotent(t *testing.T) {
+	existing := map[string]string{"pr-1": "msg-1"}
+	conflict := false
+	var window int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/messages/duplicates":
+			var req duplicatesRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			window = req.WindowMs
+			var report DuplicateReport
+			for i, c := range req.Candidates {
+				if id, ok := existing[c.ItemID]; ok {
+					report.Duplicates = append(report.Duplicates, DuplicateMessage{Index: i, ItemID: c.ItemID, MessageID: id, Status: "queued"})
+				}
+			}
+			json.NewEncoder(w).Encode(report)
+		case "/api/v1/messages":
+			var req MessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if got := r.Header.Get(IdempotencyKeyHeader); got != req.ItemID {
+				t.Errorf("Expected idempotency key '%s', got '%s'", req.ItemID, got)
+			}
+			if conflict {
+				// Another producer submitted the item in the meantime
+				existing[req.ItemID] = "msg-race"
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":{"code":"conflict","message":"duplicate item"}}`))
+				return
+			}
+			json.NewEncoder(w).Encode(MessageResponse{ID: "msg-new", ItemID: req.ItemID})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, DedupWindow: time.Hour})
+	ctx := context.Background()
+
+	_, err := client.PostMessageIdempotent(ctx, newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil))
+	var dupErr *DuplicateMessageError
+	if !errors.Is(err, ErrDuplicateMessage) || !errors.As(err, &dupErr) || dupErr.MessageID != "msg-1" {
+		t.Fatalf("Expected duplicate of msg-1, got %v", err)
+	}
+	if window != time.Hour.Milliseconds() {
+		t.Errorf("Expected dedup window to be sent, got %d", window)
+	}
+
+	resp, err := client.PostMessageIdempotent(ctx, newMessageRequest("pr-2", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil))
+	if err != nil || resp.ID != "msg-new" {
+		t.Fatalf("Expected new message, got %+v, %v", resp, err)
+	}
+
+	conflict = true
+	_, err = client.PostMessageIdempotent(ctx, newMessageRequest("pr-3", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil))
+	if !errors.As(err, &dupErr) || dupErr.MessageID != "msg-race" {
+		t.Errorf("Expected duplicate of msg-race after conflict, got %v", err)
+	}
+
+	// Plain submissions carry no idempotency key
+	conflict = false
+	existing = map[string]string{}
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(IdempotencyKeyHeader) != "" {
+			t.Error("Expected no idempotency key on plain submission")
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-plain"})
+	})
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-4", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
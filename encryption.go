@@ -0,0 +1,281 @@
+package sdk
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// EncryptionMetadataKey is the metadata key set on messages whose ObjectBody
+// was encrypted; its value is the payload's algorithm. Workers echo it on
+// callbacks as an X-Meta-Payload-Encryption header
+const EncryptionMetadataKey = "payload-encryption"
+
+// AlgorithmAES256GCM is the algorithm of payloads encrypted by
+// AESGCMEncrypter
+const AlgorithmAES256GCM = "aes-256-gcm"
+
+// ErrDecryptionFailed is returned when an encrypted payload cannot be
+// decrypted, because it was tampered with or its key is unavailable
+var ErrDecryptionFailed = errors.New("payload decryption failed")
+
+// ErrInvalidEncryptedPayload is returned by DecryptPayload for data that is
+// not an EncryptedPayload
+var ErrInvalidEncryptedPayload = errors.New("invalid encrypted payload")
+
+// EncryptedPayload replaces an ObjectBody encrypted by a PayloadEncrypter
+type EncryptedPayload struct {
+	Algorithm string `json:"alg"`
+	// KeyID identifies the key-encryption key that wrapped the data key
+	KeyID string `json:"kid"`
+	// WrappedKey is the data key, encrypted by the key provider
+	WrappedKey []byte `json:"wrapped_key"`
+	Nonce      []byte `json:"nonce"`
+	// Ciphertext is the sealed JSON of the original ObjectBody
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// PayloadEncrypter encrypts the marshaled ObjectBody of messages before they
+// are submitted, so that sensitive payloads are not stored in the queue in
+// plaintext
+type PayloadEncrypter interface {
+	Encrypt(ctx context.Context, plaintext []byte) (*EncryptedPayload, error)
+}
+
+// PayloadDecrypter recovers the ObjectBody sealed by a PayloadEncrypter
+type PayloadDecrypter interface {
+	Decrypt(ctx context.Context, payload *EncryptedPayload) ([]byte, error)
+}
+
+// DataKey is a key generated for encrypting a single payload
+type DataKey struct {
+	// KeyID identifies the key-encryption key that wrapped the data key
+	KeyID string
+	// Plaintext is the 32-byte AES key used to seal the payload
+	Plaintext []byte
+	// Wrapped is Plaintext encrypted by the key provider, stored with the
+	// payload
+	Wrapped []byte
+}
+
+// KeyProvider generates and unwraps data keys for envelope encryption,
+// typically backed by a KMS such as AWS KMS or Cloud KMS
+type KeyProvider interface {
+	GenerateDataKey(ctx context.Context) (*DataKey, error)
+	DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// AESGCMEncrypter seals each payload with AES-256-GCM under a fresh data key
+// from its KeyProvider, storing the wrapped key alongside the ciphertext. It
+// implements both PayloadEncrypter and PayloadDecrypter
+type AESGCMEncrypter struct {
+	keys KeyProvider
+}
+
+// NewAESGCMEncrypter returns an encrypter using keys for its data keys
+func NewAESGCMEncrypter(keys KeyProvider) *AESGCMEncrypter {
+	return &AESGCMEncrypter{keys: keys}
+}
+
+// Encrypt seals plaintext under a new data key
+func (e *AESGCMEncrypter) Encrypt(ctx context.Context, plaintext []byte) (*EncryptedPayload, error) {
+	key, err := e.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	nonce, ciphertext, err := sealAESGCM(key.Plaintext, plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedPayload{
+		Algorithm:  AlgorithmAES256GCM,
+		KeyID:      key.KeyID,
+		WrappedKey: key.Wrapped,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Decrypt unwraps the payload's data key and opens its ciphertext
+func (e *AESGCMEncrypter) Decrypt(ctx context.Context, payload *EncryptedPayload) ([]byte, error) {
+	if payload.Algorithm != AlgorithmAES256GCM {
+		return nil, fmt.Errorf("%w: unsupported algorithm '%s'", ErrDecryptionFailed, payload.Algorithm)
+	}
+
+	key, err := e.keys.DecryptDataKey(ctx, payload.KeyID, payload.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unwrap data key: %v", ErrDecryptionFailed, err)
+	}
+	return openAESGCM(key, payload.Nonce, payload.Ciphertext)
+}
+
+// StaticKeyProvider wraps data keys with a fixed AES-256 key held in memory,
+// for tests and deployments without a KMS
+type StaticKeyProvider struct {
+	keyID string
+	key   []byte
+}
+
+// NewStaticKeyProvider returns a provider wrapping data keys with key, which
+// must be 32 bytes, under the ID keyID
+func NewStaticKeyProvider(keyID string, key []byte) (*StaticKeyProvider, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key id is required")
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return &StaticKeyProvider{keyID: keyID, key: key}, nil
+}
+
+// GenerateDataKey returns a random data key wrapped with the static key
+func (p *StaticKeyProvider) GenerateDataKey(ctx context.Context) (*DataKey, error) {
+	plaintext := make([]byte, 32)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, err
+	}
+
+	nonce, wrapped, err := sealAESGCM(p.key, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{KeyID: p.keyID, Plaintext: plaintext, Wrapped: append(nonce, wrapped...)}, nil
+}
+
+// DecryptDataKey unwraps a data key wrapped by GenerateDataKey
+func (p *StaticKeyProvider) DecryptDataKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("unknown key id '%s'", keyID)
+	}
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	return openAESGCM(p.key, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():])
+}
+
+// newGCM returns an AES-GCM cipher for key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealAESGCM encrypts plaintext under key with a random nonce
+func sealAESGCM(key, plaintext []byte) (nonce, ciphertext []byte, err error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// openAESGCM decrypts and authenticates ciphertext sealed by sealAESGCM
+func openAESGCM(key, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid nonce", ErrDecryptionFailed)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	return plaintext, nil
+}
+
+// DecryptPayload opens data, the JSON of an EncryptedPayload, with dec and
+// returns the original ObjectBody JSON, e.g. for a pull consumer or a
+// callback carrying EncryptionMetadataKey
+func DecryptPayload(ctx context.Context, dec PayloadDecrypter, data []byte) ([]byte, error) {
+	var payload EncryptedPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncryptedPayload, err)
+	}
+	if payload.Algorithm == "" || payload.Ciphertext == nil {
+		return nil, fmt.Errorf("%w: algorithm and ciphertext are required", ErrInvalidEncryptedPayload)
+	}
+	return dec.Decrypt(ctx, &payload)
+}
+
+// encryptPayload replaces the ObjectBody of req with an *EncryptedPayload
+// when the client has a PayloadEncrypter, and records the algorithm under
+// EncryptionMetadataKey. The body is checked against its topic validator
+// first since the ciphertext no longer can be; violations are reported with
+// field names prefixed by prefix. req must be a copy owned by the caller
+func (c *Client) encryptPayload(ctx context.Context, prefix string, req *MessageRequest) error {
+	if c.encrypter == nil || req.ObjectBody == nil || isEncryptedPayload(req.ObjectBody) || isPayloadReference(req.ObjectBody) {
+		return nil
+	}
+
+	if validator := topicValidator(req.Topic); validator != nil {
+		if err := validator.ValidateBody(req.ObjectBody); err != nil {
+			verr := &ValidationError{}
+			verr.add(prefix+"object_body", "%v", err)
+			return verr
+		}
+	}
+
+	data, err := json.Marshal(req.ObjectBody)
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add(prefix+"object_body", "cannot be marshaled: %v", err)
+		return verr
+	}
+
+	payload, err := c.encrypter.Encrypt(ctx, data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt payload: %w", err)
+	}
+
+	req.ObjectBody = payload
+	req.Metadata = maps.Clone(req.Metadata)
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string, 1)
+	}
+	req.Metadata[EncryptionMetadataKey] = payload.Algorithm
+	return nil
+}
+
+// preparePayload encrypts and then, if it is too large, offloads the
+// ObjectBody of req, so that payload stores only hold ciphertext
+func (c *Client) preparePayload(ctx context.Context, prefix string, req *MessageRequest) error {
+	if err := c.encryptPayload(ctx, prefix, req); err != nil {
+		return err
+	}
+	return c.offloadPayload(ctx, prefix, req)
+}
+
+// isEncryptedPayload reports whether body is an encrypted payload, including
+// one decoded generically, as when an outbox is reloaded from disk
+func isEncryptedPayload(body interface{}) bool {
+	switch b := body.(type) {
+	case *EncryptedPayload, EncryptedPayload:
+		return true
+	case map[string]interface{}:
+		_, hasAlg := b["alg"]
+		_, hasCiphertext := b["ciphertext"]
+		_, hasKey := b["wrapped_key"]
+		return hasAlg && hasCiphertext && hasKey
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestEncrypter(t *testing.T) *AESGCMEncrypter {
+	t.Helper()
+	keys, err := NewStaticKeyProvider("test-key", bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewStaticKeyProvider failed: %v", err)
+	}
+	return NewAESGCMEncrypter(keys)
+}
+
+func TestPayloadEncryption(t *testing.T) {
+	var received map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id":"msg-1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	encrypter := newTestEncrypter(t)
+	client := NewClient(&Config{BaseURL: server.URL, PayloadEncrypter: encrypter})
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", map[string]string{"token": "s3cret"})
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	if strings.Contains(string(received["object_body"]), "s3cret") {
+		t.Fatal("Expected the payload not to be sent in plaintext")
+	}
+	var metadata map[string]string
+	json.Unmarshal(received["metadata"], &metadata)
+	if metadata[EncryptionMetadataKey] != AlgorithmAES256GCM {
+		t.Errorf("Expected the encryption metadata, got %v", metadata)
+	}
+	if req.Metadata != nil {
+		t.Error("Expected caller's request to be untouched")
+	}
+
+	plaintext, err := DecryptPayload(context.Background(), encrypter, received["object_body"])
+	if err != nil {
+		t.Fatalf("DecryptPayload failed: %v", err)
+	}
+	if string(plaintext) != `{"token":"s3cret"}` {
+		t.Errorf("Unexpected plaintext %s", plaintext)
+	}
+}
+
+func TestPayloadDecryptionFailures(t *testing.T) {
+	ctx := context.Background()
+	encrypter := newTestEncrypter(t)
+	payload, err := encrypter.Encrypt(ctx, []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	tampered := *payload
+	tampered.Ciphertext = append([]byte{}, payload.Ciphertext...)
+	tampered.Ciphertext[0] ^= 1
+	if _, err := encrypter.Decrypt(ctx, &tampered); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("Expected ErrDecryptionFailed for a tampered payload, got %v", err)
+	}
+
+	otherKey := *payload
+	otherKey.KeyID = "rotated-key"
+	if _, err := encrypter.Decrypt(ctx, &otherKey); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("Expected ErrDecryptionFailed for an unknown key, got %v", err)
+	}
+
+	if _, err := DecryptPayload(ctx, encrypter, []byte(`{"token":"plain"}`)); !errors.Is(err, ErrInvalidEncryptedPayload) {
+		t.Errorf("Expected ErrInvalidEncryptedPayload, got %v", err)
+	}
+	if _, err := NewStaticKeyProvider("short", []byte("too short")); err == nil {
+		t.Error("Expected an error for a short key")
+	}
+}
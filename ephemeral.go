@@ -0,0 +1,180 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for ephemeral callback endpoints
+const (
+	DefaultCallbackListenAddr = "127.0.0.1:0"
+	DefaultCallbackBuffer     = 16
+)
+
+// EphemeralCallbackConfig configures the endpoints started by NewEphemeralCallback
+type EphemeralCallbackConfig struct {
+	// ListenAddr is the local address to listen on; the default picks a
+	// free port on the loopback interface
+	ListenAddr string
+	// PublicURL is the base URL the service uses to reach the listener, e.g.
+	// a tunnel or load balancer; it defaults to the listener's address
+	PublicURL string
+	// Buffer is the number of callbacks held until they are received
+	Buffer int
+}
+
+// CallbackResult is a single callback delivered to an ephemeral endpoint
+type CallbackResult struct {
+	Header     http.Header
+	Body       []byte
+	ReceivedAt time.Time
+}
+
+// Decode unmarshals the callback body into v
+func (r CallbackResult) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// EphemeralCallback is a short-lived callback endpoint whose deliveries are
+// exposed on a channel. It is meant for scripts and tests
+type EphemeralCallback struct {
+	url     string
+	path    string
+	server  *http.Server
+	results chan CallbackResult
+	clock   Clock
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewEphemeralCallback starts a callback endpoint with a unique URL and
+// returns it. The endpoint is closed when ctx is done or Close is called
+func (c *Client) NewEphemeralCallback(ctx context.Context) (*EphemeralCallback, error) {
+	config := EphemeralCallbackConfig{}
+	if c.callbackConfig != nil {
+		config = *c.callbackConfig
+	}
+	if config.ListenAddr == "" {
+		config.ListenAddr = DefaultCallbackListenAddr
+	}
+	if config.Buffer <= 0 {
+		config.Buffer = DefaultCallbackBuffer
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate callback token: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", config.ListenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for callbacks: %w", err)
+	}
+
+	base := strings.TrimSuffix(config.PublicURL, "/")
+	if base == "" {
+		base = "http://" + listener.Addr().String()
+	}
+
+	e := &EphemeralCallback{
+		path:    "/callbacks/" + hex.EncodeToString(token),
+		results: make(chan CallbackResult, config.Buffer),
+		clock:   c.clock,
+	}
+	e.url = base + e.path
+	e.server = &http.Server{Handler: e, ReadHeaderTimeout: 10 * time.Second}
+
+	go e.server.Serve(listener)
+	go func() {
+		<-ctx.Done()
+		e.Close()
+	}()
+
+	return e, nil
+}
+
+// URL returns the callback URL to put on messages
+func (e *EphemeralCallback) URL() string {
+	return e.url
+}
+
+// Results returns the channel of received callbacks; it is closed by Close
+func (e *EphemeralCallback) Results() <-chan CallbackResult {
+	return e.results
+}
+
+// Wait returns the next callback or the context's error
+func (e *EphemeralCallback) Wait(ctx context.Context) (CallbackResult, error) {
+	select {
+	case <-ctx.Done():
+		return CallbackResult{}, ctx.Err()
+	case result, ok := <-e.results:
+		if !ok {
+			return CallbackResult{}, fmt.Errorf("callback endpoint closed")
+		}
+		return result, nil
+	}
+}
+
+// Close stops the endpoint and closes the results channel; it is safe to
+// call more than once
+func (e *EphemeralCallback) Close() error {
+	e.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		e.closeErr = e.server.Shutdown(ctx)
+
+		e.mu.Lock()
+		e.closed = true
+		close(e.results)
+		e.mu.Unlock()
+	})
+	return e.closeErr
+}
+
+// ServeHTTP accepts callbacks posted to the endpoint's path. When the buffer
+// is full it answers 503 so that the worker retries later
+func (e *EphemeralCallback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != e.path {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 2*MaxPayloadSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result := CallbackResult{Header: r.Header.Clone(), Body: body, ReceivedAt: e.clock.Now()}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case e.results <- result:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEphemeralCallback(t *testing.T) {
+	client := NewClient(&Config{EphemeralCallback: &EphemeralCallbackConfig{Buffer: 1}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	callback, err := client.NewEphemeralCallback(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer callback.Close()
+
+	if !strings.HasPrefix(callback.URL(), "http://127.0.0.1:") {
+		t.Errorf("Unexpected callback URL '%s'", callback.URL())
+	}
+	if err := validateCallbackURL(callback.URL()); err != nil {
+		t.Errorf("Expected a valid callback URL, got %v", err)
+	}
+
+	resp, err := http.Post(callback.URL(), "application/json", strings.NewReader(`{"status":"completed"}`))
+	if err != nil {
+		t.Fatalf("Failed to post callback: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", resp.StatusCode)
+	}
+
+	// The buffer is full until the first callback is received
+	resp, err = http.Post(callback.URL(), "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to post callback: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", resp.StatusCode)
+	}
+
+	result, err := callback.Wait(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var body struct{ Status string }
+	if err := result.Decode(&body); err != nil || body.Status != "completed" {
+		t.Errorf("Unexpected callback body '%s'", result.Body)
+	}
+
+	// Other paths on the listener are not exposed
+	base := strings.TrimSuffix(callback.URL(), callback.path)
+	resp, err = http.Post(base+"/callbacks/guess", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to post callback: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", resp.StatusCode)
+	}
+
+	if err := callback.Close(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, ok := <-callback.Results(); ok {
+		t.Error("Expected results channel to be closed")
+	}
+}
@@ -0,0 +1,184 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Message event types emitted by the service
+const (
+	MessageEventQueued            = "queued"
+	MessageEventStarted           = "started"
+	MessageEventCompleted         = "completed"
+	MessageEventFailed            = "failed"
+	MessageEventCallbackDelivered = "callback_delivered"
+	MessageEventTimedOut          = string(StatusTimedOut)
+	MessageEventExpired           = string(StatusExpired)
+)
+
+// MessageEvent represents a status change of a message
+type MessageEvent struct {
+	// EventID is the stream position used to resume after reconnecting
+	EventID   string          `json:"event_id"`
+	Type      string          `json:"type"`
+	MessageID string          `json:"message_id"`
+	ItemID    string          `json:"item_id"`
+	Priority  Priority        `json:"priority"`
+	Topic     Topic           `json:"topic"`
+	Status    MessageStatus   `json:"status"`
+	Attempt   int             `json:"attempt,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Timestamp string          `json:"timestamp"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	// CorrelationID is the correlation ID of the event's message
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// TimedOut reports whether the event records a processing timeout
+func (e *MessageEvent) TimedOut() bool {
+	return e.Type == MessageEventTimedOut || e.Status == StatusTimedOut
+}
+
+// Expired reports whether the event records a message expiring unprocessed
+func (e *MessageEvent) Expired() bool {
+	return e.Type == MessageEventExpired || e.Status == StatusExpired
+}
+
+// MessageEventFilter narrows the events delivered by SubscribeMessageEvents;
+// empty fields match everything
+type MessageEventFilter struct {
+	Topic    Topic
+	Priority Priority
+	ItemID   string
+	Types    []string
+}
+
+// query encodes the filter as URL query parameters
+func (f MessageEventFilter) query() url.Values {
+	q := url.Values{}
+	if f.Topic != "" {
+		q.Set("topic", string(f.Topic))
+	}
+	if f.Priority != "" {
+		q.Set("priority", string(f.Priority))
+	}
+	if f.ItemID != "" {
+		q.Set("item_id", f.ItemID)
+	}
+	if len(f.Types) > 0 {
+		q.Set("types", strings.Join(f.Types, ","))
+	}
+	return q
+}
+
+// SubscribeMessageEvents opens the service's message event stream and
+// delivers events on the returned channel. Dropped connections are
+// re-established with backoff and resume after the last received event. The
+// channel is closed when ctx is done or the service rejects the subscription
+func (c *Client) SubscribeMessageEvents(ctx context.Context, filter MessageEventFilter) (<-chan MessageEvent, error) {
+	events := make(chan MessageEvent, 64)
+	ctx, release := c.cancelOnShutdown(ctx)
+
+	deliver := func(e sseEvent) {
+		var event MessageEvent
+		if err := json.Unmarshal(e.Data, &event); err != nil {
+			return
+		}
+		if event.Type == "" {
+			event.Type = e.Event
+		}
+		if event.EventID == "" {
+			event.EventID = e.ID
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	done := func() {
+		release()
+		close(events)
+	}
+
+	var err error
+	if c.transport != nil {
+		err = c.watchMessageEventsTransport(ctx, filter, func(event MessageEvent) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		}, done)
+	} else {
+		err = c.openStream(ctx, "/api/v1/messages/events", filter.query(), deliver, done)
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Worker event types emitted by the service
+const (
+	WorkerEventStarted      = "worker_started"
+	WorkerEventStopped      = "worker_stopped"
+	WorkerEventError        = "worker_error"
+	WorkerEventScaleApplied = "scale_applied"
+)
+
+// WorkerEvent represents a worker lifecycle notification
+type WorkerEvent struct {
+	// EventID is the stream position used to resume after reconnecting
+	EventID   string `json:"event_id"`
+	Type      string `json:"type"`
+	WorkerID  string `json:"worker_id,omitempty"`
+	QueueName string `json:"queue_name,omitempty"`
+	Priority  string `json:"priority,omitempty"`
+	// Count is the worker count after a scale_applied event
+	Count     int    `json:"count,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// SubscribeWorkerEvents opens the service's worker event stream and delivers
+// lifecycle events on the returned channel, reconnecting with backoff when
+// the connection drops. The channel is closed when ctx is done or the
+// service rejects the subscription
+func (c *Client) SubscribeWorkerEvents(ctx context.Context) (<-chan WorkerEvent, error) {
+	events := make(chan WorkerEvent, 64)
+	ctx, release := c.cancelOnShutdown(ctx)
+
+	deliver := func(e sseEvent) {
+		var event WorkerEvent
+		if err := json.Unmarshal(e.Data, &event); err != nil {
+			return
+		}
+		if event.Type == "" {
+			event.Type = e.Event
+		}
+		if event.EventID == "" {
+			event.EventID = e.ID
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	err := c.openStream(ctx, "/api/v1/workers/events", nil, deliver, func() {
+		release()
+		close(events)
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,164 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSubscribeMessageEventsResumes(t *testing.T) {
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("topic") != "pullrequests" {
+			t.Errorf("Expected topic filter 'pullrequests', got '%s'", r.URL.Query().Get("topic"))
+		}
+		connections++
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if connections == 1 {
+			fmt.Fprint(w, "retry: 10\n\n")
+			fmt.Fprint(w, "id: 1\nevent: queued\ndata: {\"message_id\":\"msg-1\"}\n\n")
+			return
+		}
+
+		if r.Header.Get("Last-Event-ID") != "1" {
+			t.Errorf("Expected Last-Event-ID '1', got '%s'", r.Header.Get("Last-Event-ID"))
+		}
+		fmt.Fprint(w, ": keep-alive\n")
+		fmt.Fprint(w, "id: 2\nevent: completed\ndata: {\"message_id\":\"msg-1\",\n")
+		fmt.Fprint(w, "data: \"status\":\"completed\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := client.SubscribeMessageEvents(ctx, MessageEventFilter{Topic: TopicPullRequests})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	first := <-events
+	if first.Type != MessageEventQueued || first.EventID != "1" || first.MessageID != "msg-1" {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+
+	second := <-events
+	if second.Type != MessageEventCompleted || second.Status != "completed" {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubscribeMessageEventsResumesAfterIDOnlyEvent(t *testing.T) {
+	connections := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if connections == 1 {
+			fmt.Fprint(w, "retry: 10\n\n")
+			fmt.Fprint(w, "id: 1\nevent: queued\ndata: {\"message_id\":\"msg-1\"}\n\n")
+			// Events the subscription filters out still advance the stream
+			fmt.Fprint(w, "id: 5\n\n")
+			return
+		}
+
+		if r.Header.Get("Last-Event-ID") != "5" {
+			t.Errorf("Expected Last-Event-ID '5', got '%s'", r.Header.Get("Last-Event-ID"))
+		}
+		fmt.Fprint(w, "id: 6\nevent: completed\ndata: {\"message_id\":\"msg-1\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := client.SubscribeMessageEvents(ctx, MessageEventFilter{Topic: TopicPullRequests})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first := <-events; first.EventID != "1" {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+	if second := <-events; second.EventID != "6" {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubscribeMessageEventsHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.SubscribeMessageEvents(ctx, MessageEventFilter{}); err == nil || ctx.Err() != nil {
+		t.Errorf("Expected the stream to time out waiting for headers, got %v", err)
+	}
+}
+
+func TestSubscribeMessageEventsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if _, err := client.SubscribeMessageEvents(context.Background(), MessageEventFilter{}); !IsAPIError(err) {
+		t.Errorf("Expected APIError, got %v", err)
+	}
+}
+
+func TestSubscribeWorkerEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/workers/events" {
+			t.Errorf("Expected path '/api/v1/workers/events', got '%s'", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 7\nevent: worker_error\ndata: {\"worker_id\":\"high-1\",\"error\":\"panic\"}\n\n")
+		fmt.Fprint(w, "id: 8\ndata: {\"type\":\"scale_applied\",\"priority\":\"high\",\"count\":3}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := client.SubscribeWorkerEvents(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	first := <-events
+	if first.Type != WorkerEventError || first.WorkerID != "high-1" || first.Error != "panic" {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+
+	second := <-events
+	if second.Type != WorkerEventScaleApplied || second.Count != 3 || second.EventID != "8" {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -156,7 +156,7 @@ func main() {
 	// Try to post a message with invalid priority
 	invalidReq := &sdk.MessageRequest{
 		ItemID:      "pr-invalid",
-		Priority:    "invalid", // This will cause an error
+		Priority:    "invalid", // Rejected by client-side validation
 		Topic:       sdk.TopicPullRequests,
 		CallbackURL: "https://httpbin.org/post",
 		ObjectBody:  map[string]interface{}{"test": true},
@@ -164,7 +164,9 @@ func main() {
 
 	_, err = client.PostMessage(ctx, invalidReq)
 	if err != nil {
-		if sdk.IsAPIError(err) {
+		if sdk.IsValidationError(err) {
+			fmt.Printf("Validation Error: %v\n", err)
+		} else if sdk.IsAPIError(err) {
 			fmt.Printf("API Error: %v\n", err)
 		} else {
 			fmt.Printf("Other Error: %v\n", err)
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ExportFormat is the encoding of a message export
+type ExportFormat string
+
+// Export formats
+const (
+	// ExportNDJSON writes one MessageDetail JSON object per line
+	ExportNDJSON ExportFormat = "ndjson"
+	// ExportCSV writes a header row followed by one row per message
+	ExportCSV ExportFormat = "csv"
+)
+
+// IsValid reports whether f is one of the known export formats
+func (f ExportFormat) IsValid() bool {
+	return f == ExportNDJSON || f == ExportCSV
+}
+
+// contentType returns the media type the service uses for the format
+func (f ExportFormat) contentType() string {
+	if f == ExportCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// ExportOptions selects the messages ExportMessages writes; empty fields
+// match everything
+type ExportOptions struct {
+	Topic  Topic
+	Status MessageStatus
+	// Since and Until bound when the messages were submitted; Until is
+	// exclusive
+	Since time.Time
+	Until time.Time
+	// Format defaults to ExportNDJSON
+	Format ExportFormat
+}
+
+// withDefaults fills unset export options
+func (o ExportOptions) withDefaults() ExportOptions {
+	if o.Format == "" {
+		o.Format = ExportNDJSON
+	}
+	return o
+}
+
+// query encodes the options as URL query parameters
+func (o ExportOptions) query() url.Values {
+	q := url.Values{"format": {string(o.Format)}}
+	if o.Topic != "" {
+		q.Set("topic", string(o.Topic))
+	}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if !o.Since.IsZero() {
+		q.Set("since", o.Since.UTC().Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		q.Set("until", o.Until.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// ExportMessages streams the messages matching opts from the service's
+// export endpoint, e.g. for a periodic dump of processed messages into an
+// analytics store. The export is not buffered or subject to the client's
+// timeout and response size limit; the caller must close the returned
+// reader, and cancelling ctx aborts the export
+func (c *Client) ExportMessages(ctx context.Context, opts ExportOptions) (io.ReadCloser, error) {
+	opts = opts.withDefaults()
+	if !opts.Format.IsValid() {
+		return nil, fmt.Errorf("format must be '%s' or '%s'", ExportNDJSON, ExportCSV)
+	}
+	if opts.Status == UnknownStatus {
+		return nil, fmt.Errorf("cannot export messages with an unknown status")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Until.After(opts.Since) {
+		return nil, fmt.Errorf("until must be after since")
+	}
+
+	path := "/api/v1/messages/export?" + opts.query().Encode()
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", opts.Format.contentType())
+
+	// Exports may run far longer than a single request, so the client-wide
+	// timeout must not apply
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	c.telemetry.record(http.MethodGet, path, resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	c.version.observe(resp)
+	c.recordResponseMeta(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorResponseSize))
+		return nil, c.translateError(resp, msg)
+	}
+
+	return resp.Body, nil
+}
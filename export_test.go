@@ -0,0 +1,89 @@
+package sdk
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExportMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/messages/export" {
+			t.Errorf("Expected path '/api/v1/messages/export', got '%s'", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("format") != "csv" || q.Get("topic") != "pullrequests" || q.Get("status") != "completed" {
+			t.Errorf("Unexpected query '%s'", r.URL.RawQuery)
+		}
+		if q.Get("since") != "2026-01-01T00:00:00Z" || q.Get("until") != "2026-01-02T00:00:00Z" {
+			t.Errorf("Expected the time range in UTC, got '%s'", r.URL.RawQuery)
+		}
+		if r.Header.Get("Accept") != "text/csv" {
+			t.Errorf("Expected Accept 'text/csv', got '%s'", r.Header.Get("Accept"))
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("id,item_id,status\nmsg-1,pr-1,completed\nmsg-2,pr-2,completed\n"))
+	}))
+	defer server.Close()
+
+	since := time.Date(2026, 1, 1, 1, 0, 0, 0, time.FixedZone("CET", 3600))
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: time.Nanosecond})
+	export, err := client.ExportMessages(context.Background(), ExportOptions{
+		Topic:  TopicPullRequests,
+		Status: StatusCompleted,
+		Since:  since,
+		Until:  since.Add(24 * time.Hour),
+		Format: ExportCSV,
+	})
+	if err != nil {
+		t.Fatalf("ExportMessages failed: %v", err)
+	}
+	defer export.Close()
+
+	rows, err := csv.NewReader(export).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if len(rows) != 3 || rows[2][0] != "msg-2" {
+		t.Errorf("Unexpected export %v", rows)
+	}
+}
+
+func TestExportMessagesErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "ndjson" {
+			t.Errorf("Expected the default format, got '%s'", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"forbidden","message":"exports are disabled"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	export, err := client.ExportMessages(ctx, ExportOptions{})
+	if !IsAPIError(err) {
+		t.Errorf("Expected an APIError, got %v", err)
+	}
+	if export != nil {
+		io.Copy(io.Discard, export)
+		t.Error("Expected no export on error")
+	}
+
+	now := time.Now()
+	for name, opts := range map[string]ExportOptions{
+		"format": {Format: "xml"},
+		"status": {Status: UnknownStatus},
+		"range":  {Since: now, Until: now.Add(-time.Hour)},
+	} {
+		if _, err := client.ExportMessages(ctx, opts); err == nil || IsAPIError(err) {
+			t.Errorf("Expected invalid %s to be rejected, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults for the fair submitter
+const (
+	DefaultFairWeight      = 1.0
+	DefaultFairConcurrency = 4
+	DefaultFairQueueSize   = 1000
+)
+
+// ErrFairSubmitterClosed is returned when sending through a fair submitter
+// that has been closed
+var ErrFairSubmitterClosed = errors.New("fair submitter is closed")
+
+// FairClass identifies the messages sharing a queue of a FairSubmitter
+type FairClass struct {
+	Priority Priority
+	// Topic is empty in a weight that applies to every topic of Priority
+	Topic Topic
+}
+
+func (c FairClass) String() string {
+	if c.Topic == "" {
+		return string(c.Priority)
+	}
+	return fmt.Sprintf("%s/%s", c.Priority, c.Topic)
+}
+
+// FairSubmitterConfig configures a FairSubmitter
+type FairSubmitterConfig struct {
+	// Weights sets the share of submissions each class gets while several
+	// are backlogged, e.g. high priority 8 and low priority 1. A class is
+	// weighted by its own entry, then by the entry of its priority with an
+	// empty topic, then DefaultFairWeight; non-positive weights are ignored
+	Weights map[FairClass]float64
+	// Concurrency bounds the submissions in flight; defaults to
+	// DefaultFairConcurrency
+	Concurrency int
+	// RateLimit caps submissions across every class in messages per second;
+	// zero is unlimited
+	RateLimit float64
+	// QueueSize is the number of messages a class may queue before Send
+	// blocks; defaults to DefaultFairQueueSize
+	QueueSize int
+}
+
+// withDefaults fills unset fair submitter options
+func (c FairSubmitterConfig) withDefaults() FairSubmitterConfig {
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultFairConcurrency
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = DefaultFairQueueSize
+	}
+	return c
+}
+
+// weight returns the weight of class
+func (c FairSubmitterConfig) weight(class FairClass) float64 {
+	if w := c.Weights[class]; w > 0 {
+		return w
+	}
+	if w := c.Weights[FairClass{Priority: class.Priority}]; w > 0 {
+		return w
+	}
+	return DefaultFairWeight
+}
+
+// FairClassStats reports the activity of one class of a fair submitter
+type FairClassStats struct {
+	// Queued is the number of messages waiting to be submitted
+	Queued int
+	// Sent is the number of messages accepted by the service
+	Sent int64
+	// Failed is the number of messages that could not be submitted,
+	// including canceled ones
+	Failed int64
+	// MaxWait is the longest a message of the class has waited in its queue
+	MaxWait time.Duration
+}
+
+// fairItem is a queued message and the virtual time its submission finishes
+type fairItem struct {
+	future *MessageFuture
+	finish float64
+	queued time.Time
+}
+
+// fairQueue holds the messages of one class in the order they were sent
+type fairQueue struct {
+	weight float64
+	items  []fairItem
+	// finish is the finish tag of the last message queued
+	finish float64
+	// slots holds a token per queued message, bounding the queue
+	slots chan struct{}
+	stats FairClassStats
+}
+
+// FairSubmitter shares submission capacity, its concurrency and rate limit,
+// between classes of messages by weight, so that a backlog of one class
+// cannot starve another: with high priority weighted 8 and low priority 1, a
+// high priority message waits behind at most about one low priority
+// submission in eight, however deep the low priority backlog. Messages are
+// classed by priority and topic. Messages of one class are submitted in the
+// order they were sent. Only submissions made through the FairSubmitter are
+// scheduled, so every workload sharing the client should go through it
+type FairSubmitter struct {
+	client  *Client
+	config  FairSubmitterConfig
+	limiter *rateLimiter
+
+	// ready is signaled when a message is queued or the submitter closes
+	ready chan struct{}
+	done  chan struct{}
+	// ctx is canceled to abandon queued and in-flight submissions
+	ctx        context.Context
+	cancel     context.CancelFunc
+	unregister func()
+
+	mu     sync.Mutex
+	queues map[FairClass]*fairQueue
+	// vtime is the finish tag of the last message dispatched
+	vtime    float64
+	pending  int
+	inFlight map[*MessageFuture]FairClass
+	closed   bool
+}
+
+// NewFairSubmitter starts a fair submitter submitting through client
+func NewFairSubmitter(client *Client, config FairSubmitterConfig) *FairSubmitter {
+	config = config.withDefaults()
+
+	s := &FairSubmitter{
+		client:   client,
+		config:   config,
+		ready:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		queues:   make(map[FairClass]*fairQueue),
+		inFlight: make(map[*MessageFuture]FairClass),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if config.RateLimit > 0 {
+		s.limiter = newRateLimiter(config.RateLimit, client.clock)
+	}
+
+	s.unregister = client.RegisterShutdownHook(ShutdownFlush, s)
+	go s.run()
+	return s
+}
+
+// Send validates msg and queues it in its class, blocking only while the
+// class queue is full. The returned future completes once the message was
+// submitted. Cancelling ctx or the future before then drops the message
+func (s *FairSubmitter) Send(ctx context.Context, msg *MessageRequest) *MessageFuture {
+	fctx, cancel := context.WithCancel(ctx)
+	f := &MessageFuture{ctx: fctx, cancel: cancel, done: make(chan struct{})}
+
+	if msg == nil {
+		f.complete(nil, errors.New("message request cannot be nil"))
+		return f
+	}
+
+	// Validate up front, and fix the priority the message is classed by
+	req := s.client.withDefaults(ctx, msg)
+	if err := s.client.preparePayload(ctx, "", req); err != nil {
+		f.complete(nil, err)
+		return f
+	}
+	if err := req.Validate(); err != nil {
+		f.complete(nil, err)
+		return f
+	}
+	f.req = req
+
+	class := FairClass{Priority: req.Priority, Topic: req.Topic}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		f.complete(nil, ErrFairSubmitterClosed)
+		return f
+	}
+	q := s.queue(class)
+	s.mu.Unlock()
+
+	select {
+	case q.slots <- struct{}{}:
+	case <-fctx.Done():
+		f.complete(nil, fctx.Err())
+		return f
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		<-q.slots
+		f.complete(nil, ErrFairSubmitterClosed)
+		return f
+	}
+	// Self-clocked fair queuing: a message finishes 1/weight after the later
+	// of the last message dispatched and the last message of its class
+	start := s.vtime
+	if q.finish > start {
+		start = q.finish
+	}
+	q.finish = start + 1/q.weight
+	q.items = append(q.items, fairItem{future: f, finish: q.finish, queued: s.client.clock.Now()})
+	s.pending++
+	s.mu.Unlock()
+
+	s.signal()
+	return f
+}
+
+// Flush waits until every message sent before it was called has been
+// submitted or ctx is done
+func (s *FairSubmitter) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	var futures []*MessageFuture
+	for _, q := range s.queues {
+		for _, item := range q.items {
+			futures = append(futures, item.future)
+		}
+	}
+	for f := range s.inFlight {
+		futures = append(futures, f)
+	}
+	s.mu.Unlock()
+
+	for _, f := range futures {
+		select {
+		case <-f.Done():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close stops accepting messages and waits until every queued message has
+// been submitted; it is safe to call more than once
+func (s *FairSubmitter) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.signal()
+
+	<-s.done
+	s.cancel()
+	s.unregister()
+	return nil
+}
+
+// Shutdown is Close bounded by ctx: once ctx is done, queued and in-flight
+// submissions are abandoned and their futures failed
+func (s *FairSubmitter) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		s.Close()
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-closed
+		return fmt.Errorf("fair submissions abandoned: %w", ctx.Err())
+	}
+}
+
+// Stats returns a snapshot of the counters of every class seen so far
+func (s *FairSubmitter) Stats() map[FairClass]FairClassStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[FairClass]FairClassStats, len(s.queues))
+	for class, q := range s.queues {
+		st := q.stats
+		st.Queued = len(q.items)
+		stats[class] = st
+	}
+	return stats
+}
+
+// queue returns the queue of class, creating it on first use. s.mu must be held
+func (s *FairSubmitter) queue(class FairClass) *fairQueue {
+	q, ok := s.queues[class]
+	if !ok {
+		q = &fairQueue{
+			weight: s.config.weight(class),
+			finish: s.vtime,
+			slots:  make(chan struct{}, s.config.QueueSize),
+		}
+		s.queues[class] = q
+	}
+	return q
+}
+
+// signal wakes the dispatcher without blocking
+func (s *FairSubmitter) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run dispatches queued messages as capacity allows until the submitter is
+// closed and drained, or abandoned
+func (s *FairSubmitter) run() {
+	defer close(s.done)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sem := make(chan struct{}, s.config.Concurrency)
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-s.ctx.Done():
+			s.abandon(s.ctx.Err())
+			return
+		}
+
+		if !s.awaitPending() {
+			s.abandon(s.ctx.Err())
+			return
+		}
+		if s.limiter != nil {
+			if err := s.limiter.wait(s.ctx, 1); err != nil {
+				s.abandon(err)
+				return
+			}
+		}
+
+		// Pick only once capacity is free, so that a message queued while
+		// waiting for it can still go first
+		f, class := s.next()
+		if f == nil {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.submit(f, class)
+		}()
+	}
+}
+
+// awaitPending blocks until a message is queued and reports whether one is;
+// it returns false once the submitter is closed and drained, or abandoned
+func (s *FairSubmitter) awaitPending() bool {
+	for {
+		s.mu.Lock()
+		pending, closed := s.pending, s.closed
+		s.mu.Unlock()
+
+		switch {
+		case pending > 0:
+			return true
+		case closed:
+			return false
+		}
+
+		select {
+		case <-s.ready:
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+}
+
+// next dequeues the message with the earliest finish tag, completing
+// canceled messages it passes over. It returns nil when none is left
+func (s *FairSubmitter) next() (*MessageFuture, FairClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.pending > 0 {
+		var class FairClass
+		var q *fairQueue
+		for c, candidate := range s.queues {
+			if len(candidate.items) == 0 {
+				continue
+			}
+			if q == nil || candidate.items[0].finish < q.items[0].finish {
+				class, q = c, candidate
+			}
+		}
+
+		item := q.items[0]
+		q.items[0] = fairItem{}
+		q.items = q.items[1:]
+		<-q.slots
+		s.pending--
+		s.vtime = item.finish
+
+		if wait := s.client.clock.Now().Sub(item.queued); wait > q.stats.MaxWait {
+			q.stats.MaxWait = wait
+		}
+		if err := item.future.ctx.Err(); err != nil {
+			q.stats.Failed++
+			item.future.complete(nil, err)
+			continue
+		}
+
+		s.inFlight[item.future] = class
+		return item.future, class
+	}
+	return nil, FairClass{}
+}
+
+// submit posts the message of f and completes it
+func (s *FairSubmitter) submit(f *MessageFuture, class FairClass) {
+	stop := context.AfterFunc(s.ctx, f.cancel)
+	resp, err := s.client.PostMessage(f.ctx, f.req)
+	stop()
+
+	s.mu.Lock()
+	delete(s.inFlight, f)
+	if err != nil {
+		s.queues[class].stats.Failed++
+	} else {
+		s.queues[class].stats.Sent++
+	}
+	s.mu.Unlock()
+
+	f.complete(resp, err)
+}
+
+// abandon fails every queued message with err
+func (s *FairSubmitter) abandon(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, q := range s.queues {
+		for _, item := range q.items {
+			q.stats.Failed++
+			item.future.complete(nil, err)
+			<-q.slots
+		}
+		q.items = nil
+	}
+	s.pending = 0
+}
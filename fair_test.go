@@ -0,0 +1,176 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fairServer accepts messages, recording their item IDs in order. The first
+// request is held until release is closed
+func fairServer(t *testing.T, started chan<- struct{}, release <-chan struct{}) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var order []string
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		order = append(order, req.ItemID)
+		mu.Unlock()
+
+		once.Do(func() {
+			started <- struct{}{}
+			<-release
+		})
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + req.ItemID, ItemID: req.ItemID})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), order...)
+	}
+}
+
+func TestFairSubmitterWeights(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server, order := fairServer(t, started, release)
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	submitter := NewFairSubmitter(client, FairSubmitterConfig{
+		Weights:     map[FairClass]float64{{Priority: PriorityHigh}: 4},
+		Concurrency: 1,
+	})
+	defer submitter.Close()
+
+	ctx := context.Background()
+	send := func(id string, priority Priority) *MessageFuture {
+		return submitter.Send(ctx, newMessageRequest(id, priority, TopicPullRequests, "https://example.com/callback", map[string]interface{}{"n": id}))
+	}
+
+	// A backfill occupies the only slot and queues up behind it
+	futures := []*MessageFuture{send("low-0", PriorityLow)}
+	<-started
+	for i := 1; i <= 8; i++ {
+		futures = append(futures, send(fmt.Sprintf("low-%d", i), PriorityLow))
+	}
+	futures = append(futures, send("high-0", PriorityHigh), send("high-1", PriorityHigh))
+	close(release)
+
+	for _, f := range futures {
+		if _, err := f.Result(); err != nil {
+			t.Fatalf("Submission failed: %v", err)
+		}
+	}
+
+	got := order()
+	want := []string{"low-0", "high-0", "high-1", "low-1", "low-2"}
+	for i, id := range want {
+		if got[i] != id {
+			t.Fatalf("Expected submissions to start %v, got %v", want, got)
+		}
+	}
+
+	stats := submitter.Stats()
+	if low := stats[FairClass{Priority: PriorityLow, Topic: TopicPullRequests}]; low.Sent != 9 || low.Queued != 0 {
+		t.Errorf("Unexpected low priority stats: %+v", low)
+	}
+	if high := stats[FairClass{Priority: PriorityHigh, Topic: TopicPullRequests}]; high.Sent != 2 {
+		t.Errorf("Unexpected high priority stats: %+v", high)
+	}
+}
+
+func TestFairSubmitterCancel(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server, order := fairServer(t, started, release)
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	submitter := NewFairSubmitter(client, FairSubmitterConfig{Concurrency: 1})
+
+	ctx := context.Background()
+	first := submitter.Send(ctx, newMessageRequest("first", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	<-started
+
+	canceled := submitter.Send(ctx, newMessageRequest("canceled", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	kept := submitter.Send(ctx, newMessageRequest("kept", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	canceled.Cancel()
+	close(release)
+
+	if err := submitter.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if _, err := first.Result(); err != nil {
+		t.Errorf("Expected the first message to be sent, got %v", err)
+	}
+	if _, err := canceled.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the canceled message to fail with context.Canceled, got %v", err)
+	}
+	if _, err := kept.Result(); err != nil {
+		t.Errorf("Expected the kept message to be sent, got %v", err)
+	}
+	if got := order(); len(got) != 2 || got[1] != "kept" {
+		t.Errorf("Expected the canceled message to be skipped, got %v", got)
+	}
+
+	submitter.Close()
+	if _, err := submitter.Send(ctx, newMessageRequest("late", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{})).Result(); !errors.Is(err, ErrFairSubmitterClosed) {
+		t.Errorf("Expected ErrFairSubmitterClosed after Close, got %v", err)
+	}
+}
+
+func TestFairSubmitterShutdown(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server, _ := fairServer(t, started, release)
+	defer close(release)
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	submitter := NewFairSubmitter(client, FairSubmitterConfig{Concurrency: 1})
+
+	ctx := context.Background()
+	inFlight := submitter.Send(ctx, newMessageRequest("in-flight", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+	<-started
+	queued := submitter.Send(ctx, newMessageRequest("queued", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]interface{}{}))
+
+	sctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := submitter.Shutdown(sctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Shutdown to give up at its deadline, got %v", err)
+	}
+	if _, err := inFlight.Result(); err == nil {
+		t.Error("Expected the in-flight message to be abandoned")
+	}
+	if _, err := queued.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the queued message to be abandoned, got %v", err)
+	}
+}
+
+func TestFairSubmitterConfigWeight(t *testing.T) {
+	config := FairSubmitterConfig{Weights: map[FairClass]float64{
+		{Priority: PriorityHigh}:                          4,
+		{Priority: PriorityHigh, Topic: "deployments"}:    8,
+		{Priority: PriorityLow, Topic: TopicPullRequests}: -1,
+	}}
+
+	tests := []struct {
+		class FairClass
+		want  float64
+	}{
+		{FairClass{Priority: PriorityHigh, Topic: "deployments"}, 8},
+		{FairClass{Priority: PriorityHigh, Topic: TopicPullRequests}, 4},
+		{FairClass{Priority: PriorityLow, Topic: TopicPullRequests}, DefaultFairWeight},
+	}
+	for _, tt := range tests {
+		if got := config.weight(tt.class); got != tt.want {
+			t.Errorf("weight(%s) = %v, want %v", tt.class, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// FingerprintHeader carries the fingerprint of a request, so that the
+// attempts of a retried request can be matched up in service logs and audit
+// records
+const FingerprintHeader = "X-Request-Fingerprint"
+
+// RequestFingerprint identifies a request by its method, its path including
+// the query, e.g. a pagination cursor, and its serialized body. Attempts of a
+// retried request share a fingerprint since they are sent byte for byte the
+// same
+func RequestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(path))
+	h.Write([]byte{'\n'})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// preparedRequest is a request whose body has been serialized, so that it
+// can be sent again without being rebuilt. It must be released once it will
+// not be sent again
+type preparedRequest struct {
+	method      string
+	path        string
+	body        interface{}
+	version     APIVersion
+	data        []byte
+	buf         *pooledBytes
+	contentType string
+	fingerprint string
+}
+
+// release returns the request's body buffer to the pool once the bodies
+// being sent no longer need it
+func (p *preparedRequest) release() {
+	p.buf.release()
+}
+
+// prepareRequest resolves the API version and serializes body for a request
+func (c *Client) prepareRequest(ctx context.Context, method, path string, body interface{}) (*preparedRequest, error) {
+	p := &preparedRequest{method: method, path: path, body: body}
+	if body != nil {
+		var err error
+		p.version, err = c.resolveAPIVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.data, p.contentType, p.buf, err = c.marshalBody(p.version, path, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.fingerprint = RequestFingerprint(method, path, p.data)
+	return p, nil
+}
+
+type fingerprintKey struct{}
+
+// withFingerprint returns a context that sends fingerprint with requests
+func withFingerprint(ctx context.Context, fingerprint string) context.Context {
+	return context.WithValue(ctx, fingerprintKey{}, fingerprint)
+}
+
+// setFingerprintHeader sets the fingerprint header from ctx, if present
+func setFingerprintHeader(ctx context.Context, header http.Header) {
+	if fingerprint, ok := ctx.Value(fingerprintKey{}).(string); ok && fingerprint != "" {
+		header.Set(FingerprintHeader, fingerprint)
+	}
+}
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestFingerprint(t *testing.T) {
+	base := RequestFingerprint(http.MethodGet, "/api/v1/messages?cursor=abc", nil)
+	if len(base) != 32 {
+		t.Errorf("Expected a 32 character fingerprint, got '%s'", base)
+	}
+	if RequestFingerprint(http.MethodGet, "/api/v1/messages?cursor=abc", nil) != base {
+		t.Error("Expected the same request to have the same fingerprint")
+	}
+	if RequestFingerprint(http.MethodGet, "/api/v1/messages?cursor=abd", nil) == base {
+		t.Error("Expected a different cursor to change the fingerprint")
+	}
+	if RequestFingerprint(http.MethodPost, "/api/v1/messages?cursor=abc", nil) == base {
+		t.Error("Expected a different method to change the fingerprint")
+	}
+	if RequestFingerprint(http.MethodPost, "/api/v1/messages", []byte(`{"a":1}`)) == RequestFingerprint(http.MethodPost, "/api/v1/messages", []byte(`{"a":2}`)) {
+		t.Error("Expected a different body to change the fingerprint")
+	}
+}
+
+func TestCallRetrySendsSameRequest(t *testing.T) {
+	type attempt struct {
+		query       string
+		body        string
+		fingerprint string
+	}
+	var (
+		mu       sync.Mutex
+		attempts []attempt
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		attempts = append(attempts, attempt{r.URL.RawQuery, string(body), r.Header.Get(FingerprintHeader)})
+		n := len(attempts)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: BackoffFixed, InitialDelay: time.Millisecond}
+	err := client.Call(context.Background(), http.MethodPost, "/api/v1/messages/search",
+		map[string]interface{}{"topic": "pull_requests", "limit": 50}, nil,
+		WithQuery(url.Values{"cursor": {"c2FtZQ=="}}), WithRetry(policy))
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(attempts))
+	}
+	if attempts[0].fingerprint == "" {
+		t.Error("Expected the fingerprint header to be set")
+	}
+	for i, a := range attempts[1:] {
+		if a != attempts[0] {
+			t.Errorf("Expected attempt %d to match the first, got %+v and %+v", i+2, a, attempts[0])
+		}
+	}
+	want := RequestFingerprint(http.MethodPost, "/api/v1/messages/search?cursor=c2FtZQ%3D%3D", []byte(attempts[0].body))
+	if attempts[0].fingerprint != want {
+		t.Errorf("Expected fingerprint '%s', got '%s'", want, attempts[0].fingerprint)
+	}
+}
+
+func TestAPIErrorFingerprint(t *testing.T) {
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get(FingerprintHeader)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid","message":"bad request"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	err := client.Call(context.Background(), http.MethodPost, "/api/v1/preview", map[string]string{"a": "b"}, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.Fingerprint == "" || apiErr.Fingerprint != sent {
+		t.Errorf("Expected the error to carry the sent fingerprint '%s', got '%s'", sent, apiErr.Fingerprint)
+	}
+}
+
+func TestBulkSessionRetrySendsSameBody(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+		prints []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		prints = append(prints, r.Header.Get(FingerprintHeader))
+		n := len(bodies)
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"success","count":1}`))
+	}))
+	defer server.Close()
+
+	// Generated item IDs and encrypted payloads differ on every call, so
+	// they must only be filled in once per chunk
+	client := NewClient(&Config{BaseURL: server.URL, ItemIDGenerator: UUIDv7, PayloadEncrypter: newTestEncrypter(t)})
+	messages := []MessageRequest{*newMessageRequest("", PriorityLow, TopicPullRequests, "https://example.com/callback", map[string]int{"number": 1})}
+	session, _ := client.NewBulkSession(BulkSessionConfig{ID: "s", RetryBackoff: time.Millisecond})
+	if result, err := session.Submit(context.Background(), messages); err != nil || !result.Complete() {
+		t.Fatalf("Expected the chunk to succeed on retry, got %+v, %v", result, err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] || prints[0] != prints[1] {
+		t.Errorf("Expected the retry to resend the same body and fingerprint, got %v and %v", bodies, prints)
+	}
+}
@@ -0,0 +1,404 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServiceName is the full name of the service's gRPC API
+const GRPCServiceName = "messagesworker.v1.MessagesWorker"
+
+// GRPCConfig routes message submission and message events over the
+// service's gRPC API; every other call keeps using BaseURL
+type GRPCConfig struct {
+	// Target is the address of the gRPC API in grpc.NewClient syntax, e.g.
+	// "dns:///messages-worker.example.com:9090"
+	Target string
+	// DialOptions configure the connection. They must include transport
+	// credentials, e.g. grpc.WithTransportCredentials(insecure.NewCredentials())
+	DialOptions []grpc.DialOption
+}
+
+// WithGRPC sends PostMessage, PostBulkMessages, StreamBulkMessages, and
+// SubscribeMessageEvents to the gRPC API at target, dialed with opts
+func (c *Config) WithGRPC(target string, opts ...grpc.DialOption) *Config {
+	c.GRPC = &GRPCConfig{Target: target, DialOptions: opts}
+	return c
+}
+
+// messageTransport carries message submission and message events in place
+// of the HTTP API. Headers the client would send are attached to ctx as
+// outgoing metadata before each call
+type messageTransport interface {
+	postMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error)
+	postBulkMessages(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error)
+	// streamBulkMessages sends each bulkStreamLine read from lines as NDJSON
+	// and delivers the acks as they arrive
+	streamBulkMessages(ctx context.Context, lines io.Reader, deliver func(BulkMessageAck)) error
+	// watchMessageEvents opens an event stream once the server accepts it
+	// and returns a function receiving its next event
+	watchMessageEvents(ctx context.Context, req *messageWatchRequest) (func() (*MessageEvent, error), error)
+	close() error
+}
+
+// messageWatchRequest opens a message event stream over gRPC
+type messageWatchRequest struct {
+	Topic    Topic    `json:"topic,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+	ItemID   string   `json:"item_id,omitempty"`
+	Types    []string `json:"types,omitempty"`
+	// AfterEventID resumes the stream after the last event received
+	AfterEventID string `json:"after_event_id,omitempty"`
+}
+
+// grpcJSONCodec encodes gRPC messages as JSON with the SDK types' json
+// tags, so that the service's gRPC API shares the REST API's schema
+type grpcJSONCodec struct{}
+
+func (grpcJSONCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (grpcJSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// Name returns the content subtype, sent as application/grpc+json
+func (grpcJSONCodec) Name() string { return "json" }
+
+// grpcTransport implements messageTransport with a gRPC connection
+type grpcTransport struct {
+	conn *grpc.ClientConn
+	// err is the error creating the connection, returned by every call
+	err error
+}
+
+// newGRPCTransport returns a transport configured by config, or nil when
+// config is nil
+func newGRPCTransport(config *GRPCConfig, userAgent string) messageTransport {
+	if config == nil {
+		return nil
+	}
+
+	opts := append([]grpc.DialOption{
+		grpc.WithUserAgent(userAgent),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcJSONCodec{})),
+	}, config.DialOptions...)
+
+	conn, err := grpc.NewClient(config.Target, opts...)
+	if err != nil {
+		return &grpcTransport{err: fmt.Errorf("failed to create gRPC client: %w", err)}
+	}
+	return &grpcTransport{conn: conn}
+}
+
+// grpcMethod returns the full name of a method of the service
+func grpcMethod(name string) string {
+	return "/" + GRPCServiceName + "/" + name
+}
+
+func (t *grpcTransport) postMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	var resp MessageResponse
+	if err := t.conn.Invoke(ctx, grpcMethod("PostMessage"), req, &resp); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &resp, nil
+}
+
+func (t *grpcTransport) postBulkMessages(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	var resp BulkMessageResponse
+	if err := t.conn.Invoke(ctx, grpcMethod("PostBulkMessages"), req, &resp); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	return &resp, nil
+}
+
+func (t *grpcTransport) streamBulkMessages(ctx context.Context, lines io.Reader, deliver func(BulkMessageAck)) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{StreamName: "StreamBulkMessages", ClientStreams: true, ServerStreams: true}
+	stream, err := t.conn.NewStream(ctx, desc, grpcMethod("StreamBulkMessages"))
+	if err != nil {
+		return grpcError(ctx, err)
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		dec := json.NewDecoder(lines)
+		for {
+			var line json.RawMessage
+			if err := dec.Decode(&line); err != nil {
+				if err == io.EOF {
+					sent <- stream.CloseSend()
+					return
+				}
+				// The writer gave up, so abandon the stream
+				sent <- err
+				cancel()
+				return
+			}
+			if err := stream.SendMsg(line); err != nil {
+				// The server ended the stream; RecvMsg reports why
+				sent <- nil
+				return
+			}
+		}
+	}()
+
+	for {
+		var ack BulkMessageAck
+		if err := stream.RecvMsg(&ack); err != nil {
+			if err == io.EOF {
+				break
+			}
+			select {
+			case sendErr := <-sent:
+				if sendErr != nil {
+					return sendErr
+				}
+			default:
+			}
+			return grpcError(ctx, err)
+		}
+		deliver(ack)
+	}
+
+	// The server may end the stream before reading every message; the
+	// caller then stops the writer
+	select {
+	case err := <-sent:
+		return err
+	default:
+		return nil
+	}
+}
+
+func (t *grpcTransport) watchMessageEvents(ctx context.Context, req *messageWatchRequest) (func() (*MessageEvent, error), error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	desc := &grpc.StreamDesc{StreamName: "WatchMessageEvents", ServerStreams: true}
+	stream, err := t.conn.NewStream(ctx, desc, grpcMethod("WatchMessageEvents"))
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	// Wait for the server's headers, or its rejection, so that a refused
+	// subscription is reported to the caller
+	header, err := stream.Header()
+	if err == nil && header == nil {
+		// The stream ended without headers; RecvMsg reports why
+		if err = stream.RecvMsg(&MessageEvent{}); err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, grpcError(ctx, err)
+	}
+
+	return func() (*MessageEvent, error) {
+		var event MessageEvent
+		if err := stream.RecvMsg(&event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}, nil
+}
+
+func (t *grpcTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.Close()
+}
+
+// grpcStatusCodes maps gRPC status codes to the HTTP status codes the REST
+// API returns for the same failures; other codes map to 500
+var grpcStatusCodes = map[codes.Code]int{
+	codes.InvalidArgument:   http.StatusBadRequest,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.NotFound:          http.StatusNotFound,
+	codes.AlreadyExists:     http.StatusConflict,
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
+// grpcError converts a gRPC error into the error the HTTP API would have
+// returned, so that callers handle both transports alike
+func grpcError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("request failed: %w", ctx.Err())
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	code, ok := grpcStatusCodes[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	return &APIError{StatusCode: code, Message: st.Message()}
+}
+
+// transportContext attaches the headers the client sends with path to ctx
+// as gRPC metadata, so that team, cost center, and request metadata carry
+// over to the gRPC API
+func (c *Client) transportContext(ctx context.Context, path string) (context.Context, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.MD{}
+	for name, values := range req.Header {
+		switch name {
+		case "User-Agent", "Content-Type", "Accept":
+			continue
+		}
+		md.Append(strings.ToLower(name), values...)
+	}
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(existing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+// postMessageTransport submits a validated message through the transport
+func (c *Client) postMessageTransport(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ctx, err := c.transportContext(ctx, "/api/v1/messages")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.transport.postMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.CorrelationID == "" {
+		resp.CorrelationID = req.CorrelationID
+	}
+	return resp, nil
+}
+
+// postBulkMessagesTransport submits validated messages through the
+// transport, returning a *BulkPartialError when some were rejected
+func (c *Client) postBulkMessagesTransport(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ctx, err := c.transportContext(ctx, "/api/v1/messages/bulk")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.transport.postBulkMessages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	fillCorrelationIDs(resp, req)
+	if len(resp.Errors) > 0 {
+		return resp, &BulkPartialError{Response: resp}
+	}
+	return resp, nil
+}
+
+// streamBulkMessagesTransport sends the NDJSON lines written by
+// writeBulkStream through the transport
+func (c *Client) streamBulkMessagesTransport(ctx context.Context, lines io.Reader, deliver func(BulkMessageAck)) error {
+	ctx, err := c.transportContext(ctx, "/api/v1/messages/bulk/stream")
+	if err != nil {
+		return err
+	}
+	return c.transport.streamBulkMessages(ctx, lines, deliver)
+}
+
+// watchMessageEventsTransport opens the message event stream through the
+// transport once so that a refused subscription is reported to the caller,
+// then follows it in the background like openStream, reconnecting with
+// backoff and resuming after the last event delivered
+func (c *Client) watchMessageEventsTransport(ctx context.Context, filter MessageEventFilter, deliver func(MessageEvent), done func()) error {
+	req := &messageWatchRequest{Topic: filter.Topic, Priority: filter.Priority, ItemID: filter.ItemID, Types: filter.Types}
+	connect := func() (func() (*MessageEvent, error), error) {
+		mdCtx, err := c.transportContext(ctx, "/api/v1/messages/events")
+		if err != nil {
+			return nil, err
+		}
+		return c.transport.watchMessageEvents(mdCtx, req)
+	}
+
+	recv, err := connect()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer done()
+
+		backoff := streamMinBackoff
+		for {
+			if recv != nil {
+				for {
+					event, err := recv()
+					if err != nil {
+						break
+					}
+					if event.EventID != "" {
+						req.AfterEventID = event.EventID
+					}
+					deliver(*event)
+				}
+				backoff = streamMinBackoff
+			}
+
+			if sleep(ctx, c.clock, backoff) != nil {
+				return
+			}
+
+			recv, err = connect()
+			if err != nil {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+					return
+				}
+				recv = nil
+				backoff *= 2
+				if backoff > streamMaxBackoff {
+					backoff = streamMaxBackoff
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcTestService answers the service's gRPC methods in process
+type grpcTestService struct {
+	postMessage func(ctx context.Context, req *MessageRequest) (*MessageResponse, error)
+	postBulk    func(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error)
+	streamBulk  func(stream grpc.ServerStream) error
+	watch       func(req *messageWatchRequest, stream grpc.ServerStream) error
+}
+
+// newGRPCTestClient serves svc on a loopback listener and returns a client
+// sending message operations to it
+func newGRPCTestClient(t *testing.T, svc *grpcTestService) *Client {
+	t.Helper()
+
+	unary := func(name string, handle func(ctx context.Context, dec func(interface{}) error) (interface{}, error)) grpc.MethodDesc {
+		return grpc.MethodDesc{MethodName: name, Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+			return handle(ctx, dec)
+		}}
+	}
+	desc := grpc.ServiceDesc{
+		ServiceName: GRPCServiceName,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			unary("PostMessage", func(ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+				var req MessageRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				return svc.postMessage(ctx, &req)
+			}),
+			unary("PostBulkMessages", func(ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+				var req BulkMessageRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				return svc.postBulk(ctx, &req)
+			}),
+		},
+		Streams: []grpc.StreamDesc{
+			{StreamName: "StreamBulkMessages", ClientStreams: true, ServerStreams: true, Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				return svc.streamBulk(stream)
+			}},
+			{StreamName: "WatchMessageEvents", ServerStreams: true, Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				var req messageWatchRequest
+				if err := stream.RecvMsg(&req); err != nil {
+					return err
+				}
+				return svc.watch(&req, stream)
+			}},
+		},
+	}
+
+	server := grpc.NewServer(grpc.ForceServerCodec(grpcJSONCodec{}))
+	server.RegisterService(&desc, struct{}{})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	config := (&Config{BaseURL: "http://127.0.0.1:1", Team: "platform"}).
+		WithGRPC(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	client := NewClient(config)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestGRPCPostMessage(t *testing.T) {
+	client := newGRPCTestClient(t, &grpcTestService{
+		postMessage: func(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if got := md.Get("x-team"); len(got) != 1 || got[0] != "platform" {
+				t.Errorf("Expected the team in metadata, got %v", got)
+			}
+			if req.ItemID == "pr-2" {
+				return nil, status.Error(codes.InvalidArgument, "callback host not allowed")
+			}
+			if req.ItemID == "pr-4" {
+				if got := md.Get(strings.ToLower(TenantHeader)); len(got) != 1 || got[0] != "acme" {
+					t.Errorf("Expected the tenant in metadata, got %v", got)
+				}
+			}
+			return &MessageResponse{ID: "msg-1", ItemID: req.ItemID, Status: "queued"}, nil
+		},
+	})
+	ctx := context.Background()
+
+	resp, err := client.PostMessage(ctx, &MessageRequest{ItemID: "pr-1", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"})
+	if err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+	if resp.ID != "msg-1" || resp.ItemID != "pr-1" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	// The service does not echo the correlation ID, so the generated one is kept
+	if !uuidV7Pattern.MatchString(resp.CorrelationID) {
+		t.Errorf("Expected the generated correlation ID, got '%s'", resp.CorrelationID)
+	}
+
+	_, err = client.PostMessage(ctx, &MessageRequest{ItemID: "pr-2", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 || apiErr.Message != "callback host not allowed" {
+		t.Errorf("Expected a 400 APIError, got %v", err)
+	}
+
+	// Tenant clients share the connection
+	if _, err := client.ForTenant("acme").PostMessage(ctx, &MessageRequest{ItemID: "pr-4", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"}); err != nil {
+		t.Errorf("PostMessage for a tenant failed: %v", err)
+	}
+
+	// Client-side validation still runs before anything is sent
+	if _, err := client.PostMessage(ctx, &MessageRequest{ItemID: "pr-3"}); !IsValidationError(err) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestGRPCPostBulkMessages(t *testing.T) {
+	client := newGRPCTestClient(t, &grpcTestService{
+		postBulk: func(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+			return &BulkMessageResponse{
+				Status:   "partial",
+				Count:    1,
+				Messages: []MessageResponse{{ID: "msg-1", ItemID: req.Messages[0].ItemID}},
+				Errors:   []BulkMessageError{{Index: 1, ItemID: req.Messages[1].ItemID, Code: "duplicate", Reason: "already queued"}},
+			}, nil
+		},
+	})
+
+	resp, err := client.PostBulkMessages(context.Background(), &BulkMessageRequest{Messages: []MessageRequest{
+		{ItemID: "pr-1", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"},
+		{ItemID: "pr-2", Priority: PriorityHigh, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"},
+	}})
+	if !IsBulkPartialError(err) {
+		t.Fatalf("Expected a BulkPartialError, got %v", err)
+	}
+	if resp.Count != 1 || len(resp.Errors) != 1 || resp.Errors[0].ItemID != "pr-2" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestGRPCStreamBulkMessages(t *testing.T) {
+	client := newGRPCTestClient(t, &grpcTestService{
+		streamBulk: func(stream grpc.ServerStream) error {
+			for {
+				var line bulkStreamLine
+				if err := stream.RecvMsg(&line); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				ack := BulkMessageAck{Index: line.Index, ItemID: line.Message.ItemID, ID: "msg-" + line.Message.ItemID, Status: "queued"}
+				if line.Message.ItemID == "pr-2" {
+					ack = BulkMessageAck{Index: line.Index, ItemID: "pr-2", Code: "duplicate", Reason: "already queued"}
+				}
+				if err := stream.SendMsg(ack); err != nil {
+					return err
+				}
+			}
+		},
+	})
+
+	messages := make(chan MessageRequest)
+	go func() {
+		defer close(messages)
+		for _, id := range []string{"pr-0", "pr-1", "pr-2"} {
+			req := MessageRequest{ItemID: id, Priority: PriorityMedium, Topic: TopicPullRequests, CallbackURL: "https://example.com/callback"}
+			if id == "pr-1" {
+				req.CallbackURL = "not a url"
+			}
+			messages <- req
+		}
+	}()
+
+	var acks []BulkMessageAck
+	result, err := client.StreamBulkMessages(context.Background(), messages, func(ack BulkMessageAck) {
+		acks = append(acks, ack)
+	})
+	if err != nil {
+		t.Fatalf("StreamBulkMessages failed: %v", err)
+	}
+	if result.Sent != 2 || result.Accepted != 1 || result.Rejected != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(acks) != 3 {
+		t.Fatalf("Expected 3 acks, got %d", len(acks))
+	}
+}
+
+func TestGRPCSubscribeMessageEvents(t *testing.T) {
+	client := newGRPCTestClient(t, &grpcTestService{
+		watch: func(req *messageWatchRequest, stream grpc.ServerStream) error {
+			if req.Topic != TopicPullRequests {
+				return status.Error(codes.PermissionDenied, "topic not allowed")
+			}
+			stream.SendHeader(nil)
+			for _, id := range []string{"1", "2"} {
+				if err := stream.SendMsg(MessageEvent{EventID: id, Type: MessageEventQueued, MessageID: "msg-" + id}); err != nil {
+					return err
+				}
+			}
+			<-stream.Context().Done()
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.SubscribeMessageEvents(ctx, MessageEventFilter{Topic: "deployments"}); err == nil {
+		t.Error("Expected a refused subscription to fail")
+	}
+
+	events, err := client.SubscribeMessageEvents(ctx, MessageEventFilter{Topic: TopicPullRequests})
+	if err != nil {
+		t.Fatalf("SubscribeMessageEvents failed: %v", err)
+	}
+	for _, id := range []string{"1", "2"} {
+		event := <-events
+		if event.EventID != id || event.MessageID != "msg-"+id {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -2,10 +2,16 @@ package sdk
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
+// DefaultHealthCheckTimeout bounds health checks unless
+// Config.HealthCheckTimeout is set
+const DefaultHealthCheckTimeout = 2 * time.Second
+
 // HealthResponse represents the response from the health check endpoint
 type HealthResponse struct {
 	Status string `json:"status"`
@@ -13,6 +19,9 @@ type HealthResponse struct {
 
 // CheckHealth checks if the messages-worker service is healthy
 func (c *Client) CheckHealth(ctx context.Context) (*HealthResponse, error) {
+	ctx, cancel := c.healthCheckContext(ctx)
+	defer cancel()
+
 	resp, err := c.doRequest(ctx, http.MethodGet, "/health", nil)
 	if err != nil {
 		return nil, err
@@ -25,6 +34,7 @@ func (c *Client) CheckHealth(ctx context.Context) (*HealthResponse, error) {
 		return nil, &APIError{
 			StatusCode: resp.StatusCode,
 			Message:    fmt.Sprintf("health check failed with status %d", resp.StatusCode),
+			Meta:       c.responseMetaFrom(resp),
 		}
 	}
 
@@ -39,8 +49,181 @@ func (c *Client) IsHealthy(ctx context.Context) bool {
 	return err == nil
 }
 
+// healthCheckContext bounds ctx by the health check timeout; a shorter
+// deadline already set on ctx is kept
+func (c *Client) healthCheckContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.healthCheckTimeout)
+}
+
 // Ping is an alias for CheckHealth for convenience
 func (c *Client) Ping(ctx context.Context) (*HealthResponse, error) {
 	return c.CheckHealth(ctx)
 }
 
+
+// ServiceInfo describes the running service build and its dependencies
+type ServiceInfo struct {
+	Version   string `json:"version"`
+	GitSHA    string `json:"git_sha"`
+	BuildTime string `json:"build_time,omitempty"`
+	UptimeMs  int64  `json:"uptime_ms"`
+	// APIVersions lists the endpoint generations the service supports
+	APIVersions  []APIVersion       `json:"api_versions,omitempty"`
+	QueueBackend QueueBackendStatus `json:"queue_backend"`
+}
+
+// QueueBackendStatus describes the service's connection to its message broker
+type QueueBackendStatus struct {
+	Type      string `json:"type"`
+	Connected bool   `json:"connected"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Uptime returns how long the service has been running
+func (i *ServiceInfo) Uptime() time.Duration {
+	return time.Duration(i.UptimeMs) * time.Millisecond
+}
+
+// SupportsAPIVersion reports whether the service advertises the given API version
+func (i *ServiceInfo) SupportsAPIVersion(version APIVersion) bool {
+	for _, v := range i.APIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// GetServiceInfo returns the service build version, uptime, and queue
+// backend connectivity
+func (c *Client) GetServiceInfo(ctx context.Context) (*ServiceInfo, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info ServiceInfo
+	if err := c.parseResponse(resp, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// Component health states reported by the readiness endpoint
+const (
+	ComponentOK       = "ok"
+	ComponentDegraded = "degraded"
+	ComponentDown     = "down"
+)
+
+// ComponentStatus is the health of a single service dependency, such as the
+// broker, database, or callback dispatcher
+type ComponentStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+}
+
+// Healthy reports whether the component is fully operational
+func (s ComponentStatus) Healthy() bool {
+	return s.Status == ComponentOK
+}
+
+// ReadinessReport describes whether the service can accept traffic and the
+// state of each of its dependencies
+type ReadinessReport struct {
+	Status     string            `json:"status"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// Ready reports whether the service declared itself ready
+func (r *ReadinessReport) Ready() bool {
+	return r.Status == ComponentOK
+}
+
+// Degraded returns the components that are not fully operational
+func (r *ReadinessReport) Degraded() []ComponentStatus {
+	var degraded []ComponentStatus
+	for _, c := range r.Components {
+		if !c.Healthy() {
+			degraded = append(degraded, c)
+		}
+	}
+	return degraded
+}
+
+// Component returns the status of the named dependency
+func (r *ReadinessReport) Component(name string) (ComponentStatus, bool) {
+	for _, c := range r.Components {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ComponentStatus{}, false
+}
+
+// CheckReadiness queries the service's readiness endpoint. A service that is
+// not ready answers 503 with the same report, so both return a report; use
+// Ready and Degraded to inspect it
+func (c *Client) CheckReadiness(ctx context.Context) (*ReadinessReport, error) {
+	ctx, cancel := c.healthCheckContext(ctx)
+	defer cancel()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/health/ready", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, c.translateError(resp, body)
+	}
+
+	var report ReadinessReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if report.Status == "" {
+		report.Status = ComponentOK
+		if resp.StatusCode != http.StatusOK {
+			report.Status = ComponentDown
+		}
+	}
+
+	return &report, nil
+}
+
+// CheckLiveness queries the service's liveness endpoint, which only reports
+// whether the process is running, not whether its dependencies are reachable
+func (c *Client) CheckLiveness(ctx context.Context) (*HealthResponse, error) {
+	ctx, cancel := c.healthCheckContext(ctx)
+	defer cancel()
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/health/live", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.translateError(resp, body)
+	}
+
+	health := HealthResponse{Status: "OK"}
+	// The body may be JSON or plain text
+	json.Unmarshal(body, &health)
+	return &health, nil
+}
@@ -0,0 +1,174 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultProbeCacheTTL is how long HealthHandler and ReadinessHandler reuse
+// a check result unless ReadinessOptions.CacheTTL is set
+const DefaultProbeCacheTTL = 5 * time.Second
+
+// ReadinessOptions controls the checks made by ReadinessHandler
+type ReadinessOptions struct {
+	// CacheTTL is how long a result is served to probes without asking the
+	// service again; defaults to DefaultProbeCacheTTL, and a negative value
+	// checks on every probe
+	CacheTTL time.Duration
+	// Timeout bounds each check; defaults to the client's health check
+	// timeout, which also applies when Timeout is longer
+	Timeout time.Duration
+	// Required names the components, e.g. "broker", that must be healthy.
+	// When set, only these components decide readiness; otherwise the
+	// service's own readiness status does
+	Required []string
+	// AllowDegraded reports ready while the service, or a required
+	// component, is degraded rather than down
+	AllowDegraded bool
+}
+
+// withDefaults fills unset readiness options
+func (o ReadinessOptions) withDefaults(c *Client) ReadinessOptions {
+	if o.CacheTTL == 0 {
+		o.CacheTTL = DefaultProbeCacheTTL
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = c.healthCheckTimeout
+	}
+	return o
+}
+
+// ProbeResult is the body served by HealthHandler and ReadinessHandler
+type ProbeResult struct {
+	// Status is "ok" when the probe passes and "unavailable" otherwise
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Components lists the service dependencies that are not fully
+	// operational, for ReadinessHandler
+	Components []ComponentStatus `json:"components,omitempty"`
+	CheckedAt  time.Time         `json:"checked_at"`
+}
+
+// Probe outcomes
+const (
+	ProbeOK          = "ok"
+	ProbeUnavailable = "unavailable"
+)
+
+// probeCache serves the last check result until it expires. Probes
+// arriving while a check runs wait for it instead of starting their own
+type probeCache struct {
+	ttl     time.Duration
+	timeout time.Duration
+	check   func(ctx context.Context) ProbeResult
+	now     func() time.Time
+
+	mu     sync.Mutex
+	result *ProbeResult
+}
+
+// get returns the cached result, checking again once it has expired
+func (p *probeCache) get(ctx context.Context) ProbeResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.result != nil && p.now().Sub(p.result.CheckedAt) < p.ttl {
+		return *p.result
+	}
+
+	// The result is shared, so one probe giving up must not cut the check
+	// short for the others
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+	defer cancel()
+
+	result := p.check(ctx)
+	result.CheckedAt = p.now()
+	p.result = &result
+	return result
+}
+
+// ServeHTTP answers 200 when the probe passes and 503 otherwise, with the
+// ProbeResult as JSON
+func (p *probeCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := p.get(r.Context())
+
+	status := http.StatusOK
+	if result.Status != ProbeOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// HealthHandler returns an http.Handler for a liveness or health probe,
+// e.g. mounted under /healthz, that passes while the service answers its
+// health check. Results are cached for DefaultProbeCacheTTL and checks are
+// bounded by the client's health check timeout
+func HealthHandler(client *Client) http.Handler {
+	return &probeCache{
+		ttl:     DefaultProbeCacheTTL,
+		timeout: client.healthCheckTimeout,
+		now:     client.clock.Now,
+		check: func(ctx context.Context) ProbeResult {
+			if _, err := client.CheckHealth(ctx); err != nil {
+				return ProbeResult{Status: ProbeUnavailable, Error: err.Error()}
+			}
+			return ProbeResult{Status: ProbeOK}
+		},
+	}
+}
+
+// ReadinessHandler returns an http.Handler for a readiness probe, e.g.
+// mounted under /readyz, that passes while the service reports itself
+// ready, or while the components in opts.Required are healthy
+func ReadinessHandler(client *Client, opts ReadinessOptions) http.Handler {
+	opts = opts.withDefaults(client)
+	return &probeCache{
+		ttl:     opts.CacheTTL,
+		timeout: opts.Timeout,
+		now:     client.clock.Now,
+		check: func(ctx context.Context) ProbeResult {
+			report, err := client.CheckReadiness(ctx)
+			if err != nil {
+				return ProbeResult{Status: ProbeUnavailable, Error: err.Error()}
+			}
+			result := ProbeResult{Status: ProbeOK, Components: report.Degraded()}
+			if err := opts.evaluate(report); err != nil {
+				result.Status = ProbeUnavailable
+				result.Error = err.Error()
+			}
+			return result
+		},
+	}
+}
+
+// evaluate returns why report does not meet the options, or nil when the
+// service counts as ready
+func (o ReadinessOptions) evaluate(report *ReadinessReport) error {
+	acceptable := func(status string) bool {
+		return status == ComponentOK || o.AllowDegraded && status == ComponentDegraded
+	}
+
+	if len(o.Required) == 0 {
+		if !acceptable(report.Status) {
+			return fmt.Errorf("service is %s", report.Status)
+		}
+		return nil
+	}
+	for _, name := range o.Required {
+		component, ok := report.Component(name)
+		if !ok {
+			return fmt.Errorf("component '%s' is not reported", name)
+		}
+		if !acceptable(component.Status) {
+			return fmt.Errorf("component '%s' is %s", name, component.Status)
+		}
+	}
+	return nil
+}
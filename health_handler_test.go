@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler(t *testing.T) {
+	var checks atomic.Int32
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	handler := HealthHandler(NewClient(&Config{BaseURL: server.URL})).(*probeCache)
+	handler.now = func() time.Time { return now }
+
+	probe := func() (int, ProbeResult) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var result ProbeResult
+		json.NewDecoder(rec.Body).Decode(&result)
+		return rec.Code, result
+	}
+
+	if code, result := probe(); code != http.StatusOK || result.Status != ProbeOK {
+		t.Errorf("Expected a passing probe, got %d %+v", code, result)
+	}
+
+	// The cached result is served until it expires
+	healthy = false
+	if code, _ := probe(); code != http.StatusOK || checks.Load() != 1 {
+		t.Errorf("Expected the cached result, got %d after %d checks", code, checks.Load())
+	}
+
+	now = now.Add(DefaultProbeCacheTTL)
+	if code, result := probe(); code != http.StatusServiceUnavailable || result.Error == "" {
+		t.Errorf("Expected a failing probe, got %d %+v", code, result)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	report := ReadinessReport{Status: ComponentDegraded, Components: []ComponentStatus{
+		{Name: "broker", Status: ComponentOK},
+		{Name: "callbacks", Status: ComponentDegraded},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health/ready" {
+			t.Errorf("Expected path '/health/ready', got '%s'", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(report)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	tests := map[string]struct {
+		opts ReadinessOptions
+		code int
+	}{
+		"degraded":          {ReadinessOptions{}, http.StatusServiceUnavailable},
+		"degraded allowed":  {ReadinessOptions{AllowDegraded: true}, http.StatusOK},
+		"required healthy":  {ReadinessOptions{Required: []string{"broker"}}, http.StatusOK},
+		"required degraded": {ReadinessOptions{Required: []string{"broker", "callbacks"}}, http.StatusServiceUnavailable},
+		"required missing":  {ReadinessOptions{Required: []string{"database"}}, http.StatusServiceUnavailable},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ReadinessHandler(client, tt.opts).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.code {
+				t.Errorf("Expected %d, got %d: %s", tt.code, rec.Code, rec.Body)
+			}
+
+			var result ProbeResult
+			json.NewDecoder(rec.Body).Decode(&result)
+			if len(result.Components) != 1 || result.Components[0].Name != "callbacks" {
+				t.Errorf("Expected the degraded component to be listed, got %+v", result.Components)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ResponseHook rewrites a raw response body before it is unmarshaled, which
+// lets the client talk to older forks of the service that use different
+// field names or wrap payloads in envelopes
+type ResponseHook func(resp *http.Response, body []byte) ([]byte, error)
+
+// RenameFields returns a ResponseHook that renames JSON object keys at any
+// depth, mapping legacy names to the names this SDK expects
+func RenameFields(renames map[string]string) ResponseHook {
+	return func(resp *http.Response, body []byte) ([]byte, error) {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			// Not JSON (e.g. a plain text error), leave it untouched
+			return body, nil
+		}
+
+		return json.Marshal(renameKeys(value, renames))
+	}
+}
+
+// renameKeys walks value and renames map keys found in renames
+func renameKeys(value interface{}, renames map[string]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, inner := range v {
+			if newKey, ok := renames[key]; ok {
+				key = newKey
+			}
+			renamed[key] = renameKeys(inner, renames)
+		}
+		return renamed
+	case []interface{}:
+		for i := range v {
+			v[i] = renameKeys(v[i], renames)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// UnwrapEnvelope returns a ResponseHook that replaces a body of the form
+// {"<key>": {...}} with the inner value. Bodies without the key are left as is
+func UnwrapEnvelope(key string) ResponseHook {
+	return func(resp *http.Response, body []byte) ([]byte, error) {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return body, nil
+		}
+
+		inner, ok := envelope[key]
+		if !ok {
+			return body, nil
+		}
+		return inner, nil
+	}
+}
+
+// applyResponseHooks runs each hook over body in order
+func applyResponseHooks(hooks []ResponseHook, resp *http.Response, body []byte) ([]byte, error) {
+	for _, hook := range hooks {
+		var err error
+		body, err = hook(resp, body)
+		if err != nil {
+			return nil, fmt.Errorf("response hook failed: %w", err)
+		}
+	}
+	return body, nil
+}
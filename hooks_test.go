@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseHooksLegacyFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"message_id":"msg-1","status":"published","itemId":"pr-1"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		ResponseHooks: []ResponseHook{
+			UnwrapEnvelope("data"),
+			RenameFields(map[string]string{"message_id": "id"}),
+		},
+	})
+
+	resp, err := client.PostMessageWithDefaults(context.Background(), "pr-1", "https://example.com/callback", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.ID != "msg-1" {
+		t.Errorf("Expected ID 'msg-1', got '%s'", resp.ID)
+	}
+	if resp.ItemID != "pr-1" {
+		t.Errorf("Expected ItemID 'pr-1', got '%s'", resp.ItemID)
+	}
+}
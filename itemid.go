@@ -0,0 +1,161 @@
+package sdk
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// IDGenerator returns a new unique identifier on every call
+type IDGenerator func() string
+
+// ItemID returns a new identifier from g, prefixed with prefix and a dash
+// when prefix is not empty
+func (g IDGenerator) ItemID(prefix string) string {
+	if prefix == "" {
+		return g()
+	}
+	return prefix + "-" + g()
+}
+
+// GenerateItemID returns a new UUIDv7 item ID, prefixed with prefix and a
+// dash when prefix is not empty, e.g. "pr-01928c7e-...". Use the ItemID
+// method of another IDGenerator to pick a different format
+func GenerateItemID(prefix string) string {
+	return IDGenerator(UUIDv7).ItemID(prefix)
+}
+
+// uuidV7State makes UUIDv7 values generated by this process monotonic
+var uuidV7State struct {
+	sync.Mutex
+	ms  int64
+	seq uint16
+}
+
+// UUIDv7 returns a time-ordered RFC 9562 UUID. IDs generated within the same
+// millisecond use an increasing 12-bit counter, so they sort in generation
+// order; 62 random bits keep separate processes from colliding
+func UUIDv7() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	uuidV7State.Lock()
+	ms := time.Now().UnixMilli()
+	if ms <= uuidV7State.ms {
+		uuidV7State.seq++
+		if uuidV7State.seq > 0xfff {
+			// Counter exhausted: borrow the next millisecond
+			uuidV7State.ms++
+			uuidV7State.seq = 0
+		}
+		ms = uuidV7State.ms
+	} else {
+		uuidV7State.ms = ms
+		uuidV7State.seq = binary.BigEndian.Uint16(b[6:8]) & 0x7ff
+	}
+	seq := uuidV7State.seq
+	uuidV7State.Unlock()
+
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8)
+	b[7] = byte(seq)
+	b[8] = 0x80 | b[8]&0x3f
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID returns a 26-character, lexicographically time-ordered identifier
+// made of a 48-bit millisecond timestamp and 80 random bits
+func ULID() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	rand.Read(b[6:])
+
+	// 128 bits encode to 26 characters of 5 bits, the first holding only 3
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// SnowflakeEpoch is the zero time of snowflake IDs, 2020-01-01 UTC
+var SnowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// MaxSnowflakeNode is the largest node number a snowflake generator accepts
+const MaxSnowflakeNode = 1<<10 - 1
+
+// NewSnowflake returns a generator of decimal 63-bit snowflake IDs: 41 bits
+// of milliseconds since SnowflakeEpoch, a 10-bit node number, and a 12-bit
+// sequence. IDs are unique as long as every producing process uses a
+// distinct node; a generator that exhausts its sequence waits for the next
+// millisecond
+func NewSnowflake(node int) (IDGenerator, error) {
+	if node < 0 || node > MaxSnowflakeNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d, got %d", MaxSnowflakeNode, node)
+	}
+
+	var (
+		mu   sync.Mutex
+		last int64
+		seq  int64
+	)
+	epoch := SnowflakeEpoch.UnixMilli()
+
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ms := time.Now().UnixMilli() - epoch
+		if ms < last {
+			// The clock moved backwards; keep issuing from the last millisecond
+			ms = last
+		}
+		if ms == last {
+			seq = (seq + 1) & 0xfff
+			if seq == 0 {
+				for ms <= last {
+					time.Sleep(time.Millisecond)
+					ms = time.Now().UnixMilli() - epoch
+				}
+			}
+		} else {
+			seq = 0
+		}
+		last = ms
+
+		return strconv.FormatInt(ms<<22|int64(node)<<12|seq, 10)
+	}, nil
+}
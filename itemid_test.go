@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestGenerateItemID(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^pr-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id := GenerateItemID("pr")
+	if !uuidPattern.MatchString(id) {
+		t.Errorf("Expected prefixed UUIDv7, got '%s'", id)
+	}
+
+	ulidPattern := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	if id := IDGenerator(ULID).ItemID(""); !ulidPattern.MatchString(id) {
+		t.Errorf("Expected ULID, got '%s'", id)
+	}
+
+	if _, err := NewSnowflake(MaxSnowflakeNode + 1); err == nil {
+		t.Error("Expected error for out-of-range snowflake node")
+	}
+	snowflake, err := NewSnowflake(7)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, gen := range map[string]IDGenerator{"uuidv7": UUIDv7, "snowflake": snowflake} {
+		const n = 5000
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		seen := make(map[string]bool, n)
+		ordered := make([]string, 0, n)
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < n/4; i++ {
+					mu.Lock()
+					id := gen()
+					if seen[id] {
+						t.Errorf("%s: duplicate ID '%s'", name, id)
+					}
+					seen[id] = true
+					ordered = append(ordered, id)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		// IDs generated under the lock must already be in generation order
+		sorted := sort.StringsAreSorted(ordered)
+		if name == "snowflake" {
+			sorted = sort.SliceIsSorted(ordered, func(i, j int) bool {
+				a, _ := strconv.ParseInt(ordered[i], 10, 64)
+				b, _ := strconv.ParseInt(ordered[j], 10, 64)
+				return a < b
+			})
+		}
+		if !sorted {
+			t.Errorf("%s: expected monotonic IDs", name)
+		}
+	}
+}
+
+func TestAutoItemID(t *testing.T) {
+	var received BulkMessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(BulkMessageResponse{Status: "success"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, ItemIDGenerator: ULID, ItemIDPrefix: "job"})
+	req := &BulkMessageRequest{Messages: []MessageRequest{
+		*newMessageRequest("", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+		*newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil),
+	}}
+	if _, err := client.PostBulkMessages(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(received.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(received.Messages))
+	}
+	if id := received.Messages[0].ItemID; len(id) != len("job-")+26 || id[:4] != "job-" {
+		t.Errorf("Expected generated item ID, got '%s'", id)
+	}
+	if received.Messages[1].ItemID != "pr-1" {
+		t.Errorf("Expected explicit item ID to be kept, got '%s'", received.Messages[1].ItemID)
+	}
+	if req.Messages[0].ItemID != "" {
+		t.Error("Expected caller's request to be untouched")
+	}
+}
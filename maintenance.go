@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaintenanceWindow represents a scheduled period during which the workers
+// of a priority are paused and automatically resumed afterwards
+type MaintenanceWindow struct {
+	ID       string    `json:"id"`
+	Priority string    `json:"priority"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Status   string    `json:"status,omitempty"`
+}
+
+// ListMaintenanceWindowsResponse represents the response from listing maintenance windows
+type ListMaintenanceWindowsResponse struct {
+	Windows []MaintenanceWindow `json:"windows"`
+}
+
+// ScheduleMaintenanceWindow schedules the workers of a priority to pause at
+// from and resume at to
+func (c *Client) ScheduleMaintenanceWindow(ctx context.Context, priority string, from, to time.Time) (*MaintenanceWindow, error) {
+	if err := validateWorkerPriority(priority); err != nil {
+		return nil, err
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("maintenance window must end after it starts")
+	}
+
+	if !to.After(c.clock.Now()) {
+		return nil, fmt.Errorf("maintenance window must end in the future")
+	}
+
+	req := MaintenanceWindow{Priority: priority, From: from.UTC(), To: to.UTC()}
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/maintenance-windows", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var window MaintenanceWindow
+	if err := c.parseResponse(resp, &window); err != nil {
+		return nil, err
+	}
+
+	return &window, nil
+}
+
+// ListMaintenanceWindows returns the scheduled and active maintenance windows
+func (c *Client) ListMaintenanceWindows(ctx context.Context) ([]MaintenanceWindow, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/maintenance-windows", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var listResp ListMaintenanceWindowsResponse
+	if err := c.parseResponse(resp, &listResp); err != nil {
+		return nil, err
+	}
+
+	return listResp.Windows, nil
+}
+
+// CancelMaintenanceWindow cancels a scheduled window; cancelling an active
+// window resumes the paused workers immediately
+func (c *Client) CancelMaintenanceWindow(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("maintenance window id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/maintenance-windows/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.parseResponse(resp, nil)
+}
@@ -0,0 +1,60 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindows(t *testing.T) {
+	from := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	to := from.Add(2 * time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/maintenance-windows":
+			var window MaintenanceWindow
+			json.NewDecoder(r.Body).Decode(&window)
+			if window.Priority != "low" || !window.From.Equal(from) || !window.To.Equal(to) {
+				t.Errorf("Unexpected window request: %+v", window)
+			}
+			window.ID = "mw-1"
+			window.Status = "scheduled"
+			json.NewEncoder(w).Encode(window)
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(ListMaintenanceWindowsResponse{Windows: []MaintenanceWindow{{ID: "mw-1", Priority: "low"}}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/maintenance-windows/mw-1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	window, err := client.ScheduleMaintenanceWindow(ctx, "low", from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if window.ID != "mw-1" || window.Status != "scheduled" {
+		t.Errorf("Unexpected window: %+v", window)
+	}
+
+	windows, err := client.ListMaintenanceWindows(ctx)
+	if err != nil || len(windows) != 1 {
+		t.Errorf("Expected one window, got %v (%v)", windows, err)
+	}
+
+	if err := client.CancelMaintenanceWindow(ctx, "mw-1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if _, err := client.ScheduleMaintenanceWindow(ctx, "low", to, from); err == nil {
+		t.Error("Expected error for window ending before it starts, got nil")
+	}
+}
@@ -0,0 +1,293 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Defaults for polling helpers
+const (
+	DefaultPollInterval    = time.Second
+	DefaultPollMaxInterval = 30 * time.Second
+)
+
+// MessageStatus is the processing state of a message
+type MessageStatus string
+
+// Message statuses reported by the service
+const (
+	StatusQueued       MessageStatus = "queued"
+	StatusPublished    MessageStatus = "published"
+	StatusProcessing   MessageStatus = "processing"
+	StatusCompleted    MessageStatus = "completed"
+	StatusFailed       MessageStatus = "failed"
+	StatusDeadLettered MessageStatus = "dead_lettered"
+	// StatusTimedOut is the terminal status of a message whose callback
+	// processing exceeded its ProcessingTimeout
+	StatusTimedOut MessageStatus = "timed_out"
+	// StatusExpired is the terminal status of a message whose TTL ran out
+	// before it was processed
+	StatusExpired MessageStatus = "expired"
+	// UnknownStatus stands in for a status this version of the SDK does
+	// not recognize, e.g. one added to the service later
+	UnknownStatus MessageStatus = "unknown"
+)
+
+// ParseMessageStatus returns the status named s, or UnknownStatus when s
+// is not a known status. An empty s is returned as is
+func ParseMessageStatus(s string) MessageStatus {
+	switch status := MessageStatus(s); status {
+	case "", StatusQueued, StatusPublished, StatusProcessing, StatusCompleted,
+		StatusFailed, StatusDeadLettered, StatusTimedOut, StatusExpired:
+		return status
+	default:
+		return UnknownStatus
+	}
+}
+
+// UnmarshalJSON decodes a status, mapping unrecognized values to
+// UnknownStatus instead of failing
+func (s *MessageStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("message status: %w", err)
+	}
+	*s = ParseMessageStatus(raw)
+	return nil
+}
+
+// IsTerminal reports whether a message in this status will not change
+// status again
+func (s MessageStatus) IsTerminal() bool {
+	return s == StatusCompleted || s.IsFailure()
+}
+
+// IsFailure reports whether the status is a terminal state in which the
+// message was not processed successfully
+func (s MessageStatus) IsFailure() bool {
+	switch s {
+	case StatusFailed, StatusDeadLettered, StatusTimedOut, StatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// MessageDetail represents the processing state of a submitted message
+type MessageDetail struct {
+	ID          string        `json:"id"`
+	ItemID      string        `json:"item_id"`
+	Priority    Priority      `json:"priority"`
+	Topic       Topic         `json:"topic"`
+	Status      MessageStatus `json:"status"`
+	Attempts    int           `json:"attempts"`
+	CallbackURL string        `json:"callback_url"`
+	LastError   string        `json:"last_error,omitempty"`
+	CreatedAt   string        `json:"created_at"`
+	UpdatedAt   string        `json:"updated_at"`
+	CompletedAt string        `json:"completed_at,omitempty"`
+	// Metadata is the metadata the message was submitted with
+	Metadata map[string]string `json:"metadata,omitempty"`
+	GroupID  string            `json:"group_id,omitempty"`
+	// ExpiresAt is when the message expires if still unprocessed; empty
+	// when it was submitted without a TTL
+	ExpiresAt string `json:"expires_at,omitempty"`
+	Tenant    string `json:"tenant,omitempty"`
+	// CorrelationID is the correlation ID the message was submitted with
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// IsTerminal reports whether the message has reached a final state
+func (d *MessageDetail) IsTerminal() bool {
+	return d.Status.IsTerminal()
+}
+
+// TimedOut reports whether processing failed because it exceeded the
+// message's ProcessingTimeout
+func (d *MessageDetail) TimedOut() bool {
+	return d.Status == StatusTimedOut
+}
+
+// Expired reports whether the message's TTL ran out before it was processed
+func (d *MessageDetail) Expired() bool {
+	return d.Status == StatusExpired
+}
+
+// PollOptions controls how polling helpers wait between requests
+type PollOptions struct {
+	// Interval is the delay before the second poll; defaults to DefaultPollInterval
+	Interval time.Duration
+	// Backoff multiplies the interval after each poll; values <= 1 keep it fixed
+	Backoff float64
+	// MaxInterval caps the delay between polls; defaults to DefaultPollMaxInterval
+	MaxInterval time.Duration
+	// Confirmations is the number of consecutive polls that must observe
+	// the awaited state, guarding against a momentary reading; defaults to
+	// 1. Used by WaitForQueueEmpty
+	Confirmations int
+	// OnProgress, when set, is called after every poll with the amount of
+	// work left, such as the queue depth. Used by WaitForQueueEmpty
+	OnProgress func(remaining int)
+}
+
+// withDefaults fills unset poll options
+func (o PollOptions) withDefaults() PollOptions {
+	if o.Interval <= 0 {
+		o.Interval = DefaultPollInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultPollMaxInterval
+	}
+	if o.Confirmations <= 0 {
+		o.Confirmations = 1
+	}
+	return o
+}
+
+// next returns the interval to use after the given one
+func (o PollOptions) next(interval time.Duration) time.Duration {
+	if o.Backoff > 1 {
+		interval = time.Duration(float64(interval) * o.Backoff)
+	}
+	if interval > o.MaxInterval {
+		interval = o.MaxInterval
+	}
+	return interval
+}
+
+// poll calls check until it reports done, returns an error, or ctx expires
+func (c *Client) poll(ctx context.Context, opts PollOptions, check func() (bool, error)) error {
+	opts = opts.withDefaults()
+	interval := opts.Interval
+
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		if err := sleep(ctx, c.clock, interval); err != nil {
+			return err
+		}
+
+		interval = opts.next(interval)
+	}
+}
+
+// GetMessage returns the current processing state of a message
+func (c *Client) GetMessage(ctx context.Context, id string) (*MessageDetail, error) {
+	if id == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/messages/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail MessageDetail
+	if err := c.parseResponse(resp, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// WaitForMessage polls the message until it reaches a terminal state
+// (completed, failed, dead-lettered, or timed out) or ctx expires, and returns the final
+// detail. On error the last detail observed, if any, is returned as well
+func (c *Client) WaitForMessage(ctx context.Context, id string, opts PollOptions) (*MessageDetail, error) {
+	var detail *MessageDetail
+	err := c.poll(ctx, opts, func() (bool, error) {
+		current, err := c.GetMessage(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		detail = current
+		return detail.IsTerminal(), nil
+	})
+
+	// The last known detail is returned even on error so callers can report progress
+	return detail, err
+}
+
+// MaxStatusBatchSize is the largest number of IDs GetMessageStatuses
+// looks up in one request
+const MaxStatusBatchSize = 500
+
+// MessageStatuses is the result of a batch status lookup
+type MessageStatuses struct {
+	// Messages maps the ID of every message found to its detail
+	Messages map[string]*MessageDetail
+	// Missing lists the IDs the service has no message for, e.g. because
+	// they were purged or never existed
+	Missing []string
+}
+
+// Status returns the status of the message with id, and whether it was found
+func (s *MessageStatuses) Status(id string) (MessageStatus, bool) {
+	detail, ok := s.Messages[id]
+	if !ok {
+		return "", false
+	}
+	return detail.Status, true
+}
+
+// messageStatusesRequest is the body of a batch status lookup
+type messageStatusesRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// messageStatusesResponse is the service's answer to a batch status lookup
+type messageStatusesResponse struct {
+	Messages []MessageDetail `json:"messages"`
+	Missing  []string        `json:"missing"`
+}
+
+// GetMessageStatuses looks up many messages at once, e.g. to follow up on a
+// bulk submission, splitting ids into requests of at most
+// MaxStatusBatchSize. IDs the service does not know are listed in Missing
+// rather than failing the lookup. If a request fails, the statuses found by
+// the earlier requests are returned along with the error
+func (c *Client) GetMessageStatuses(ctx context.Context, ids []string) (*MessageStatuses, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no message ids provided")
+	}
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for i, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("ids[%d]: message id is required", i)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	statuses := &MessageStatuses{Messages: make(map[string]*MessageDetail, len(unique))}
+	for start := 0; start < len(unique); start += MaxStatusBatchSize {
+		batch := unique[start:min(start+MaxStatusBatchSize, len(unique))]
+
+		resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/status", messageStatusesRequest{IDs: batch})
+		if err != nil {
+			return statuses, err
+		}
+		var result messageStatusesResponse
+		if err := c.parseResponse(resp, &result); err != nil {
+			return statuses, err
+		}
+
+		for i := range result.Messages {
+			statuses.Messages[result.Messages[i].ID] = &result.Messages[i]
+		}
+		statuses.Missing = append(statuses.Missing, result.Missing...)
+	}
+
+	return statuses, nil
+}
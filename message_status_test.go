@@ -0,0 +1,186 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWaitForMessage(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/messages/msg-1" {
+			t.Errorf("Expected path '/api/v1/messages/msg-1', got '%s'", r.URL.Path)
+		}
+
+		polls++
+		status := StatusProcessing
+		if polls == 3 {
+			status = StatusCompleted
+		}
+		json.NewEncoder(w).Encode(MessageDetail{ID: "msg-1", Status: status, Attempts: 1})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	detail, err := client.WaitForMessage(context.Background(), "msg-1", PollOptions{Interval: time.Millisecond, Backoff: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if detail.Status != "completed" {
+		t.Errorf("Expected status 'completed', got '%s'", detail.Status)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", polls)
+	}
+}
+
+func TestWaitForMessageContextExpires(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(MessageDetail{ID: "msg-1", Status: "queued"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	detail, err := client.WaitForMessage(ctx, "msg-1", PollOptions{Interval: 5 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if detail == nil || detail.Status != "queued" {
+		t.Errorf("Expected last known detail to be returned, got %+v", detail)
+	}
+}
+
+func TestMessageStatus(t *testing.T) {
+	tests := []struct {
+		status   MessageStatus
+		terminal bool
+		failure  bool
+	}{
+		{StatusQueued, false, false},
+		{StatusProcessing, false, false},
+		{StatusCompleted, true, false},
+		{StatusFailed, true, true},
+		{StatusDeadLettered, true, true},
+		{StatusTimedOut, true, true},
+		{StatusExpired, true, true},
+		{UnknownStatus, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.IsTerminal(); got != tt.terminal {
+			t.Errorf("Expected %s IsTerminal=%v, got %v", tt.status, tt.terminal, got)
+		}
+		if got := tt.status.IsFailure(); got != tt.failure {
+			t.Errorf("Expected %s IsFailure=%v, got %v", tt.status, tt.failure, got)
+		}
+	}
+}
+
+func TestMessageStatusUnknown(t *testing.T) {
+	var detail MessageDetail
+	if err := json.Unmarshal([]byte(`{"id":"msg-1","status":"quarantined"}`), &detail); err != nil {
+		t.Fatalf("Expected an unknown status to decode, got %v", err)
+	}
+	if detail.Status != UnknownStatus {
+		t.Errorf("Expected '%s', got '%s'", UnknownStatus, detail.Status)
+	}
+
+	var event MessageEvent
+	if err := json.Unmarshal([]byte(`{"type":"failed","status":"dead_lettered"}`), &event); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if event.Status != StatusDeadLettered {
+		t.Errorf("Expected '%s', got '%s'", StatusDeadLettered, event.Status)
+	}
+
+	if status := ParseMessageStatus(""); status != "" {
+		t.Errorf("Expected an empty status to stay empty, got '%s'", status)
+	}
+}
+
+func TestGetMessageStatuses(t *testing.T) {
+	var batches [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/messages/status" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req messageStatusesRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		batches = append(batches, req.IDs)
+
+		var resp messageStatusesResponse
+		for _, id := range req.IDs {
+			if id == "msg-gone" {
+				resp.Missing = append(resp.Missing, id)
+				continue
+			}
+			resp.Messages = append(resp.Messages, MessageDetail{ID: id, Status: StatusCompleted})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	ids := []string{"msg-gone"}
+	for i := 0; i < MaxStatusBatchSize+10; i++ {
+		ids = append(ids, fmt.Sprintf("msg-%d", i))
+	}
+	ids = append(ids, "msg-0")
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	statuses, err := client.GetMessageStatuses(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetMessageStatuses failed: %v", err)
+	}
+
+	if len(batches) != 2 || len(batches[0]) != MaxStatusBatchSize || len(batches[1]) != 11 {
+		t.Errorf("Expected 2 batches of unique IDs, got %d", len(batches))
+	}
+	if len(statuses.Messages) != MaxStatusBatchSize+10 {
+		t.Errorf("Expected %d messages, got %d", MaxStatusBatchSize+10, len(statuses.Messages))
+	}
+	if status, ok := statuses.Status("msg-7"); !ok || status != StatusCompleted {
+		t.Errorf("Expected msg-7 to be completed, got '%s'", status)
+	}
+	if _, ok := statuses.Status("msg-gone"); ok || len(statuses.Missing) != 1 || statuses.Missing[0] != "msg-gone" {
+		t.Errorf("Expected msg-gone to be missing, got %v", statuses.Missing)
+	}
+}
+
+func TestGetMessageStatusesPartialFailure(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(messageStatusesResponse{Messages: []MessageDetail{{ID: "msg-0", Status: StatusQueued}}})
+	}))
+	defer server.Close()
+
+	ids := make([]string, MaxStatusBatchSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("msg-%d", i)
+	}
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	statuses, err := client.GetMessageStatuses(context.Background(), ids)
+	if !IsAPIError(err) {
+		t.Errorf("Expected an APIError, got %v", err)
+	}
+	if statuses == nil || len(statuses.Messages) != 1 {
+		t.Errorf("Expected the first batch's statuses to be returned, got %+v", statuses)
+	}
+
+	if _, err := client.GetMessageStatuses(context.Background(), []string{"msg-1", ""}); err == nil {
+		t.Error("Expected an error for an empty id")
+	}
+}
@@ -2,8 +2,11 @@ package sdk
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // Priority represents the priority level of a message
@@ -29,15 +32,126 @@ type MessageRequest struct {
 	Topic       Topic       `json:"topic"`
 	CallbackURL string      `json:"callback_url"`
 	ObjectBody  interface{} `json:"object_body"`
+	// Team and CostCenter attribute queue usage and worker cost to the
+	// producing team; the client's defaults are used when they are empty
+	Team       string `json:"team,omitempty"`
+	CostCenter string `json:"cost_center,omitempty"`
+	// ProcessingTimeout bounds how long a worker may spend delivering the
+	// callback; zero uses the service default. Sent as processing_timeout_ms
+	ProcessingTimeout time.Duration `json:"-"`
+	// Budget is the end-to-end latency budget measured from submission;
+	// workers forward what remains of it to the callback in BudgetHeader.
+	// Sent as budget_ms
+	Budget time.Duration `json:"-"`
+	// TTL is how long the message stays valid after submission; the service
+	// expires messages not yet processed by then instead of delivering them
+	// late. Zero never expires. Sent as ttl_ms
+	TTL time.Duration `json:"-"`
+	// RetryPolicy controls how workers retry the message's callback; nil
+	// uses the topic's default policy and then the service's
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// Metadata is echoed back in responses and callbacks; metadata attached
+	// to the context with WithMetadata is merged in on submission
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// GroupID is an ordering key: messages with the same group are processed
+	// one at a time in submission order, while different groups proceed in
+	// parallel. Empty leaves the message unordered
+	GroupID string `json:"group_id,omitempty"`
+	// CorrelationID ties the message to its callback in the caller's own
+	// systems; the service echoes it in responses, status lookups, events,
+	// and callbacks. A UUIDv7 is generated on submission when it is empty
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// callbackErr is why a callback URL expanded from the client's template
+	// is unusable
+	callbackErr error
+}
+
+// BudgetHeader carries the remaining latency budget, in milliseconds, on callbacks
+const BudgetHeader = "X-Messages-Budget-Remaining-Ms"
+
+// messageRequestJSON is the wire form of MessageRequest
+type messageRequestJSON struct {
+	messageRequestAlias
+	ProcessingTimeoutMs int64 `json:"processing_timeout_ms,omitempty"`
+	BudgetMs            int64 `json:"budget_ms,omitempty"`
+	TTLMs               int64 `json:"ttl_ms,omitempty"`
+}
+
+// messageRequestAlias has the fields of MessageRequest without its methods
+type messageRequestAlias MessageRequest
+
+// MarshalJSON encodes durations as integer milliseconds
+func (r MessageRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.wire())
+}
+
+// wire returns the wire form of r
+func (r MessageRequest) wire() messageRequestJSON {
+	return messageRequestJSON{
+		messageRequestAlias: messageRequestAlias(r),
+		ProcessingTimeoutMs: r.ProcessingTimeout.Milliseconds(),
+		BudgetMs:            r.Budget.Milliseconds(),
+		TTLMs:               r.TTL.Milliseconds(),
+	}
+}
+
+// bulkMessageRequestJSON is the wire form of BulkMessageRequest
+type bulkMessageRequestJSON struct {
+	Messages []messageRequestJSON `json:"messages"`
+}
+
+// wireForm returns the wire form of the message submissions among request
+// bodies, and other bodies as they are. Encoding the wire form directly
+// produces the same JSON as going through MarshalJSON, without encoding
+// every message twice
+func wireForm(body interface{}) interface{} {
+	switch b := body.(type) {
+	case *MessageRequest:
+		return b.wire()
+	case *BulkMessageRequest:
+		if b.Messages == nil {
+			return body
+		}
+		wire := bulkMessageRequestJSON{Messages: make([]messageRequestJSON, len(b.Messages))}
+		for i := range b.Messages {
+			wire.Messages[i] = b.Messages[i].wire()
+		}
+		return wire
+	default:
+		return body
+	}
+}
+
+// UnmarshalJSON decodes durations from integer milliseconds
+func (r *MessageRequest) UnmarshalJSON(data []byte) error {
+	var wire messageRequestJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*r = MessageRequest(wire.messageRequestAlias)
+	r.ProcessingTimeout = time.Duration(wire.ProcessingTimeoutMs) * time.Millisecond
+	r.Budget = time.Duration(wire.BudgetMs) * time.Millisecond
+	r.TTL = time.Duration(wire.TTLMs) * time.Millisecond
+	return nil
 }
 
 // MessageResponse represents the response for a single message
 type MessageResponse struct {
-	ID       string   `json:"id"`
-	Status   string   `json:"status"`
-	ItemID   string   `json:"itemId"`
-	Priority Priority `json:"priority"`
-	Topic    Topic    `json:"topic"`
+	ID       string            `json:"id"`
+	Status   MessageStatus     `json:"status"`
+	ItemID   string            `json:"itemId"`
+	Priority Priority          `json:"priority"`
+	Topic    Topic             `json:"topic"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tenant   string            `json:"tenant,omitempty"`
+	// CorrelationID is the correlation ID the message was submitted with
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Deduplicated reports that the message was not posted because the
+	// client's deduplication cache holds a submission of the same item and
+	// topic; the response is that submission's
+	Deduplicated bool `json:"-"`
 }
 
 // BulkMessageRequest represents a request to post multiple messages
@@ -45,11 +159,72 @@ type BulkMessageRequest struct {
 	Messages []MessageRequest `json:"messages"`
 }
 
+// BulkMessageError describes a single message rejected from a bulk request
+type BulkMessageError struct {
+	Index  int    `json:"index"`
+	ItemID string `json:"item_id"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e BulkMessageError) Error() string {
+	return fmt.Sprintf("message %d (%s) rejected: %s: %s", e.Index, e.ItemID, e.Code, e.Reason)
+}
+
 // BulkMessageResponse represents the response for bulk messages
 type BulkMessageResponse struct {
-	Status   string            `json:"status"`
-	Count    int               `json:"count"`
-	Messages []MessageResponse `json:"messages"`
+	Status   string             `json:"status"`
+	Count    int                `json:"count"`
+	Messages []MessageResponse  `json:"messages"`
+	Errors   []BulkMessageError `json:"errors,omitempty"`
+
+	// indexes holds the index in the request of each entry of Messages, or
+	// -1 when it is not known; nil until matched with the request
+	indexes []int
+}
+
+// Failed returns the messages rejected by the service
+func (r *BulkMessageResponse) Failed() []BulkMessageError {
+	return r.Errors
+}
+
+// Succeeded returns the messages accepted by the service
+func (r *BulkMessageResponse) Succeeded() []MessageResponse {
+	if len(r.Errors) == 0 || r.indexes == nil {
+		// Without the request, Messages is taken to list only the
+		// accepted messages
+		return r.Messages
+	}
+
+	failed := make(map[int]bool, len(r.Errors))
+	for _, e := range r.Errors {
+		failed[e.Index] = true
+	}
+
+	succeeded := make([]MessageResponse, 0, len(r.Messages))
+	for i, m := range r.Messages {
+		if !failed[r.indexes[i]] {
+			succeeded = append(succeeded, m)
+		}
+	}
+	return succeeded
+}
+
+// BulkPartialError is returned alongside the response when only some
+// messages of a bulk request were accepted
+type BulkPartialError struct {
+	Response *BulkMessageResponse
+}
+
+func (e *BulkPartialError) Error() string {
+	return fmt.Sprintf("bulk request partially failed: %d of %d messages rejected",
+		len(e.Response.Errors), len(e.Response.Errors)+len(e.Response.Succeeded()))
+}
+
+// IsBulkPartialError checks if an error is a bulk partial failure
+func IsBulkPartialError(err error) bool {
+	var partial *BulkPartialError
+	return errors.As(err, &partial)
 }
 
 // PostMessage submits a single message for processing
@@ -58,6 +233,31 @@ func (c *Client) PostMessage(ctx context.Context, req *MessageRequest) (*Message
 		return nil, fmt.Errorf("message request cannot be nil")
 	}
 
+	req = c.withDefaults(ctx, req)
+	if err := c.preparePayload(ctx, "", req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if c.dedupCache != nil {
+		return c.dedupCache.do(ctx, dedupKey(c.tenant, req), func() (*MessageResponse, error) {
+			return c.postMessage(ctx, req)
+		})
+	}
+	return c.postMessage(ctx, req)
+}
+
+// postMessage submits a message that has been validated
+func (c *Client) postMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if err := c.applyBackpressure(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if c.transport != nil {
+		return c.postMessageTransport(ctx, req)
+	}
+
 	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages", req)
 	if err != nil {
 		return nil, err
@@ -67,11 +267,32 @@ func (c *Client) PostMessage(ctx context.Context, req *MessageRequest) (*Message
 	if err := c.parseResponse(resp, &messageResp); err != nil {
 		return nil, err
 	}
+	if messageResp.CorrelationID == "" {
+		messageResp.CorrelationID = req.CorrelationID
+	}
 
 	return &messageResp, nil
 }
 
-// PostBulkMessages submits multiple messages for processing
+// PostOrderedMessage submits a message in the ordering group groupID, e.g. a
+// repository's full name, so that it is processed only after every message
+// submitted earlier to the same group
+func (c *Client) PostOrderedMessage(ctx context.Context, groupID string, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	if groupID == "" {
+		return nil, fmt.Errorf("group id is required")
+	}
+
+	ordered := *req
+	ordered.GroupID = groupID
+	return c.PostMessage(ctx, &ordered)
+}
+
+// PostBulkMessages submits multiple messages for processing. When some
+// messages are rejected it returns the response together with a *BulkPartialError
 func (c *Client) PostBulkMessages(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
 	if req == nil {
 		return nil, fmt.Errorf("bulk message request cannot be nil")
@@ -81,6 +302,23 @@ func (c *Client) PostBulkMessages(ctx context.Context, req *BulkMessageRequest)
 		return nil, fmt.Errorf("no messages provided")
 	}
 
+	req = c.withBulkDefaults(ctx, req)
+	for i := range req.Messages {
+		if err := c.preparePayload(ctx, fmt.Sprintf("messages[%d].", i), &req.Messages[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.applyBulkBackpressure(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if c.transport != nil {
+		return c.postBulkMessagesTransport(ctx, req)
+	}
+
 	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/messages/bulk", req)
 	if err != nil {
 		return nil, err
@@ -90,46 +328,156 @@ func (c *Client) PostBulkMessages(ctx context.Context, req *BulkMessageRequest)
 	if err := c.parseResponse(resp, &bulkResp); err != nil {
 		return nil, err
 	}
+	fillCorrelationIDs(&bulkResp, req)
+
+	// 207 Multi-Status signals mixed results; the response is still returned
+	// so callers can inspect which messages were accepted
+	if resp.StatusCode == http.StatusMultiStatus || len(bulkResp.Errors) > 0 {
+		return &bulkResp, &BulkPartialError{Response: &bulkResp}
+	}
 
 	return &bulkResp, nil
 }
 
-// PostMessageWithDefaults creates a message request with default values and submits it
-func (c *Client) PostMessageWithDefaults(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
-	req := &MessageRequest{
-		ItemID:      itemID,
-		Priority:    PriorityMedium,
-		Topic:       TopicPullRequests,
-		CallbackURL: callbackURL,
-		ObjectBody:  objectBody,
+// withDefaults returns a copy of req with empty fields filled from the
+// defaults of its topic and then the client's, a generated ItemID when the
+// client has a generator, a generated CorrelationID, a callback URL from the
+// client's template, and the context's metadata merged in, leaving the
+// caller's request untouched
+func (c *Client) withDefaults(ctx context.Context, req *MessageRequest) *MessageRequest {
+	out := *req
+	withContextMetadata(ctx, &out)
+
+	if out.ItemID == "" && c.itemIDGenerator != nil {
+		out.ItemID = c.itemIDGenerator.ItemID(c.itemIDPrefix)
+	}
+	if out.CorrelationID == "" {
+		out.CorrelationID = UUIDv7()
+	}
+
+	defaults := c.TopicDefaults(out.Topic)
+	if out.Priority == "" {
+		out.Priority = defaults.Priority
 	}
+	if out.CallbackURL == "" {
+		out.CallbackURL = defaults.CallbackURL
+	}
+	if out.ProcessingTimeout == 0 {
+		out.ProcessingTimeout = defaults.ProcessingTimeout
+	}
+	if out.Budget == 0 {
+		out.Budget = defaults.Budget
+	}
+	if out.TTL == 0 {
+		out.TTL = defaults.TTL
+	}
+	if out.RetryPolicy == nil {
+		out.RetryPolicy = defaults.RetryPolicy
+	}
+	if out.RetryPolicy != nil {
+		policy := out.RetryPolicy.withDefaults()
+		out.RetryPolicy = &policy
+	}
+	c.expandCallbackURL(&out)
 
-	return c.PostMessage(ctx, req)
+	if out.Team == "" {
+		out.Team = c.team
+	}
+	if out.CostCenter == "" {
+		out.CostCenter = c.costCenter
+	}
+	return &out
 }
 
-// PostHighPriorityMessage submits a high priority message
-func (c *Client) PostHighPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
-	req := &MessageRequest{
-		ItemID:      itemID,
-		Priority:    PriorityHigh,
-		Topic:       TopicPullRequests,
-		CallbackURL: callbackURL,
-		ObjectBody:  objectBody,
+// withBulkDefaults applies withDefaults to every message of a bulk request
+func (c *Client) withBulkDefaults(ctx context.Context, req *BulkMessageRequest) *BulkMessageRequest {
+	out := *req
+	out.Messages = make([]MessageRequest, len(req.Messages))
+	for i := range req.Messages {
+		out.Messages[i] = *c.withDefaults(ctx, &req.Messages[i])
 	}
+	return &out
+}
 
-	return c.PostMessage(ctx, req)
+// fillCorrelationIDs matches the messages of resp with those of req and
+// sets the correlation ID of the accepted messages that the service did not
+// echo one for, from the request message they answer
+func fillCorrelationIDs(resp *BulkMessageResponse, req *BulkMessageRequest) {
+	resp.indexes = matchBulkResponse(resp, req)
+	for i := range resp.Messages {
+		if index := resp.indexes[i]; index >= 0 && resp.Messages[i].CorrelationID == "" {
+			resp.Messages[i].CorrelationID = req.Messages[index].CorrelationID
+		}
+	}
 }
 
-// PostLowPriorityMessage submits a low priority message
-func (c *Client) PostLowPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
-	req := &MessageRequest{
+// matchBulkResponse returns the index in req of each entry of resp.Messages,
+// or -1 when it cannot be told. An item ID that appears once in req
+// identifies its message; otherwise entries are matched by position, as the
+// service lists either every message of the request in order or only the
+// accepted ones, skipping the indexes of resp.Errors
+func matchBulkResponse(resp *BulkMessageResponse, req *BulkMessageRequest) []int {
+	byItem := make(map[string]int, len(req.Messages))
+	for i, m := range req.Messages {
+		if _, ok := byItem[m.ItemID]; ok {
+			byItem[m.ItemID] = -1
+		} else {
+			byItem[m.ItemID] = i
+		}
+	}
+
+	failed := make(map[int]bool, len(resp.Errors))
+	for _, e := range resp.Errors {
+		failed[e.Index] = true
+	}
+	var positions []int
+	switch {
+	case len(resp.Messages) == len(req.Messages):
+		for i := range req.Messages {
+			positions = append(positions, i)
+		}
+	case len(resp.Messages)+len(failed) == len(req.Messages):
+		for i := range req.Messages {
+			if !failed[i] {
+				positions = append(positions, i)
+			}
+		}
+	}
+
+	indexes := make([]int, len(resp.Messages))
+	for i, m := range resp.Messages {
+		indexes[i] = -1
+		if index, ok := byItem[m.ItemID]; ok && m.ItemID != "" && index >= 0 {
+			indexes[i] = index
+		} else if positions != nil {
+			indexes[i] = positions[i]
+		}
+	}
+	return indexes
+}
+
+// newMessageRequest builds a message request from its individual fields
+func newMessageRequest(itemID string, priority Priority, topic Topic, callbackURL string, objectBody interface{}) *MessageRequest {
+	return &MessageRequest{
 		ItemID:      itemID,
-		Priority:    PriorityLow,
-		Topic:       TopicPullRequests,
+		Priority:    priority,
+		Topic:       topic,
 		CallbackURL: callbackURL,
 		ObjectBody:  objectBody,
 	}
+}
+
+// PostMessageWithDefaults creates a message request with default values and submits it
+func (c *Client) PostMessageWithDefaults(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return c.Topic(TopicPullRequests).PostMessageWithDefaults(ctx, itemID, callbackURL, objectBody)
+}
 
-	return c.PostMessage(ctx, req)
+// PostHighPriorityMessage submits a high priority message
+func (c *Client) PostHighPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return c.Topic(TopicPullRequests).PostHighPriorityMessage(ctx, itemID, callbackURL, objectBody)
 }
 
+// PostLowPriorityMessage submits a low priority message
+func (c *Client) PostLowPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return c.Topic(TopicPullRequests).PostLowPriorityMessage(ctx, itemID, callbackURL, objectBody)
+}
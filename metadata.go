@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// MetadataHeaderPrefix prefixes the headers that carry context metadata on
+// requests, and that workers echo on callbacks
+const MetadataHeaderPrefix = "X-Meta-"
+
+type metadataKey struct{}
+
+// WithMetadata returns a context carrying md, merged over any metadata
+// already in ctx. The client forwards it as X-Meta-<key> headers on every
+// request and in the Metadata of submitted messages. Keys are
+// case-insensitive and stored lowercase; keys other than letters, digits,
+// and dashes, and values with line breaks, are ignored
+func WithMetadata(ctx context.Context, md map[string]string) context.Context {
+	merged := make(map[string]string)
+	for k, v := range MetadataFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range md {
+		if validMetadata(k, v) {
+			merged[strings.ToLower(k)] = v
+		}
+	}
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+// MetadataFromContext returns the metadata attached with WithMetadata; the
+// map must not be modified
+func MetadataFromContext(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return md
+}
+
+// MetadataFromHeader extracts metadata from X-Meta-<key> headers
+func MetadataFromHeader(header http.Header) map[string]string {
+	var md map[string]string
+	for name, values := range header {
+		if len(values) == 0 || len(name) <= len(MetadataHeaderPrefix) || !strings.EqualFold(name[:len(MetadataHeaderPrefix)], MetadataHeaderPrefix) {
+			continue
+		}
+		if md == nil {
+			md = make(map[string]string)
+		}
+		md[strings.ToLower(name[len(MetadataHeaderPrefix):])] = values[0]
+	}
+	return md
+}
+
+// setMetadataHeaders adds the context's metadata to header
+func setMetadataHeaders(ctx context.Context, header http.Header) {
+	for k, v := range MetadataFromContext(ctx) {
+		header.Set(MetadataHeaderPrefix+k, v)
+	}
+}
+
+// withContextMetadata fills metadata keys missing from req with those of the
+// context; keys set on the request win
+func withContextMetadata(ctx context.Context, req *MessageRequest) {
+	md := MetadataFromContext(ctx)
+	if len(md) == 0 {
+		return
+	}
+
+	merged := make(map[string]string, len(md)+len(req.Metadata))
+	for k, v := range md {
+		merged[k] = v
+	}
+	for k, v := range req.Metadata {
+		merged[k] = v
+	}
+	req.Metadata = merged
+}
+
+// validMetadata reports whether a metadata pair can be sent as a header
+func validMetadata(key, value string) bool {
+	if key == "" || strings.ContainsAny(value, "\r\n") {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
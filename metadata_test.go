@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextMetadata(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Meta-Request-Id") != "req-42" || r.Header.Get("X-Meta-Tenant") != "acme" {
+			t.Errorf("Expected metadata headers, got %v", r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", Metadata: received.Metadata})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+
+	ctx := WithMetadata(context.Background(), map[string]string{"Request-ID": "req-1", "tenant": "acme"})
+	ctx = WithMetadata(ctx, map[string]string{"request-id": "req-42", "bad key": "x", "actor": "line\nbreak"})
+
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	req.Metadata = map[string]string{"tenant": "acme", "source": "webhook"}
+
+	resp, err := client.PostMessage(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[string]string{"request-id": "req-42", "tenant": "acme", "source": "webhook"}
+	if len(received.Metadata) != len(want) {
+		t.Fatalf("Expected metadata %v, got %v", want, received.Metadata)
+	}
+	for k, v := range want {
+		if received.Metadata[k] != v || resp.Metadata[k] != v {
+			t.Errorf("Expected %s=%s, got %v (echoed %v)", k, v, received.Metadata, resp.Metadata)
+		}
+	}
+	if len(req.Metadata) != 2 {
+		t.Errorf("Expected caller's request to be untouched, got %v", req.Metadata)
+	}
+
+	header := http.Header{}
+	header.Set("X-Meta-Request-Id", "req-42")
+	header.Set("X-Team", "platform")
+	if md := MetadataFromHeader(header); len(md) != 1 || md["request-id"] != "req-42" {
+		t.Errorf("Unexpected metadata from header: %v", md)
+	}
+}
@@ -0,0 +1,155 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Operation states reported by the service
+const (
+	OperationPending   = "pending"
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+)
+
+// OperationStatus represents the state of a long-running operation
+type OperationStatus struct {
+	ID     string          `json:"id"`
+	Kind   string          `json:"kind"`
+	State  string          `json:"state"`
+	Error  string          `json:"error,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// Done reports whether the operation has finished
+func (s *OperationStatus) Done() bool {
+	return s.State == OperationSucceeded || s.State == OperationFailed
+}
+
+// OperationError is returned when a long-running operation finishes unsuccessfully
+type OperationError struct {
+	ID      string
+	Kind    string
+	Message string
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s (%s) failed: %s", e.ID, e.Kind, e.Message)
+}
+
+// Operation is a handle to a destructive operation that the service may
+// process asynchronously. Operations the service completed synchronously
+// are returned already done
+type Operation struct {
+	client *Client
+	kind   string
+	status OperationStatus
+}
+
+// ID returns the server-assigned operation ID, empty for synchronous operations
+func (o *Operation) ID() string {
+	return o.status.ID
+}
+
+// Status refreshes and returns the operation state
+func (o *Operation) Status(ctx context.Context) (*OperationStatus, error) {
+	if o.status.Done() || o.status.ID == "" {
+		status := o.status
+		return &status, nil
+	}
+
+	resp, err := o.client.doRequest(ctx, http.MethodGet, "/api/v1/operations/"+url.PathEscape(o.status.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status OperationStatus
+	if err := o.client.parseResponse(resp, &status); err != nil {
+		return nil, err
+	}
+	if status.Kind == "" {
+		status.Kind = o.kind
+	}
+
+	o.status = status
+	return &status, nil
+}
+
+// Wait polls the operation until it finishes or ctx expires. It returns an
+// *OperationError when the operation failed
+func (o *Operation) Wait(ctx context.Context, opts PollOptions) (*OperationStatus, error) {
+	var status *OperationStatus
+	err := o.client.poll(ctx, opts, func() (bool, error) {
+		current, err := o.Status(ctx)
+		if err != nil {
+			return false, err
+		}
+		status = current
+		return status.Done(), nil
+	})
+	if err != nil {
+		return status, err
+	}
+
+	if status.State == OperationFailed {
+		return status, &OperationError{ID: status.ID, Kind: status.Kind, Message: status.Error}
+	}
+
+	return status, nil
+}
+
+// Result unmarshals the result of a finished operation into target
+func (o *Operation) Result(target interface{}) error {
+	if !o.status.Done() {
+		return fmt.Errorf("operation %s has not finished", o.status.ID)
+	}
+	if len(o.status.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(o.status.Result, target); err != nil {
+		return fmt.Errorf("failed to unmarshal operation result: %w", err)
+	}
+	return nil
+}
+
+// startOperation issues a destructive request. A 202 Accepted response is
+// treated as an asynchronous operation identified by its operation_id; any
+// other successful response completes the operation immediately
+func (c *Client) startOperation(ctx context.Context, method, path, kind string, body interface{}) (*Operation, error) {
+	resp, err := c.doRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &Operation{client: c, kind: kind}
+
+	if resp.StatusCode != http.StatusAccepted {
+		var raw json.RawMessage
+		if err := c.parseResponse(resp, &raw); err != nil {
+			return nil, err
+		}
+		op.status = OperationStatus{Kind: kind, State: OperationSucceeded, Result: raw}
+		return op, nil
+	}
+
+	var accepted struct {
+		OperationID string `json:"operation_id"`
+		State       string `json:"state"`
+	}
+	if err := c.parseResponse(resp, &accepted); err != nil {
+		return nil, err
+	}
+	if accepted.OperationID == "" {
+		return nil, fmt.Errorf("service accepted %s without an operation id", kind)
+	}
+	if accepted.State == "" {
+		accepted.State = OperationPending
+	}
+
+	op.status = OperationStatus{ID: accepted.OperationID, Kind: kind, State: accepted.State}
+	return op, nil
+}
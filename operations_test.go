@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRemoveAllWorkersSynchronous(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(RemoveAllWorkersResponse{Status: "success", TotalRemoved: 4})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	resp, err := client.RemoveAllWorkers(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.TotalRemoved != 4 {
+		t.Errorf("Expected 4 workers removed, got %d", resp.TotalRemoved)
+	}
+}
+
+func TestRemoveAllWorkersAsynchronous(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/workers/remove-all":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]string{"operation_id": "op-1", "state": "running"})
+		case "/api/v1/operations/op-1":
+			polls++
+			status := OperationStatus{ID: "op-1", State: OperationRunning}
+			if polls == 2 {
+				status.State = OperationSucceeded
+				status.Result = json.RawMessage(`{"status":"success","total_removed":6}`)
+			}
+			json.NewEncoder(w).Encode(status)
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	op, err := client.StartRemoveAllWorkers(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if op.ID() != "op-1" {
+		t.Errorf("Expected operation ID 'op-1', got '%s'", op.ID())
+	}
+
+	status, err := op.Wait(ctx, PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status.Kind != "remove_all_workers" {
+		t.Errorf("Expected kind 'remove_all_workers', got '%s'", status.Kind)
+	}
+
+	var result RemoveAllWorkersResponse
+	if err := op.Result(&result); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.TotalRemoved != 6 {
+		t.Errorf("Expected 6 workers removed, got %d", result.TotalRemoved)
+	}
+}
+
+func TestOperationWaitFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OperationStatus{ID: "op-1", State: OperationFailed, Error: "broker unavailable"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	op := &Operation{client: client, kind: "purge_queue", status: OperationStatus{ID: "op-1", State: OperationPending}}
+
+	_, err := op.Wait(context.Background(), PollOptions{Interval: time.Millisecond})
+	if _, ok := err.(*OperationError); !ok {
+		t.Errorf("Expected OperationError, got %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Defaults for outbox replay backoff
+const (
+	DefaultOutboxMinBackoff = time.Second
+	DefaultOutboxMaxBackoff = 5 * time.Minute
+)
+
+// ErrSpooled is returned by Outbox.Send when the message could not be
+// delivered and was stored on disk for later replay
+var ErrSpooled = errors.New("message spooled to outbox")
+
+// OutboxConfig holds configuration options for an outbox
+type OutboxConfig struct {
+	// Path is the append-only file holding spooled messages
+	Path string
+	// MinBackoff is the initial delay between failed replay attempts
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between failed replay attempts
+	MaxBackoff time.Duration
+	// OnReject, when set, is called by Flush for each spooled message the
+	// service rejected permanently, e.g. with a 4xx, before it is removed
+	// from the outbox
+	OnReject func(entry ArchiveRecord, err error)
+}
+
+// OutboxStats reports the state of the outbox
+type OutboxStats struct {
+	Depth     int
+	OldestAge time.Duration
+	Replayed  int64
+	// Rejected is the number of spooled messages removed after the service
+	// rejected them permanently
+	Rejected int64
+}
+
+// Outbox spools messages to local disk when the service is unreachable and
+// replays them in order once it recovers, giving at-least-once delivery. The
+// outbox file is an uncompressed archive (see WriteArchive)
+type Outbox struct {
+	client *Client
+	config OutboxConfig
+
+	// flushMu serializes replays, so that no message is replayed twice;
+	// mu guards the state and is never held during a submission
+	flushMu  sync.Mutex
+	mu       sync.Mutex
+	pending  []ArchiveRecord
+	replayed int64
+	rejected int64
+}
+
+// NewOutbox opens or creates the outbox file and loads any spooled messages
+func NewOutbox(client *Client, config OutboxConfig) (*Outbox, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("outbox path is required")
+	}
+
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = DefaultOutboxMinBackoff
+	}
+
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultOutboxMaxBackoff
+	}
+
+	o := &Outbox{client: client, config: config}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// Send submits the message, spooling it to disk when the service cannot be
+// reached or answers with a server error. Messages are also spooled while
+// older ones are still pending so that delivery order is preserved. When the
+// message is spooled Send returns ErrSpooled; when ctx ends first, the
+// message is not spooled and ctx's error is returned
+func (o *Outbox) Send(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	req = o.client.withDefaults(ctx, req)
+	if err := o.client.preparePayload(ctx, "", req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	pending := len(o.pending)
+	o.mu.Unlock()
+
+	if pending == 0 {
+		resp, err := o.client.PostMessage(ctx, req)
+		if err == nil || ctx.Err() != nil || !shouldSpool(err) {
+			return resp, err
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.append(ArchiveRecord{EnqueuedAt: o.client.clock.Now(), Request: *req}); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrSpooled
+}
+
+// Flush replays spooled messages in order, stopping at the first message
+// that still cannot be delivered. Messages the service rejects permanently
+// are removed, since retrying them would block the rest of the outbox
+// forever, and reported to OnReject. Messages spooled during the replay are
+// kept for the next one
+func (o *Outbox) Flush(ctx context.Context) error {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+
+	o.mu.Lock()
+	entries := append([]ArchiveRecord(nil), o.pending...)
+	o.mu.Unlock()
+
+	done, accepted, rejected := 0, 0, 0
+	var replayErr error
+	for _, entry := range entries {
+		req := entry.Request
+		_, err := o.client.PostMessage(ctx, &req)
+		if err != nil && (ctx.Err() != nil || !isRejection(err)) {
+			replayErr = err
+			break
+		}
+		done++
+		if err == nil {
+			accepted++
+			continue
+		}
+		rejected++
+		if o.config.OnReject != nil {
+			o.config.OnReject(entry, err)
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if done > 0 {
+		remaining := append([]ArchiveRecord(nil), o.pending[done:]...)
+		if err := o.rewrite(remaining); err != nil {
+			return err
+		}
+		o.pending = remaining
+		o.replayed += int64(accepted)
+		o.rejected += int64(rejected)
+	}
+
+	if replayErr != nil {
+		return fmt.Errorf("outbox replay stopped with %d messages pending: %w", len(o.pending), replayErr)
+	}
+
+	return nil
+}
+
+// Run replays the outbox until ctx is done, backing off exponentially while
+// the service stays unreachable
+func (o *Outbox) Run(ctx context.Context) error {
+	backoff := o.config.MinBackoff
+	for {
+		delay := o.config.MinBackoff
+		if err := o.Flush(ctx); err != nil {
+			delay = backoff
+			backoff *= 2
+			if backoff > o.config.MaxBackoff {
+				backoff = o.config.MaxBackoff
+			}
+		} else {
+			backoff = o.config.MinBackoff
+		}
+
+		if err := sleep(ctx, o.client.clock, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Stats returns the outbox depth and the age of the oldest spooled message
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stats := OutboxStats{Depth: len(o.pending), Replayed: o.replayed, Rejected: o.rejected}
+	if len(o.pending) > 0 {
+		stats.OldestAge = o.client.clock.Now().Sub(o.pending[0].EnqueuedAt)
+	}
+	return stats
+}
+
+// load reads spooled messages from disk
+func (o *Outbox) load() error {
+	f, err := os.Open(o.config.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open outbox: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 2*MaxPayloadSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if header, ok := parseArchiveHeader(scanner.Bytes()); ok {
+			if header.Compression != "" {
+				return fmt.Errorf("outbox must not be compressed")
+			}
+			continue
+		}
+		var entry ArchiveRecord
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return fmt.Errorf("failed to parse outbox entry: %w", err)
+		}
+		o.pending = append(o.pending, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	return nil
+}
+
+// append durably adds an entry to the end of the outbox file
+func (o *Outbox) append(entry ArchiveRecord) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox entry: %w", err)
+	}
+
+	f, err := os.OpenFile(o.config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat outbox: %w", err)
+	}
+	if info.Size() == 0 {
+		if _, err := writeArchive(f, ArchiveHeader{}, o.client.clock); err != nil {
+			return err
+		}
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+
+	o.pending = append(o.pending, entry)
+	return nil
+}
+
+// rewrite atomically replaces the outbox file with the given entries
+func (o *Outbox) rewrite(entries []ArchiveRecord) error {
+	tmp, err := os.CreateTemp(filepath.Dir(o.config.Path), filepath.Base(o.config.Path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create outbox file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w, err := writeArchive(tmp, ArchiveHeader{}, o.client.clock)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync outbox: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), o.config.Path); err != nil {
+		return fmt.Errorf("failed to replace outbox: %w", err)
+	}
+
+	return nil
+}
+
+// shouldSpool reports whether a submission failed in transit: the service
+// could not be reached or answered with a server error. Callers check their
+// context first, since a canceled request also fails in transit
+func shouldSpool(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr)
+}
+
+// isRejection reports whether the service refused a message for good, so
+// that replaying it again cannot succeed. Throttling is not a rejection
+func isRejection(err error) bool {
+	if IsValidationError(err) {
+		return true
+	}
+
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
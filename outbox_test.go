@@ -0,0 +1,248 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOutboxSpoolAndReplay(t *testing.T) {
+	var healthy atomic.Bool
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req.ItemID)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + req.ItemID})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	client := NewClient(&Config{BaseURL: server.URL})
+	outbox, err := NewOutbox(client, OutboxConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	for _, id := range []string{"pr-1", "pr-2"} {
+		req := newMessageRequest(id, PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+		if _, err := outbox.Send(ctx, req); !errors.Is(err, ErrSpooled) {
+			t.Fatalf("Expected ErrSpooled, got %v", err)
+		}
+	}
+
+	if stats := outbox.Stats(); stats.Depth != 2 {
+		t.Errorf("Expected depth 2, got %d", stats.Depth)
+	}
+
+	// A new outbox on the same file picks up the spooled messages
+	reopened, err := NewOutbox(client, OutboxConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reopened.Stats().Depth != 2 {
+		t.Errorf("Expected reopened depth 2, got %d", reopened.Stats().Depth)
+	}
+
+	healthy.Store(true)
+	if err := reopened.Flush(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(received) != 2 || received[0] != "pr-1" || received[1] != "pr-2" {
+		t.Errorf("Expected messages replayed in order, got %v", received)
+	}
+	if stats := reopened.Stats(); stats.Depth != 0 || stats.Replayed != 2 {
+		t.Errorf("Expected empty outbox after replay, got %+v", stats)
+	}
+}
+
+func TestOutboxDoesNotSpoolClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	outbox, err := NewOutbox(NewClient(&Config{BaseURL: server.URL}), OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+	if _, err := outbox.Send(context.Background(), req); !IsAPIError(err) {
+		t.Errorf("Expected APIError, got %v", err)
+	}
+	if outbox.Stats().Depth != 0 {
+		t.Error("Expected 4xx rejection not to be spooled")
+	}
+}
+
+func TestOutboxFileIsArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	// Outboxes written before the archive format have no header
+	legacy := `{"enqueued_at":"2024-01-01T00:00:00Z","request":{"item_id":"pr-1","priority":"high","topic":"pullrequests","callback_url":"https://example.com/cb","object_body":null}}` + "\n"
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("Failed to write outbox: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	outbox, err := NewOutbox(NewClient(&Config{BaseURL: server.URL}), OutboxConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if outbox.Stats().Depth != 1 {
+		t.Fatalf("Expected legacy entry to be loaded, got depth %d", outbox.Stats().Depth)
+	}
+
+	os.Remove(path)
+	if _, err := outbox.Send(context.Background(), &MessageRequest{ItemID: "pr-2", Priority: PriorityLow, Topic: TopicPullRequests, CallbackURL: "https://example.com/cb"}); !errors.Is(err, ErrSpooled) {
+		t.Fatalf("Expected ErrSpooled, got %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open outbox: %v", err)
+	}
+	defer f.Close()
+
+	archive, err := ReadArchive(f)
+	if err != nil {
+		t.Fatalf("Expected outbox to be a readable archive, got %v", err)
+	}
+	record, err := archive.Next()
+	if err != nil || record.Request.ItemID != "pr-2" {
+		t.Errorf("Unexpected record %+v, err %v", record, err)
+	}
+}
+
+func TestOutboxDoesNotSpoolCanceledSends(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	outbox, err := NewOutbox(NewClient(&Config{BaseURL: server.URL}), OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+	if _, err := outbox.Send(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline, got %v", err)
+	}
+	if outbox.Stats().Depth != 0 {
+		t.Error("Expected a canceled send not to be spooled")
+	}
+}
+
+func TestOutboxFlushReportsRejections(t *testing.T) {
+	statuses := map[string]int{"pr-1": http.StatusBadRequest, "pr-3": http.StatusTooManyRequests}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if status, ok := statuses[req.ItemID]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + req.ItemID})
+	}))
+	defer server.Close()
+
+	var rejected []string
+	outbox, err := NewOutbox(NewClient(&Config{BaseURL: server.URL}), OutboxConfig{
+		Path: filepath.Join(t.TempDir(), "outbox.jsonl"),
+		OnReject: func(entry ArchiveRecord, err error) {
+			if !IsAPIError(err) {
+				t.Errorf("Expected the rejection error, got %v", err)
+			}
+			rejected = append(rejected, entry.Request.ItemID)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	for _, id := range []string{"pr-1", "pr-2", "pr-3", "pr-4"} {
+		if err := outbox.Spill(ctx, newMessageRequest(id, PriorityLow, TopicPullRequests, "https://example.com/callback", nil), nil); err != nil {
+			t.Fatalf("Spill failed: %v", err)
+		}
+	}
+
+	// Throttling stops the replay rather than dropping the message
+	if err := outbox.Flush(ctx); err == nil {
+		t.Error("Expected the replay to stop at the throttled message")
+	}
+	if len(rejected) != 1 || rejected[0] != "pr-1" {
+		t.Errorf("Expected pr-1 to be reported as rejected, got %v", rejected)
+	}
+	if stats := outbox.Stats(); stats.Depth != 2 || stats.Replayed != 1 || stats.Rejected != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestOutboxFlushDoesNotBlockSends(t *testing.T) {
+	release := make(chan struct{})
+	replaying := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ItemID == "pr-1" {
+			replaying <- struct{}{}
+			<-release
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-" + req.ItemID})
+	}))
+	defer server.Close()
+
+	outbox, err := NewOutbox(NewClient(&Config{BaseURL: server.URL}), OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx := context.Background()
+	if err := outbox.Spill(ctx, newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil), nil); err != nil {
+		t.Fatalf("Spill failed: %v", err)
+	}
+
+	flushed := make(chan error, 1)
+	go func() { flushed <- outbox.Flush(ctx) }()
+	<-replaying
+
+	// Older messages are pending, so the send is spooled behind them
+	// without waiting for the replay
+	if _, err := outbox.Send(ctx, newMessageRequest("pr-2", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)); !errors.Is(err, ErrSpooled) {
+		t.Errorf("Expected ErrSpooled, got %v", err)
+	}
+	if depth := outbox.Stats().Depth; depth != 2 {
+		t.Errorf("Expected depth 2 during the replay, got %d", depth)
+	}
+
+	close(release)
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if depth := outbox.Stats().Depth; depth != 1 {
+		t.Errorf("Expected the message spooled during the replay to be kept, got depth %d", depth)
+	}
+}
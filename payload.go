@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// PayloadStore holds ObjectBody content too large to send inline, such as an
+// S3 bucket. Put stores data under key and returns a URL the message's
+// receiver can fetch it from
+type PayloadStore interface {
+	Put(ctx context.Context, key string, data []byte) (string, error)
+}
+
+// PayloadReference replaces an ObjectBody that was offloaded to a PayloadStore
+type PayloadReference struct {
+	URL string `json:"payload_url"`
+	// Size is the length of the stored JSON body in bytes
+	Size int `json:"payload_size"`
+	// SHA256 is the hex digest of the stored body, for integrity checks
+	SHA256 string `json:"payload_sha256"`
+}
+
+// offloadPayload moves the ObjectBody of req to the client's payload store
+// when it exceeds MaxPayloadSize, replacing it with a *PayloadReference. The
+// body is checked against its topic validator first since the reference no
+// longer can be; violations are reported with field names prefixed by
+// prefix. req must be a copy owned by the caller
+func (c *Client) offloadPayload(ctx context.Context, prefix string, req *MessageRequest) error {
+	if c.payloadStore == nil || req.ObjectBody == nil || isPayloadReference(req.ObjectBody) {
+		return nil
+	}
+
+	data, err := json.Marshal(req.ObjectBody)
+	if err != nil || len(data) <= MaxPayloadSize {
+		// Marshal errors are reported by validation
+		return nil
+	}
+
+	if validator := topicValidator(req.Topic); validator != nil && !isEncryptedPayload(req.ObjectBody) {
+		if err := validator.ValidateBody(req.ObjectBody); err != nil {
+			verr := &ValidationError{}
+			verr.add(prefix+"object_body", "%v", err)
+			return verr
+		}
+	}
+
+	hash, err := contentHash(req.ObjectBody)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s.json", url.PathEscape(string(req.Topic)), url.PathEscape(req.ItemID), hash)
+	location, err := c.payloadStore.Put(ctx, key, data)
+	if err != nil {
+		return fmt.Errorf("failed to offload payload: %w", err)
+	}
+
+	req.ObjectBody = &PayloadReference{URL: location, Size: len(data), SHA256: hash}
+	return nil
+}
+
+// isPayloadReference reports whether body is an offloaded payload reference,
+// including one decoded generically, as when an outbox is reloaded from disk
+func isPayloadReference(body interface{}) bool {
+	switch b := body.(type) {
+	case *PayloadReference, PayloadReference:
+		return true
+	case map[string]interface{}:
+		_, hasURL := b["payload_url"]
+		_, hasHash := b["payload_sha256"]
+		return hasURL && hasHash && len(b) == 3
+	default:
+		return false
+	}
+}
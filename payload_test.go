@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type memoryPayloadStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryPayloadStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	s.objects[key] = data
+	return "https://payloads.example.com/" + key, nil
+}
+
+func TestPayloadTooLarge(t *testing.T) {
+	req := &MessageRequest{
+		ItemID:      "pr-1",
+		Priority:    PriorityHigh,
+		Topic:       TopicPullRequests,
+		CallbackURL: "https://example.com/callback",
+		ObjectBody:  map[string]string{"diff": strings.Repeat("a", MaxPayloadSize)},
+	}
+
+	err := req.Validate()
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error, got %T", err)
+	}
+
+	if !errors.Is(&APIError{StatusCode: http.StatusRequestEntityTooLarge}, ErrPayloadTooLarge) {
+		t.Error("Expected a 413 API error to match ErrPayloadTooLarge")
+	}
+	if errors.Is(&APIError{StatusCode: http.StatusBadRequest}, ErrPayloadTooLarge) {
+		t.Error("Expected a 400 API error not to match ErrPayloadTooLarge")
+	}
+}
+
+func TestPayloadOffloading(t *testing.T) {
+	var received MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	store := &memoryPayloadStore{objects: make(map[string][]byte)}
+	client := NewClient(&Config{BaseURL: server.URL, PayloadStore: store})
+
+	large := map[string]string{"diff": strings.Repeat("a", MaxPayloadSize)}
+	if _, err := client.PostMessageWithDefaults(context.Background(), "pr-1", "https://example.com/callback", large); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(store.objects) != 1 {
+		t.Fatalf("Expected 1 stored payload, got %d", len(store.objects))
+	}
+
+	body, ok := received.ObjectBody.(map[string]interface{})
+	if !ok || !strings.HasPrefix(body["payload_url"].(string), "https://payloads.example.com/pullrequests/pr-1/") {
+		t.Errorf("Expected a payload reference, got %v", received.ObjectBody)
+	}
+	if !isPayloadReference(received.ObjectBody) {
+		t.Error("Expected the decoded body to be recognized as a payload reference")
+	}
+
+	// Small bodies are sent inline
+	if _, err := client.PostMessageWithDefaults(context.Background(), "pr-2", "https://example.com/callback", map[string]string{"diff": "a"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(store.objects) != 1 {
+		t.Errorf("Expected small body not to be offloaded, got %d stored payloads", len(store.objects))
+	}
+}
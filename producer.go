@@ -0,0 +1,549 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for the producer pipeline
+const (
+	DefaultProducerBatchSize    = 100
+	DefaultProducerBatchDelay   = 100 * time.Millisecond
+	DefaultProducerBufferSize   = 1000
+	DefaultProducerConcurrency  = 4
+	DefaultProducerMaxRetries   = 3
+	DefaultProducerRetryBackoff = 200 * time.Millisecond
+)
+
+// ErrProducerClosed is returned when sending through a producer that has been closed
+var ErrProducerClosed = errors.New("producer is closed")
+
+// ProducerSendFunc submits a batch of messages
+type ProducerSendFunc func(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error)
+
+// ProducerMiddleware wraps the submission of every batch, e.g. to add
+// tracing or custom metrics. The response must keep the semantics of
+// PostBulkMessages so that results can be matched to messages
+type ProducerMiddleware func(next ProducerSendFunc) ProducerSendFunc
+
+// ProducerConfig configures the stages of a Producer
+type ProducerConfig struct {
+	// BatchSize is the largest number of messages sent in one bulk request;
+	// defaults to DefaultProducerBatchSize
+	BatchSize int
+	// BatchDelay is how long a partial batch waits for more messages before
+	// it is sent; defaults to DefaultProducerBatchDelay
+	BatchDelay time.Duration
+	// BufferSize is the number of messages that may wait for a batch before
+	// Send blocks; defaults to DefaultProducerBufferSize
+	BufferSize int
+	// Concurrency bounds the batches in flight; defaults to DefaultProducerConcurrency
+	Concurrency int
+
+	// MaxRetries is the number of times a batch failing with a transient
+	// error (network failures, capacity errors, and 5xx responses) is
+	// retried; defaults to DefaultProducerMaxRetries, negative disables retries
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, doubled after each;
+	// defaults to DefaultProducerRetryBackoff
+	RetryBackoff time.Duration
+
+	// RateLimit caps submissions in messages per second; zero is unlimited
+	RateLimit float64
+
+	// Idempotent checks every batch for messages that already exist and
+	// completes them with a *DuplicateMessageError instead of resending them
+	Idempotent bool
+
+	// Middleware wraps batch submission, the first entry outermost
+	Middleware []ProducerMiddleware
+}
+
+// withDefaults fills unset producer options
+func (c ProducerConfig) withDefaults() ProducerConfig {
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultProducerBatchSize
+	}
+	if c.BatchDelay <= 0 {
+		c.BatchDelay = DefaultProducerBatchDelay
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = DefaultProducerBufferSize
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultProducerConcurrency
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultProducerMaxRetries
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = DefaultProducerRetryBackoff
+	}
+	return c
+}
+
+// ProducerStats reports the activity of a producer
+type ProducerStats struct {
+	// Buffered is the number of messages waiting for a batch
+	Buffered int64
+	// Sent is the number of messages accepted by the service
+	Sent int64
+	// Failed is the number of messages that could not be submitted
+	Failed int64
+	// Duplicates is the number of messages skipped as already existing
+	Duplicates int64
+	Batches    int64
+	Retries    int64
+}
+
+// Producer submits messages through a configured pipeline: messages are
+// validated when sent, buffered into batches, checked for duplicates,
+// rate limited, and submitted in bulk requests with retries. Results are
+// delivered through the futures returned by Send
+type Producer struct {
+	client  *Client
+	config  ProducerConfig
+	send    ProducerSendFunc
+	limiter *rateLimiter
+
+	in      chan *MessageFuture
+	flushes chan chan []chan struct{}
+	done    chan struct{}
+	// ctx is canceled to abandon batches in flight, including their retries
+	ctx        context.Context
+	cancel     context.CancelFunc
+	unregister func()
+
+	mu     sync.RWMutex
+	closed bool
+
+	buffered   atomic.Int64
+	sent       atomic.Int64
+	failed     atomic.Int64
+	duplicates atomic.Int64
+	batches    atomic.Int64
+	retries    atomic.Int64
+}
+
+// NewProducer starts a producer submitting through client
+func NewProducer(client *Client, config ProducerConfig) *Producer {
+	config = config.withDefaults()
+
+	p := &Producer{
+		client:  client,
+		config:  config,
+		in:      make(chan *MessageFuture, config.BufferSize),
+		flushes: make(chan chan []chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	if config.RateLimit > 0 {
+		p.limiter = newRateLimiter(config.RateLimit, client.clock)
+	}
+
+	p.send = p.submit
+	for i := len(config.Middleware) - 1; i >= 0; i-- {
+		p.send = config.Middleware[i](p.send)
+	}
+
+	p.unregister = client.RegisterShutdownHook(ShutdownFlush, p)
+	go p.run()
+	return p
+}
+
+// Send validates msg and queues it for the next batch, blocking only while
+// the buffer is full. The returned future completes once the batch holding
+// the message was submitted. Cancelling ctx or the future before then drops
+// the message
+func (p *Producer) Send(ctx context.Context, msg *MessageRequest) *MessageFuture {
+	fctx, cancel := context.WithCancel(ctx)
+	f := &MessageFuture{ctx: fctx, cancel: cancel, done: make(chan struct{})}
+
+	if msg == nil {
+		f.complete(nil, errors.New("message request cannot be nil"))
+		return f
+	}
+
+	// Validate up front so that one bad message cannot fail a whole batch
+	req := p.client.withDefaults(ctx, msg)
+	if err := p.client.preparePayload(ctx, "", req); err != nil {
+		f.complete(nil, err)
+		return f
+	}
+	if err := req.Validate(); err != nil {
+		f.complete(nil, err)
+		return f
+	}
+	f.req = req
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		f.complete(nil, ErrProducerClosed)
+		return f
+	}
+
+	p.buffered.Add(1)
+	select {
+	case p.in <- f:
+	case <-fctx.Done():
+		p.buffered.Add(-1)
+		f.complete(nil, fctx.Err())
+	}
+	return f
+}
+
+// Flush sends buffered messages without waiting for their batch to fill and
+// waits until every batch in flight has been submitted or ctx is done
+func (p *Producer) Flush(ctx context.Context) error {
+	reply := make(chan []chan struct{}, 1)
+	select {
+	case p.flushes <- reply:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for _, batchDone := range <-reply {
+		select {
+		case <-batchDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close stops accepting messages, sends those still buffered, and waits for
+// every batch in flight; it is safe to call more than once
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.in)
+	}
+	p.mu.Unlock()
+
+	<-p.done
+	p.cancel()
+	p.unregister()
+	return nil
+}
+
+// Shutdown is Close bounded by ctx: once ctx is done, batches still in
+// flight are abandoned, their retries canceled, and their futures failed
+func (p *Producer) Shutdown(ctx context.Context) error {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		p.Close()
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-closed
+		return fmt.Errorf("producer batches abandoned: %w", ctx.Err())
+	}
+}
+
+// Stats returns a snapshot of the producer counters
+func (p *Producer) Stats() ProducerStats {
+	return ProducerStats{
+		Buffered:   p.buffered.Load(),
+		Sent:       p.sent.Load(),
+		Failed:     p.failed.Load(),
+		Duplicates: p.duplicates.Load(),
+		Batches:    p.batches.Load(),
+		Retries:    p.retries.Load(),
+	}
+}
+
+// run collects messages into batches and dispatches them until the input
+// is closed and every batch has finished
+func (p *Producer) run() {
+	defer close(p.done)
+
+	sem := make(chan struct{}, p.config.Concurrency)
+	inFlight := make(map[chan struct{}]bool)
+	var inFlightMu sync.Mutex
+
+	var batch []*MessageFuture
+	timer := p.client.clock.NewTimer(p.config.BatchDelay)
+	timer.Stop()
+
+	dispatch := func() {
+		timer.Stop()
+		if len(batch) == 0 {
+			return
+		}
+
+		futures := batch
+		batch = nil
+		p.buffered.Add(-int64(len(futures)))
+
+		batchDone := make(chan struct{})
+		inFlightMu.Lock()
+		inFlight[batchDone] = true
+		inFlightMu.Unlock()
+
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				inFlightMu.Lock()
+				delete(inFlight, batchDone)
+				inFlightMu.Unlock()
+				close(batchDone)
+			}()
+			p.sendBatch(futures)
+		}()
+	}
+
+	pending := func() []chan struct{} {
+		inFlightMu.Lock()
+		defer inFlightMu.Unlock()
+		chans := make([]chan struct{}, 0, len(inFlight))
+		for c := range inFlight {
+			chans = append(chans, c)
+		}
+		return chans
+	}
+
+	for {
+		select {
+		case f, ok := <-p.in:
+			if !ok {
+				dispatch()
+				for _, batchDone := range pending() {
+					<-batchDone
+				}
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(p.config.BatchDelay)
+			}
+			batch = append(batch, f)
+			if len(batch) >= p.config.BatchSize {
+				dispatch()
+			}
+		case <-timer.C():
+			dispatch()
+		case reply := <-p.flushes:
+			// Take every message sent before Flush was called
+			for drained := false; !drained; {
+				select {
+				case f, ok := <-p.in:
+					if !ok {
+						drained = true
+						break
+					}
+					batch = append(batch, f)
+					if len(batch) >= p.config.BatchSize {
+						dispatch()
+					}
+				default:
+					drained = true
+				}
+			}
+			dispatch()
+			reply <- pending()
+		}
+	}
+}
+
+// sendBatch submits the messages of futures that are still wanted and
+// completes every future
+func (p *Producer) sendBatch(futures []*MessageFuture) {
+	p.batches.Add(1)
+
+	live := futures[:0]
+	for _, f := range futures {
+		if err := f.ctx.Err(); err != nil {
+			p.failed.Add(1)
+			f.complete(nil, err)
+			continue
+		}
+		live = append(live, f)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	ctx := p.ctx
+	if p.config.Idempotent {
+		live = p.skipDuplicates(ctx, live)
+		if len(live) == 0 {
+			return
+		}
+	}
+
+	req := &BulkMessageRequest{Messages: make([]MessageRequest, len(live))}
+	for i, f := range live {
+		req.Messages[i] = *f.req
+	}
+
+	resp, err := p.send(ctx, req)
+	if err != nil && !IsBulkPartialError(err) {
+		p.failed.Add(int64(len(live)))
+		for _, f := range live {
+			f.complete(nil, err)
+		}
+		return
+	}
+
+	p.completeBatch(live, req, resp)
+}
+
+// skipDuplicates completes the futures whose messages already exist and
+// returns the others. When the check itself fails every message is sent
+func (p *Producer) skipDuplicates(ctx context.Context, futures []*MessageFuture) []*MessageFuture {
+	reqs := make([]MessageRequest, len(futures))
+	for i, f := range futures {
+		reqs[i] = *f.req
+	}
+
+	report, err := p.client.CheckDuplicates(ctx, reqs)
+	if err != nil {
+		return futures
+	}
+
+	unique := futures[:0:0]
+	for i, f := range futures {
+		dup := duplicateAt(report, i)
+		if dup == nil {
+			unique = append(unique, f)
+			continue
+		}
+		p.duplicates.Add(1)
+		f.complete(nil, &DuplicateMessageError{ItemID: dup.ItemID, MessageID: dup.MessageID, Status: dup.Status, SameContent: dup.SameContent})
+	}
+	return unique
+}
+
+// duplicateAt returns the duplicate reported for index i, if any
+func duplicateAt(report *DuplicateReport, i int) *DuplicateMessage {
+	for j := range report.Duplicates {
+		if report.Duplicates[j].Index == i {
+			return &report.Duplicates[j]
+		}
+	}
+	return nil
+}
+
+// completeBatch matches the bulk response to the futures of the batch by
+// their index in req, so that repeated or empty item IDs resolve each
+// future with its own message's response
+func (p *Producer) completeBatch(futures []*MessageFuture, req *BulkMessageRequest, resp *BulkMessageResponse) {
+	rejected := make(map[int]BulkMessageError, len(resp.Errors))
+	for _, e := range resp.Errors {
+		rejected[e.Index] = e
+	}
+
+	// Responses from middleware may not have been matched with the request
+	indexes := resp.indexes
+	if indexes == nil {
+		indexes = matchBulkResponse(resp, req)
+	}
+	accepted := make(map[int]MessageResponse, len(resp.Messages))
+	for i, m := range resp.Messages {
+		if indexes[i] >= 0 {
+			accepted[indexes[i]] = m
+		}
+	}
+
+	for i, f := range futures {
+		if e, ok := rejected[i]; ok {
+			p.failed.Add(1)
+			f.complete(nil, e)
+			continue
+		}
+
+		result, ok := accepted[i]
+		if !ok {
+			result = MessageResponse{ItemID: f.req.ItemID, Priority: f.req.Priority, Topic: f.req.Topic}
+		}
+		p.sent.Add(1)
+		f.complete(&result, nil)
+	}
+}
+
+// submit is the innermost pipeline stage: it rate limits the batch and
+// posts it, retrying transient failures with exponential backoff
+func (p *Producer) submit(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+	if p.limiter != nil {
+		if err := p.limiter.wait(ctx, len(req.Messages)); err != nil {
+			return nil, err
+		}
+	}
+
+	backoff := p.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := p.client.PostBulkMessages(ctx, req)
+		if err == nil || attempt >= p.config.MaxRetries || !isTransientSubmitError(err) {
+			return resp, err
+		}
+
+		p.retries.Add(1)
+		if err := sleep(ctx, p.client.clock, backoff); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientSubmitError reports whether resubmitting may succeed: network
+// failures, capacity errors, and server errors are transient, while
+// validation failures, client errors, and cancellation are not
+func isTransientSubmitError(err error) bool {
+	if IsValidationError(err) || IsBulkPartialError(err) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, ErrClientClosed) || errors.Is(err, ErrSigningKeyRequired) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+	return true
+}
+
+// rateLimiter spaces out submissions to a steady rate, allowing up to one
+// second of unused capacity to accumulate as burst
+type rateLimiter struct {
+	clock    Clock
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter returns a limiter allowing perSecond messages per second
+func newRateLimiter(perSecond float64, clock Clock) *rateLimiter {
+	return &rateLimiter{clock: clock, interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until n messages may be sent
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := l.clock.Now()
+	if earliest := now.Add(-time.Second); l.next.Before(earliest) {
+		l.next = earliest
+	}
+	l.next = l.next.Add(time.Duration(n) * l.interval)
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	return sleep(ctx, l.clock, delay)
+}
@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProducer(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/messages/duplicates":
+			var req duplicatesRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			var report DuplicateReport
+			for i, c := range req.Candidates {
+				if c.ItemID == "dup" {
+					report.Duplicates = append(report.Duplicates, DuplicateMessage{Index: i, ItemID: c.ItemID, MessageID: "msg-old"})
+				}
+			}
+			json.NewEncoder(w).Encode(report)
+		case "/api/v1/messages/bulk":
+			// The first attempt fails transiently and is retried
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var req BulkMessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			batchSizes = append(batchSizes, len(req.Messages))
+			mu.Unlock()
+
+			resp := BulkMessageResponse{Status: "success"}
+			for i, m := range req.Messages {
+				if m.ItemID == "bad" {
+					resp.Errors = append(resp.Errors, BulkMessageError{Index: i, ItemID: m.ItemID, Code: "rejected", Reason: "unknown repository"})
+					continue
+				}
+				resp.Messages = append(resp.Messages, MessageResponse{ID: "msg-" + m.ItemID, ItemID: m.ItemID})
+			}
+			if len(resp.Errors) > 0 {
+				w.WriteHeader(http.StatusMultiStatus)
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var middlewareCalls atomic.Int32
+	client := NewClient(&Config{BaseURL: server.URL})
+	producer := NewProducer(client, ProducerConfig{
+		BatchSize:    3,
+		BatchDelay:   time.Hour,
+		Concurrency:  1,
+		RetryBackoff: time.Millisecond,
+		Idempotent:   true,
+		Middleware: []ProducerMiddleware{func(next ProducerSendFunc) ProducerSendFunc {
+			return func(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+				middlewareCalls.Add(1)
+				return next(ctx, req)
+			}
+		}},
+	})
+	ctx := context.Background()
+
+	newReq := func(itemID string) *MessageRequest {
+		return newMessageRequest(itemID, PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	}
+
+	invalid := producer.Send(ctx, newReq(""))
+	if _, err := invalid.Result(); !IsValidationError(err) {
+		t.Errorf("Expected validation error at send time, got %v", err)
+	}
+
+	futures := []*MessageFuture{
+		producer.Send(ctx, newReq("pr-1")),
+		producer.Send(ctx, newReq("dup")),
+		producer.Send(ctx, newReq("bad")),
+		producer.Send(ctx, newReq("pr-2")),
+	}
+
+	// The first three fill a batch; the fourth waits for Flush
+	if resp, err := futures[0].Result(); err != nil || resp.ID != "msg-pr-1" {
+		t.Errorf("Expected pr-1 to be sent, got %+v, %v", resp, err)
+	}
+	var dupErr *DuplicateMessageError
+	if _, err := futures[1].Result(); !errors.As(err, &dupErr) || dupErr.MessageID != "msg-old" {
+		t.Errorf("Expected duplicate to be skipped, got %v", err)
+	}
+	var rejected BulkMessageError
+	if _, err := futures[2].Result(); !errors.As(err, &rejected) || rejected.Code != "rejected" {
+		t.Errorf("Expected rejection, got %v", err)
+	}
+
+	select {
+	case <-futures[3].Done():
+		t.Fatal("Expected partial batch to wait for Flush")
+	default:
+	}
+	if err := producer.Flush(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp, err := futures[3].Result(); err != nil || resp.ID != "msg-pr-2" {
+		t.Errorf("Expected pr-2 to be sent on flush, got %+v, %v", resp, err)
+	}
+
+	if err := producer.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := producer.Send(ctx, newReq("pr-3")).Result(); !errors.Is(err, ErrProducerClosed) {
+		t.Errorf("Expected closed producer error, got %v", err)
+	}
+
+	stats := producer.Stats()
+	if stats.Sent != 2 || stats.Failed != 1 || stats.Duplicates != 1 || stats.Batches != 2 || stats.Retries != 1 || stats.Buffered != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if middlewareCalls.Load() != 2 {
+		t.Errorf("Expected middleware per batch, got %d calls", middlewareCalls.Load())
+	}
+	if len(batchSizes) != 2 || batchSizes[0] != 2 || batchSizes[1] != 1 {
+		t.Errorf("Unexpected batch sizes: %v", batchSizes)
+	}
+}
+
+func TestProducerCloseDrains(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received.Add(int32(len(req.Messages)))
+		json.NewEncoder(w).Encode(BulkMessageResponse{Status: "success"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	producer := NewProducer(client, ProducerConfig{BatchSize: 10, BatchDelay: time.Hour, RateLimit: 1000})
+
+	var futures []*MessageFuture
+	for i := 0; i < 25; i++ {
+		req := newMessageRequest(GenerateItemID("pr"), PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+		futures = append(futures, producer.Send(context.Background(), req))
+	}
+	producer.Close()
+
+	if received.Load() != 25 {
+		t.Errorf("Expected every buffered message to be sent on close, got %d", received.Load())
+	}
+	for _, f := range futures {
+		select {
+		case <-f.Done():
+		default:
+			t.Fatal("Expected every future to be complete after Close")
+		}
+	}
+}
+
+func TestProducerMatchesResponsesByIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req BulkMessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		// Every message is listed in order, the rejected one as a placeholder
+		resp := BulkMessageResponse{Status: "partial", Errors: []BulkMessageError{{Index: 0, ItemID: "pr-1", Code: "rejected"}}}
+		for i, m := range req.Messages {
+			resp.Messages = append(resp.Messages, MessageResponse{ID: fmt.Sprintf("msg-%d", i), ItemID: m.ItemID})
+		}
+		resp.Messages[0].ID = ""
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	producer := NewProducer(client, ProducerConfig{BatchSize: 3, BatchDelay: time.Hour})
+	defer producer.Close()
+
+	var futures []*MessageFuture
+	for _, id := range []string{"pr-1", "pr-1", "pr-2"} {
+		futures = append(futures, producer.Send(context.Background(), newMessageRequest(id, PriorityLow, TopicPullRequests, "https://example.com/callback", nil)))
+	}
+
+	if _, err := futures[0].Result(); err == nil {
+		t.Error("Expected the first message to be rejected")
+	}
+	for i := 1; i < len(futures); i++ {
+		resp, err := futures[i].Result()
+		if want := fmt.Sprintf("msg-%d", i); err != nil || resp.ID != want {
+			t.Errorf("Expected message %d to resolve to %s, got %+v, %v", i, want, resp, err)
+		}
+	}
+}
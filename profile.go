@@ -0,0 +1,334 @@
+package sdk
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the environment variable NewClientFromProfile reads
+// the config file path from
+const ConfigFileEnv = "MESSAGES_WORKER_CONFIG"
+
+// DefaultConfigFile is the config file NewClientFromProfile reads when
+// ConfigFileEnv is not set
+const DefaultConfigFile = "messages-worker.yaml"
+
+// profileConfig is the client configuration as written in a config file
+type profileConfig struct {
+	BaseURL            string         `yaml:"base_url"`
+	Timeout            time.Duration  `yaml:"timeout"`
+	HealthCheckTimeout time.Duration  `yaml:"health_check_timeout"`
+	DialTimeout        time.Duration  `yaml:"dial_timeout"`
+	APIVersion         string         `yaml:"api_version"`
+	Decoding           string         `yaml:"decoding"`
+	MaxResponseSize    int64          `yaml:"max_response_size"`
+	DedupWindow        time.Duration  `yaml:"dedup_window"`
+	UserAgentSuffix    string         `yaml:"user_agent_suffix"`
+	Team               string         `yaml:"team"`
+	CostCenter         string         `yaml:"cost_center"`
+	Tenant             string         `yaml:"tenant"`
+	Signing            *signingConfig `yaml:"signing"`
+}
+
+// signingConfig configures request signing in a config file
+type signingConfig struct {
+	KeyID  string `yaml:"key_id"`
+	Secret string `yaml:"secret"`
+}
+
+// configFile is the layout of a config file: top-level settings shared by
+// every profile, and named profiles overriding them
+type configFile struct {
+	profileConfig `yaml:",inline"`
+	Profiles      map[string]profileConfig `yaml:"profiles"`
+}
+
+// ConfigFile is a loaded config file, holding the client configuration of
+// each environment as a named profile
+type ConfigFile struct {
+	// Path is the file the configuration was loaded from
+	Path string
+
+	defaults profileConfig
+	profiles map[string]profileConfig
+}
+
+// LoadConfig reads a YAML or JSON config file. Top-level settings apply to
+// every profile, and each entry of profiles overrides them for one
+// environment:
+//
+//	base_url: http://localhost:8083
+//	timeout: 10s
+//	profiles:
+//	  prod:
+//	    base_url: https://messages-worker.example.com
+//	    signing:
+//	      key_id: key-1
+//	      secret: ${MW_SIGNING_SECRET}
+//
+// $VAR and ${VAR} in string values are replaced with environment variables,
+// and $$ with a literal $; a variable that is not set fails the load. Keys
+// and comments are left as written, and a substituted value cannot change
+// the structure of the file. Unknown fields are rejected, so that a
+// misspelled setting is not silently ignored
+func LoadConfig(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := expandConfigEnv(&root); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	// Decode the expanded document again, since only a decoder can reject
+	// unknown fields
+	var file configFile
+	if root.Kind != 0 {
+		expanded, err := yaml.Marshal(&root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(expanded))
+		dec.KnownFields(true)
+		if err := dec.Decode(&file); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	return &ConfigFile{Path: path, defaults: file.profileConfig, profiles: file.Profiles}, nil
+}
+
+// NewClientFromProfile creates a client from a profile of the config file
+// named by ConfigFileEnv, or DefaultConfigFile. An empty name uses the
+// top-level settings
+func NewClientFromProfile(name string) (*Client, error) {
+	path := os.Getenv(ConfigFileEnv)
+	if path == "" {
+		path = DefaultConfigFile
+	}
+
+	file, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := file.Config(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config), nil
+}
+
+// Profiles returns the names of the file's profiles, sorted
+func (f *ConfigFile) Profiles() []string {
+	names := make([]string, 0, len(f.profiles))
+	for name := range f.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config returns the client configuration of profile merged over the
+// top-level settings, or of the top-level settings alone when profile is
+// empty. Invalid settings are reported as a *ValidationError listing each
+func (f *ConfigFile) Config(profile string) (*Config, error) {
+	settings := f.defaults
+	if profile != "" {
+		override, ok := f.profiles[profile]
+		if !ok {
+			available := "none"
+			if names := f.Profiles(); len(names) > 0 {
+				available = strings.Join(names, ", ")
+			}
+			return nil, fmt.Errorf("profile '%s' not found in %s; available: %s", profile, f.Path, available)
+		}
+		settings = settings.merge(override)
+	}
+
+	config, err := settings.config()
+	if err != nil {
+		if profile == "" {
+			return nil, fmt.Errorf("invalid configuration in %s: %w", f.Path, err)
+		}
+		return nil, fmt.Errorf("invalid profile '%s' in %s: %w", profile, f.Path, err)
+	}
+	return config, nil
+}
+
+// merge returns c with the fields set in override replaced
+func (c profileConfig) merge(override profileConfig) profileConfig {
+	if override.BaseURL != "" {
+		c.BaseURL = override.BaseURL
+	}
+	if override.Timeout != 0 {
+		c.Timeout = override.Timeout
+	}
+	if override.HealthCheckTimeout != 0 {
+		c.HealthCheckTimeout = override.HealthCheckTimeout
+	}
+	if override.DialTimeout != 0 {
+		c.DialTimeout = override.DialTimeout
+	}
+	if override.APIVersion != "" {
+		c.APIVersion = override.APIVersion
+	}
+	if override.Decoding != "" {
+		c.Decoding = override.Decoding
+	}
+	if override.MaxResponseSize != 0 {
+		c.MaxResponseSize = override.MaxResponseSize
+	}
+	if override.DedupWindow != 0 {
+		c.DedupWindow = override.DedupWindow
+	}
+	if override.UserAgentSuffix != "" {
+		c.UserAgentSuffix = override.UserAgentSuffix
+	}
+	if override.Team != "" {
+		c.Team = override.Team
+	}
+	if override.CostCenter != "" {
+		c.CostCenter = override.CostCenter
+	}
+	if override.Tenant != "" {
+		c.Tenant = override.Tenant
+	}
+	if override.Signing != nil {
+		c.Signing = override.Signing
+	}
+	return c
+}
+
+// config checks the settings and converts them to a client configuration
+func (c profileConfig) config() (*Config, error) {
+	verr := &ValidationError{}
+
+	if c.BaseURL == "" {
+		verr.add("base_url", "is required")
+	} else if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		verr.add("base_url", "'%s' must be an absolute http or https URL", c.BaseURL)
+	}
+
+	switch APIVersion(c.APIVersion) {
+	case "", APIVersionV1, APIVersionV2, APIVersionDetect:
+	default:
+		verr.add("api_version", "'%s' must be %s, %s, or %s", c.APIVersion, APIVersionV1, APIVersionV2, APIVersionDetect)
+	}
+
+	switch DecodingMode(c.Decoding) {
+	case "", DecodeLenient, DecodeStrict:
+	default:
+		verr.add("decoding", "'%s' must be %s or %s", c.Decoding, DecodeLenient, DecodeStrict)
+	}
+
+	durations := []struct {
+		field string
+		value time.Duration
+	}{
+		{"timeout", c.Timeout},
+		{"health_check_timeout", c.HealthCheckTimeout},
+		{"dial_timeout", c.DialTimeout},
+		{"dedup_window", c.DedupWindow},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			verr.add(d.field, "cannot be negative")
+		}
+	}
+	if c.MaxResponseSize < 0 {
+		verr.add("max_response_size", "cannot be negative")
+	}
+
+	if c.Tenant != "" {
+		if err := validateTenant(c.Tenant); err != nil {
+			verr.add("tenant", "%v", err)
+		}
+	}
+	if c.Signing != nil && c.Signing.Secret == "" {
+		verr.add("signing.secret", "is required")
+	}
+
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	config := &Config{
+		BaseURL:            c.BaseURL,
+		Timeout:            c.Timeout,
+		HealthCheckTimeout: c.HealthCheckTimeout,
+		DialTimeout:        c.DialTimeout,
+		APIVersion:         APIVersion(c.APIVersion),
+		Decoding:           DecodingMode(c.Decoding),
+		MaxResponseSize:    c.MaxResponseSize,
+		DedupWindow:        c.DedupWindow,
+		UserAgentSuffix:    c.UserAgentSuffix,
+		Team:               c.Team,
+		CostCenter:         c.CostCenter,
+		Tenant:             c.Tenant,
+	}
+	if c.Signing != nil {
+		config.Signing = &SigningConfig{KeyID: c.Signing.KeyID, Secret: []byte(c.Signing.Secret)}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// expandConfigEnv replaces $VAR and ${VAR} in the string values under node
+// with environment variables and $$ with $, failing on variables that are
+// not set. Unquoted values are resolved again after expansion, so that e.g.
+// max_response_size: ${MAX_SIZE} decodes as a number
+func expandConfigEnv(node *yaml.Node) error {
+	var missing []string
+	var expand func(n *yaml.Node)
+	expand = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range n.Content {
+				expand(child)
+			}
+		case yaml.MappingNode:
+			// Keys alternate with values; only values are expanded
+			for i := 1; i < len(n.Content); i += 2 {
+				expand(n.Content[i])
+			}
+		case yaml.ScalarNode:
+			if n.ShortTag() != "!!str" || !strings.Contains(n.Value, "$") {
+				return
+			}
+			n.Value = os.Expand(n.Value, func(name string) string {
+				if name == "$" {
+					return "$"
+				}
+				value, ok := os.LookupEnv(name)
+				if !ok && !slices.Contains(missing, name) {
+					missing = append(missing, name)
+				}
+				return value
+			})
+			if n.Style == 0 {
+				n.Tag = ""
+			}
+		}
+	}
+	expand(node)
+
+	if len(missing) > 0 {
+		return fmt.Errorf("environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package sdk
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MW_SECRET", "s3cret")
+	path := writeConfigFile(t, "sdk.yaml", `
+base_url: http://localhost:8083
+timeout: 10s
+team: platform
+profiles:
+  staging:
+    base_url: https://messages-worker.staging.example.com
+  prod:
+    base_url: https://messages-worker.example.com
+    tenant: acme
+    signing:
+      key_id: key-1
+      secret: ${MW_SECRET}$$
+`)
+
+	file, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := strings.Join(file.Profiles(), ","); got != "prod,staging" {
+		t.Errorf("Expected profiles prod and staging, got %s", got)
+	}
+
+	config, err := file.Config("prod")
+	if err != nil {
+		t.Fatalf("Config failed: %v", err)
+	}
+	if config.BaseURL != "https://messages-worker.example.com" || config.Team != "platform" || config.Timeout != 10*time.Second || config.Tenant != "acme" {
+		t.Errorf("Expected the profile merged over the top-level settings, got %+v", config)
+	}
+	if string(config.Signing.Secret) != "s3cret$" {
+		t.Errorf("Expected the secret from the environment, got %q", config.Signing.Secret)
+	}
+
+	defaults, err := file.Config("")
+	if err != nil || defaults.BaseURL != "http://localhost:8083" || defaults.Signing != nil {
+		t.Errorf("Expected the top-level settings, got %+v, %v", defaults, err)
+	}
+
+	if _, err := file.Config("dev"); err == nil || !strings.Contains(err.Error(), "available: prod, staging") {
+		t.Errorf("Expected a missing profile error listing the profiles, got %v", err)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeConfigFile(t, "sdk.json", `{"base_url": "http://localhost:8083", "profiles": {"prod": {"base_url": "https://messages-worker.example.com", "timeout": "45s"}}}`)
+
+	file, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	config, err := file.Config("prod")
+	if err != nil || config.Timeout != 45*time.Second {
+		t.Errorf("Expected the JSON profile, got %+v, %v", config, err)
+	}
+}
+
+func TestLoadConfigExpandsStringValuesOnly(t *testing.T) {
+	t.Setenv("MW_TEAM", "platform\ntimeout: -1s")
+	t.Setenv("MW_MAX_SIZE", "1024")
+	path := writeConfigFile(t, "sdk.yaml", `
+# Set $MW_UNSET_IN_COMMENT to override
+base_url: http://localhost:8083
+team: ${MW_TEAM}
+max_response_size: ${MW_MAX_SIZE}
+user_agent_suffix: "${MW_MAX_SIZE}"
+`)
+
+	file, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	config, err := file.Config("")
+	if err != nil {
+		t.Fatalf("Config failed: %v", err)
+	}
+	if config.Team != "platform\ntimeout: -1s" || config.Timeout != 0 {
+		t.Errorf("Expected the variable to stay within its value, got team %q and timeout %v", config.Team, config.Timeout)
+	}
+	if config.MaxResponseSize != 1024 || config.UserAgentSuffix != "1024" {
+		t.Errorf("Expected unquoted values to be resolved after expansion, got %d and %q", config.MaxResponseSize, config.UserAgentSuffix)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	unset := writeConfigFile(t, "unset.yaml", "base_url: ${MW_UNSET_URL}\nsigning:\n  secret: $MW_UNSET_SECRET\n")
+	if _, err := LoadConfig(unset); err == nil || !strings.Contains(err.Error(), "MW_UNSET_URL, MW_UNSET_SECRET") {
+		t.Errorf("Expected an error naming the unset variables, got %v", err)
+	}
+
+	unknown := writeConfigFile(t, "unknown.yaml", "base_url: http://localhost\nprofiles:\n  prod:\n    baseurl: typo\n")
+	if _, err := LoadConfig(unknown); err == nil || !strings.Contains(err.Error(), "baseurl") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+
+	invalid := writeConfigFile(t, "invalid.yaml", "base_url: http://localhost\nprofiles:\n  prod:\n    base_url: localhost\n    api_version: v3\n    timeout: -1s\n    signing:\n      key_id: key-1\n")
+	file, err := LoadConfig(invalid)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	_, err = file.Config("prod")
+	var verr *ValidationError
+	if !errors.As(err, &verr) || !strings.Contains(err.Error(), "invalid profile 'prod'") {
+		t.Fatalf("Expected a ValidationError for the profile, got %v", err)
+	}
+	var fields []string
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	if got := strings.Join(fields, ","); got != "base_url,api_version,timeout,signing.secret" {
+		t.Errorf("Expected a violation per invalid field, got %v", verr)
+	}
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	path := writeConfigFile(t, "sdk.yaml", "base_url: http://localhost:8083\nprofiles:\n  prod:\n    base_url: https://messages-worker.example.com\n")
+	t.Setenv(ConfigFileEnv, path)
+
+	client, err := NewClientFromProfile("prod")
+	if err != nil {
+		t.Fatalf("NewClientFromProfile failed: %v", err)
+	}
+	if client.baseURL != "https://messages-worker.example.com" {
+		t.Errorf("Expected the prod base URL, got %s", client.baseURL)
+	}
+
+	if _, err := NewClientFromProfile("staging"); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+}
@@ -0,0 +1,251 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// QueueDepthPoint is a single queue depth sample
+type QueueDepthPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Depth     int       `json:"depth"`
+}
+
+// QueueDepthHistory represents queue depth samples over a time window
+type QueueDepthHistory struct {
+	Priority   Priority          `json:"priority"`
+	Window     string            `json:"window"`
+	Resolution string            `json:"resolution,omitempty"`
+	Points     []QueueDepthPoint `json:"points"`
+}
+
+// TrendDirection describes whether a queue is growing or shrinking
+type TrendDirection string
+
+const (
+	TrendGrowing   TrendDirection = "growing"
+	TrendShrinking TrendDirection = "shrinking"
+	TrendStable    TrendDirection = "stable"
+)
+
+// StableTrendThreshold is the fraction of the mean depth the queue may change
+// by over the sampled span while still counting as stable
+const StableTrendThreshold = 0.05
+
+// QueueTrend summarizes how queue depth changed over a history
+type QueueTrend struct {
+	Direction TrendDirection
+	// Slope is the least-squares rate of change in messages per minute
+	Slope float64
+}
+
+// Trend fits a line through the history's samples. The queue is stable when
+// the fitted change over the sampled span is under one message or under
+// StableTrendThreshold of the mean depth. Fewer than two samples are stable
+func (h *QueueDepthHistory) Trend() QueueTrend {
+	n := len(h.Points)
+	if n < 2 {
+		return QueueTrend{Direction: TrendStable}
+	}
+
+	start := h.Points[0].Timestamp
+	var sumX, sumY float64
+	for _, p := range h.Points {
+		sumX += p.Timestamp.Sub(start).Minutes()
+		sumY += float64(p.Depth)
+	}
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+
+	var cov, varX float64
+	for _, p := range h.Points {
+		dx := p.Timestamp.Sub(start).Minutes() - meanX
+		cov += dx * (float64(p.Depth) - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return QueueTrend{Direction: TrendStable}
+	}
+
+	slope := cov / varX
+	span := h.Points[n-1].Timestamp.Sub(start).Minutes()
+	change := math.Abs(slope * span)
+
+	trend := QueueTrend{Direction: TrendStable, Slope: slope}
+	if change >= 1 && change >= StableTrendThreshold*meanY {
+		if slope > 0 {
+			trend.Direction = TrendGrowing
+		} else {
+			trend.Direction = TrendShrinking
+		}
+	}
+	return trend
+}
+
+// GetQueueDepths returns the current queue depth of every priority
+func (c *Client) GetQueueDepths(ctx context.Context) (map[Priority]int, error) {
+	status, err := c.GetWorkerStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[Priority]int{
+		PriorityLow:    status.LowPriority.QueueDepth,
+		PriorityMedium: status.MediumPriority.QueueDepth,
+		PriorityHigh:   status.HighPriority.QueueDepth,
+	}, nil
+}
+
+// WaitForQueueEmpty polls the queue depth of priority until the queue is
+// empty on opts.Confirmations consecutive polls, e.g. so that a deployment
+// can cut over once a queue has drained, or until ctx expires. A non-empty
+// reading resets the count. Messages already dequeued by workers are not
+// counted
+func (c *Client) WaitForQueueEmpty(ctx context.Context, priority Priority, opts PollOptions) error {
+	if !priority.IsValid() {
+		return fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	opts = opts.withDefaults()
+	empty := 0
+	return c.poll(ctx, opts, func() (bool, error) {
+		depths, err := c.GetQueueDepths(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		depth := depths[priority]
+		if opts.OnProgress != nil {
+			opts.OnProgress(depth)
+		}
+		if depth > 0 {
+			empty = 0
+			return false, nil
+		}
+		empty++
+		return empty >= opts.Confirmations, nil
+	})
+}
+
+// GetQueueDepthHistory returns queue depth samples for a priority over the
+// given window, e.g. the last hour, one sample per resolution. A zero
+// resolution uses the service default
+func (c *Client) GetQueueDepthHistory(ctx context.Context, priority Priority, window, resolution time.Duration) (*QueueDepthHistory, error) {
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be greater than 0")
+	}
+
+	if resolution < 0 || resolution > window {
+		return nil, fmt.Errorf("resolution must be between 0 and the window")
+	}
+
+	query := url.Values{}
+	query.Set("window", window.String())
+	if resolution > 0 {
+		query.Set("resolution", resolution.String())
+	}
+	path := fmt.Sprintf("/api/v1/queues/%s/depth/history?%s", priority, query.Encode())
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var history QueueDepthHistory
+	if err := c.parseResponse(resp, &history); err != nil {
+		return nil, err
+	}
+
+	return &history, nil
+}
+
+// ErrPurgeNotConfirmed is returned when a purge is requested without Confirm set
+var ErrPurgeNotConfirmed = errors.New("purge requires PurgeOptions.Confirm to be true")
+
+// PurgeOptions guards purge operations against accidental data loss
+type PurgeOptions struct {
+	// Confirm must be true for the purge to be sent
+	Confirm bool
+}
+
+// PurgeResponse represents the result of a purge
+type PurgeResponse struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	Priority Priority `json:"priority,omitempty"`
+	Topic    Topic    `json:"topic,omitempty"`
+	Purged   int      `json:"purged"`
+}
+
+// PurgeQueue drops every queued message of the given priority and reports
+// how many were purged, waiting for the purge to finish if the service
+// processes it asynchronously
+func (c *Client) PurgeQueue(ctx context.Context, priority Priority, opts PurgeOptions) (*PurgeResponse, error) {
+	op, err := c.StartPurgeQueue(ctx, priority, opts)
+	if err != nil {
+		return nil, err
+	}
+	return waitForPurge(ctx, op)
+}
+
+// StartPurgeQueue requests a purge of the given priority queue and returns a
+// handle to the operation without waiting for it to finish
+func (c *Client) StartPurgeQueue(ctx context.Context, priority Priority, opts PurgeOptions) (*Operation, error) {
+	if !opts.Confirm {
+		return nil, ErrPurgeNotConfirmed
+	}
+
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	path := fmt.Sprintf("/api/v1/queues/%s/purge?confirm=true", priority)
+	return c.startOperation(ctx, http.MethodPost, path, "purge_queue", nil)
+}
+
+// PurgeTopic drops every queued message of the given topic across all
+// priorities and reports how many were purged
+func (c *Client) PurgeTopic(ctx context.Context, topic Topic, opts PurgeOptions) (*PurgeResponse, error) {
+	op, err := c.StartPurgeTopic(ctx, topic, opts)
+	if err != nil {
+		return nil, err
+	}
+	return waitForPurge(ctx, op)
+}
+
+// StartPurgeTopic requests a purge of the given topic and returns a handle to
+// the operation without waiting for it to finish
+func (c *Client) StartPurgeTopic(ctx context.Context, topic Topic, opts PurgeOptions) (*Operation, error) {
+	if !opts.Confirm {
+		return nil, ErrPurgeNotConfirmed
+	}
+
+	if topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+
+	path := fmt.Sprintf("/api/v1/topics/%s/purge?confirm=true", url.PathEscape(string(topic)))
+	return c.startOperation(ctx, http.MethodPost, path, "purge_topic", nil)
+}
+
+// waitForPurge waits for a purge operation and decodes its result
+func waitForPurge(ctx context.Context, op *Operation) (*PurgeResponse, error) {
+	if _, err := op.Wait(ctx, PollOptions{}); err != nil {
+		return nil, err
+	}
+
+	var purgeResp PurgeResponse
+	if err := op.Result(&purgeResp); err != nil {
+		return nil, err
+	}
+
+	return &purgeResp, nil
+}
@@ -0,0 +1,170 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetQueueDepths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(WorkerStatusResponse{
+			LowPriority:    PriorityWorkerInfo{QueueDepth: 12},
+			MediumPriority: PriorityWorkerInfo{QueueDepth: 4},
+			HighPriority:   PriorityWorkerInfo{QueueDepth: 1},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	depths, err := client.GetQueueDepths(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if depths[PriorityLow] != 12 || depths[PriorityMedium] != 4 || depths[PriorityHigh] != 1 {
+		t.Errorf("Unexpected depths: %v", depths)
+	}
+}
+
+func TestGetQueueDepthHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/queues/high/depth/history" {
+			t.Errorf("Expected path '/api/v1/queues/high/depth/history', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("window") != "1h0m0s" {
+			t.Errorf("Expected window '1h0m0s', got '%s'", r.URL.Query().Get("window"))
+		}
+		if r.URL.Query().Get("resolution") != "1m0s" {
+			t.Errorf("Expected resolution '1m0s', got '%s'", r.URL.Query().Get("resolution"))
+		}
+		w.Write([]byte(`{"priority":"high","window":"1h","points":[{"timestamp":"2024-01-01T00:00:00Z","depth":3},{"timestamp":"2024-01-01T00:01:00Z","depth":5}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	history, err := client.GetQueueDepthHistory(context.Background(), PriorityHigh, time.Hour, time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history.Points) != 2 || history.Points[1].Depth != 5 {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+
+	if _, err := client.GetQueueDepthHistory(context.Background(), "urgent", time.Hour, time.Minute); err == nil {
+		t.Error("Expected error for invalid priority, got nil")
+	}
+}
+
+func TestQueueDepthTrend(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := func(depths ...int) *QueueDepthHistory {
+		h := &QueueDepthHistory{}
+		for i, d := range depths {
+			h.Points = append(h.Points, QueueDepthPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Depth: d})
+		}
+		return h
+	}
+
+	growing := history(10, 20, 30, 40).Trend()
+	if growing.Direction != TrendGrowing || math.Abs(growing.Slope-10) > 1e-9 {
+		t.Errorf("Expected growing at 10/min, got %+v", growing)
+	}
+
+	if trend := history(40, 30, 20, 10).Trend(); trend.Direction != TrendShrinking {
+		t.Errorf("Expected shrinking, got %+v", trend)
+	}
+
+	if trend := history(1000, 1001, 999, 1002).Trend(); trend.Direction != TrendStable {
+		t.Errorf("Expected stable, got %+v", trend)
+	}
+
+	if trend := history(5).Trend(); trend.Direction != TrendStable || trend.Slope != 0 {
+		t.Errorf("Expected stable for a single sample, got %+v", trend)
+	}
+}
+
+func TestPurgeQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/queues/low/purge" || r.URL.Query().Get("confirm") != "true" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(PurgeResponse{Status: "success", Priority: PriorityLow, Purged: 42})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	if _, err := client.PurgeQueue(ctx, PriorityLow, PurgeOptions{}); err != ErrPurgeNotConfirmed {
+		t.Errorf("Expected ErrPurgeNotConfirmed, got %v", err)
+	}
+
+	resp, err := client.PurgeQueue(ctx, PriorityLow, PurgeOptions{Confirm: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Purged != 42 {
+		t.Errorf("Expected 42 purged, got %d", resp.Purged)
+	}
+}
+
+func TestPurgeTopic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/topics/deployments/purge" {
+			t.Errorf("Expected path '/api/v1/topics/deployments/purge', got '%s'", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(PurgeResponse{Status: "success", Topic: "deployments", Purged: 7})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	resp, err := client.PurgeTopic(context.Background(), "deployments", PurgeOptions{Confirm: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Purged != 7 || resp.Topic != "deployments" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestWaitForQueueEmpty(t *testing.T) {
+	depths := []int{5, 2, 0, 1, 0, 0}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		depth := depths[min(polls, len(depths)-1)]
+		polls++
+		json.NewEncoder(w).Encode(WorkerStatusResponse{HighPriority: PriorityWorkerInfo{QueueDepth: depth}, LowPriority: PriorityWorkerInfo{QueueDepth: 9}})
+	}))
+	defer server.Close()
+
+	var progress []int
+	client := NewClient(&Config{BaseURL: server.URL})
+	err := client.WaitForQueueEmpty(context.Background(), PriorityHigh, PollOptions{
+		Interval:      time.Millisecond,
+		Confirmations: 2,
+		OnProgress:    func(remaining int) { progress = append(progress, remaining) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForQueueEmpty failed: %v", err)
+	}
+	if polls != 6 {
+		t.Errorf("Expected the wait to end on the second consecutive empty reading, got %d polls", polls)
+	}
+	if len(progress) != 6 || progress[0] != 5 || progress[3] != 1 {
+		t.Errorf("Unexpected progress %v", progress)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.WaitForQueueEmpty(ctx, PriorityLow, PollOptions{Interval: time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if err := client.WaitForQueueEmpty(ctx, "urgent", PollOptions{}); err == nil {
+		t.Error("Expected an error for an invalid priority")
+	}
+}
@@ -0,0 +1,59 @@
+// Package receiver provides helpers for services that receive
+// messages-worker callbacks
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+type budgetKey struct{}
+
+// BudgetMiddleware reads the remaining latency budget the worker forwards in
+// sdk.BudgetHeader and makes it available to handlers through RemainingBudget
+func BudgetMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ctx, ok := withBudgetFromRequest(r); ok {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withBudgetFromRequest stores the budget deadline of r in its context
+func withBudgetFromRequest(r *http.Request) (context.Context, bool) {
+	raw := r.Header.Get(sdk.BudgetHeader)
+	if raw == "" {
+		return nil, false
+	}
+
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	deadline := time.Now().Add(time.Duration(ms) * time.Millisecond)
+	return context.WithValue(r.Context(), budgetKey{}, deadline), true
+}
+
+// RemainingBudget returns how much of the message's latency budget is left.
+// ok is false when the callback carried no budget; the duration is negative
+// once the budget is blown
+func RemainingBudget(ctx context.Context) (remaining time.Duration, ok bool) {
+	deadline, ok := ctx.Value(budgetKey{}).(time.Time)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// BudgetExceeded reports whether the callback carried a budget that has run
+// out, signalling that the handler should shed work
+func BudgetExceeded(ctx context.Context) bool {
+	remaining, ok := RemainingBudget(ctx)
+	return ok && remaining <= 0
+}
@@ -0,0 +1,45 @@
+package receiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+func TestBudgetMiddleware(t *testing.T) {
+	var remaining time.Duration
+	var ok, exceeded bool
+	handler := BudgetMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining, ok = RemainingBudget(r.Context())
+		exceeded = BudgetExceeded(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", nil)
+	req.Header.Set(sdk.BudgetHeader, "1500")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !ok {
+		t.Fatal("Expected budget to be present")
+	}
+	if remaining <= time.Second || remaining > 1500*time.Millisecond {
+		t.Errorf("Expected about 1.5s remaining, got %v", remaining)
+	}
+	if exceeded {
+		t.Error("Expected budget not to be exceeded")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/callback", nil)
+	req.Header.Set(sdk.BudgetHeader, "0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !exceeded {
+		t.Error("Expected exhausted budget to be exceeded")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/callback", nil))
+	if ok {
+		t.Error("Expected no budget without the header")
+	}
+}
@@ -0,0 +1,72 @@
+package receiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// encryptionHeader is the header in which workers echo the
+// sdk.EncryptionMetadataKey metadata of encrypted messages
+const encryptionHeader = sdk.MetadataHeaderPrefix + sdk.EncryptionMetadataKey
+
+// Decrypt opens the result of callbacks for messages whose ObjectBody was
+// encrypted by the submitting client's sdk.PayloadEncrypter, as marked by
+// the sdk.EncryptionMetadataKey metadata, and passes next the callback with
+// the plaintext result. The callback is an sdk.CallbackEnvelope, and only
+// its result is decrypted, when the worker returned the sealed payload;
+// other results and other callbacks pass through unchanged. A body that is
+// not a callback envelope is answered with 400, and a result that cannot be
+// decrypted with 503 so that the worker redelivers it, e.g. once the key
+// provider is reachable
+func Decrypt(dec sdk.PayloadDecrypter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(encryptionHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, sdk.MaxCallbackSize))
+		r.Body.Close()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// The envelope is kept as raw fields, so that fields this package
+		// does not know reach next as sent
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if result := envelope["result"]; len(result) > 0 && string(result) != "null" {
+			plaintext, err := sdk.DecryptPayload(r.Context(), dec, result)
+			switch {
+			case errors.Is(err, sdk.ErrInvalidEncryptedPayload):
+				// The worker returned a result of its own
+			case err != nil:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			default:
+				envelope["result"] = plaintext
+				if body, err = json.Marshal(envelope); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				r.Header.Del(encryptionHeader)
+			}
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,73 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+func TestDecrypt(t *testing.T) {
+	keys, err := sdk.NewStaticKeyProvider("test-key", bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewStaticKeyProvider failed: %v", err)
+	}
+	encrypter := sdk.NewAESGCMEncrypter(keys)
+	payload, err := encrypter.Encrypt(context.Background(), []byte(`{"token":"s3cret"}`))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	envelope := func(result interface{}) []byte {
+		raw, _ := json.Marshal(result)
+		body, _ := json.Marshal(sdk.CallbackEnvelope{
+			MessageID: "msg-1", ItemID: "pr-1", Attempt: 1, Status: sdk.StatusCompleted,
+			Result: raw, Metadata: map[string]string{sdk.EncryptionMetadataKey: sdk.AlgorithmAES256GCM},
+		})
+		return body
+	}
+
+	var received *sdk.CallbackEnvelope
+	var body []byte
+	handler := Decrypt(encrypter, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		received, _ = sdk.DecodeCallback(r)
+	}))
+	serve := func(data []byte, encryptedHeader bool) int {
+		body, received = nil, nil
+		req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(data))
+		if encryptedHeader {
+			req.Header.Set(sdk.MetadataHeaderPrefix+sdk.EncryptionMetadataKey, sdk.AlgorithmAES256GCM)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(envelope(payload), true); code != http.StatusOK || received == nil || string(received.Result) != `{"token":"s3cret"}` {
+		t.Errorf("Expected the plaintext result, got %d %s", code, body)
+	}
+	if received != nil && (received.MessageID != "msg-1" || received.Status != sdk.StatusCompleted) {
+		t.Errorf("Expected the rest of the envelope to be kept, got %+v", received)
+	}
+	plain := envelope(map[string]bool{"plain": true})
+	if code := serve(plain, false); code != http.StatusOK || !bytes.Equal(body, plain) {
+		t.Errorf("Expected an unencrypted callback to pass through, got %d %s", code, body)
+	}
+	if code := serve(plain, true); code != http.StatusOK || received == nil || string(received.Result) != `{"plain":true}` {
+		t.Errorf("Expected a result of the worker's own to pass through, got %d %s", code, body)
+	}
+	if code := serve([]byte(`not a callback`), true); code != http.StatusBadRequest || body != nil {
+		t.Errorf("Expected 400 for a body that is not a callback envelope, got %d", code)
+	}
+
+	payload.KeyID = "rotated-key"
+	if code := serve(envelope(payload), true); code != http.StatusServiceUnavailable || body != nil {
+		t.Errorf("Expected 503 for a result that cannot be decrypted, got %d", code)
+	}
+}
@@ -0,0 +1,67 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Drainer tracks the callbacks being handled by next, so that a shutdown
+// can wait for them before the process exits. It satisfies sdk.Drainer, and
+// Drain can be registered with sdk.Client.RegisterShutdownHook
+type Drainer struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	inFlight int
+	idle     chan struct{}
+}
+
+// NewDrainer wraps next so that its in-flight callbacks can be drained
+func NewDrainer(next http.Handler) *Drainer {
+	return &Drainer{next: next}
+}
+
+// ServeHTTP passes the callback to next, or answers it with 503 once Drain
+// has been called so that the worker redelivers it to another instance
+func (d *Drainer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	if d.idle != nil {
+		d.mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	d.inFlight++
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.inFlight--
+		if d.idle != nil && d.inFlight == 0 {
+			close(d.idle)
+		}
+	}()
+	d.next.ServeHTTP(w, r)
+}
+
+// Drain stops accepting callbacks and waits until those being handled have
+// finished, or until ctx is done
+func (d *Drainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	if d.idle == nil {
+		d.idle = make(chan struct{})
+		if d.inFlight == 0 {
+			close(d.idle)
+		}
+	}
+	idle := d.idle
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
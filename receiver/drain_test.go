@@ -0,0 +1,52 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+var _ sdk.Drainer = (*Drainer)(nil)
+
+func TestDrainer(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	drainer := NewDrainer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	handled := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		drainer.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", nil))
+		handled <- rec.Code
+	}()
+	<-started
+
+	// Drain waits for the callback in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := drainer.Drain(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected Drain to wait for the in-flight callback, got %v", err)
+	}
+
+	// New callbacks are turned away for redelivery
+	rec := httptest.NewRecorder()
+	drainer.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %d", rec.Code)
+	}
+
+	close(release)
+	if code := <-handled; code != http.StatusOK {
+		t.Errorf("Expected the in-flight callback to complete, got %d", code)
+	}
+	if err := drainer.Drain(context.Background()); err != nil {
+		t.Errorf("Expected Drain to succeed once idle, got %v", err)
+	}
+}
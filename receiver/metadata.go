@@ -0,0 +1,20 @@
+package receiver
+
+import (
+	"net/http"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// MetadataMiddleware attaches the metadata echoed on a callback in
+// X-Meta-<key> headers to the request context, so that handlers can read it
+// with sdk.MetadataFromContext and messages they submit with that context
+// carry it forward
+func MetadataMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if md := sdk.MetadataFromHeader(r.Header); len(md) > 0 {
+			r = r.WithContext(sdk.WithMetadata(r.Context(), md))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
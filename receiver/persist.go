@@ -0,0 +1,256 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// Record is a callback that was received but whose handler has not finished
+type Record struct {
+	ID         string      `json:"id"`
+	ReceivedAt time.Time   `json:"received_at"`
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+}
+
+// Request rebuilds the callback as an *http.Request, e.g. to pass it to the
+// original handler again
+func (r Record) Request(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.Path, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	return req, nil
+}
+
+// Store durably holds in-flight callback records
+type Store interface {
+	Save(ctx context.Context, record Record) error
+	Delete(ctx context.Context, id string) error
+	// Pending returns every saved record, oldest first
+	Pending(ctx context.Context) ([]Record, error)
+}
+
+// Persist writes each callback to store before calling next and removes it
+// once next returns. If the process crashes mid-handler, the record survives
+// and can be handled on restart with Recover. When the record cannot be
+// saved the callback is answered with 503 so that the worker redelivers it
+// instead of it being acknowledged and lost
+func Persist(store Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 2*sdk.MaxPayloadSize))
+		r.Body.Close()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		id, err := newRecordID()
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		record := Record{
+			ID:         id,
+			ReceivedAt: time.Now().UTC(),
+			Method:     r.Method,
+			Path:       r.URL.RequestURI(),
+			Header:     r.Header.Clone(),
+			Body:       body,
+		}
+		if err := store.Save(r.Context(), record); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+
+		// Not deferred: a panicking handler must leave its record behind.
+		// A failure to delete only means the callback is recovered twice
+		store.Delete(context.WithoutCancel(r.Context()), record.ID)
+	})
+}
+
+// Recover passes every pending record to handle, oldest first, and deletes
+// those it handles without error. It returns how many were recovered and
+// the errors of the others
+func Recover(ctx context.Context, store Store, handle func(context.Context, Record) error) (int, error) {
+	records, err := store.Pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	var errs []error
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return recovered, err
+		}
+		if err := handle(ctx, record); err != nil {
+			errs = append(errs, fmt.Errorf("record %s: %w", record.ID, err))
+			continue
+		}
+		if err := store.Delete(ctx, record.ID); err != nil {
+			errs = append(errs, fmt.Errorf("record %s: %w", record.ID, err))
+			continue
+		}
+		recovered++
+	}
+
+	return recovered, errors.Join(errs...)
+}
+
+// newRecordID returns a random, time-ordered record ID
+func newRecordID() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%020d-%s", time.Now().UnixNano(), hex.EncodeToString(suffix)), nil
+}
+
+// FileStore keeps each record in its own file in a directory, synced to
+// disk before the handler runs
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates dir if needed and returns a store backed by it
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save durably writes the record
+func (s *FileStore) Save(ctx context.Context, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "record-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create record: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync record: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close record: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(record.ID)); err != nil {
+		return fmt.Errorf("failed to save record: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the record; deleting a missing record is not an error
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return nil
+}
+
+// Pending reads every saved record, oldest first
+func (s *FileStore) Pending(ctx context.Context) ([]Record, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse record %s: %w", entry.Name(), err)
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
+
+// path returns the file holding the record with the given ID
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
+
+// MemoryStore keeps records in memory; it does not survive a crash and is
+// meant for tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Save stores the record
+func (s *MemoryStore) Save(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.ID] = record
+	return nil
+}
+
+// Delete removes the record
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	return nil
+}
+
+// Pending returns every stored record, oldest first
+func (s *MemoryStore) Pending(ctx context.Context) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
@@ -0,0 +1,100 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPersistRemovesHandledCallbacks(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var body string
+	handler := Persist(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pending, _ := store.Pending(r.Context())
+		if len(pending) != 1 {
+			t.Errorf("Expected the callback to be recorded while handled, got %d records", len(pending))
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{"id":"msg-1"}`)))
+
+	if rec.Code != http.StatusNoContent || body != `{"id":"msg-1"}` {
+		t.Errorf("Unexpected handling: status %d, body '%s'", rec.Code, body)
+	}
+	if pending, _ := store.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected no pending records, got %d", len(pending))
+	}
+}
+
+func TestRecoverAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir)
+
+	handler := Persist(store, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("consumer crashed")
+	}))
+
+	func() {
+		defer func() { recover() }()
+		req := httptest.NewRequest(http.MethodPost, "/callback?x=1", strings.NewReader(`{"id":"msg-1"}`))
+		req.Header.Set("X-Test", "yes")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	// A new process opens the same directory
+	restarted, _ := NewFileStore(dir)
+
+	var recovered []Record
+	n, err := Recover(context.Background(), restarted, func(ctx context.Context, record Record) error {
+		recovered = append(recovered, record)
+		req, err := record.Request(ctx)
+		if err != nil {
+			return err
+		}
+		if req.URL.Query().Get("x") != "1" || req.Header.Get("X-Test") != "yes" {
+			t.Errorf("Unexpected rebuilt request: %s %v", req.URL, req.Header)
+		}
+		return nil
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 recovered record, got %d, %v", n, err)
+	}
+	if string(recovered[0].Body) != `{"id":"msg-1"}` {
+		t.Errorf("Unexpected recovered body '%s'", recovered[0].Body)
+	}
+	if pending, _ := restarted.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected recovered records to be deleted, got %d", len(pending))
+	}
+}
+
+type failingStore struct{ *MemoryStore }
+
+func (failingStore) Save(ctx context.Context, record Record) error {
+	return errors.New("disk full")
+}
+
+func TestPersistRejectsWhenStoreFails(t *testing.T) {
+	called := false
+	handler := Persist(failingStore{NewMemoryStore()}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(`{}`)))
+
+	if called || rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without calling the handler, got %d (called=%v)", rec.Code, called)
+	}
+}
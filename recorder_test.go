@@ -0,0 +1,116 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"id":"msg-1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "post.json")
+	recorder, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recorder.Redact = []string{"X-Team"}
+
+	client := NewClient(&Config{
+		BaseURL:  server.URL,
+		Signing:  &SigningConfig{Secret: []byte("s3cret"), KeyID: "key-1"},
+		Team:     "acme",
+		Recorder: recorder,
+	})
+
+	ctx := context.Background()
+	if _, err := client.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/callback", map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected fixture to be written, got %v", err)
+	}
+	var recorded fixture
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		t.Fatalf("Expected valid fixture, got %v", err)
+	}
+	if len(recorded.Interactions) != 1 {
+		t.Fatalf("Expected 1 interaction, got %d", len(recorded.Interactions))
+	}
+	interaction := recorded.Interactions[0]
+	for _, name := range []string{"X-Team", SignatureHeader, SignatureKeyIDHeader} {
+		if got := interaction.Request.Header.Get(name); got != RedactedValue {
+			t.Errorf("Expected request header %s to be redacted, got %q", name, got)
+		}
+	}
+	if got := interaction.Response.Header.Get("Set-Cookie"); got != RedactedValue {
+		t.Errorf("Expected Set-Cookie to be redacted, got %q", got)
+	}
+	server.Close()
+
+	replayer, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client = NewClient(&Config{BaseURL: "http://replay.invalid", Recorder: replayer})
+
+	resp, err := client.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/callback", map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.ID != "msg-1" || resp.Status != "queued" {
+		t.Errorf("Expected replayed response, got %+v", resp)
+	}
+
+	_, err = client.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/callback", map[string]string{"a": "b"})
+	if !errors.Is(err, ErrNoRecordedInteraction) {
+		t.Errorf("Expected ErrNoRecordedInteraction once the interaction is used, got %v", err)
+	}
+}
+
+func TestRecorderMatchBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	fixture := `{"interactions":[{
+		"request":{"method":"POST","url":"http://example.com/api/v1/messages","body":"{\"item_id\":\"other\"}"},
+		"response":{"status_code":200,"body":"{\"id\":\"msg-1\"}"}
+	}]}`
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	replayer.MatchBody = true
+	client := NewClient(&Config{BaseURL: "http://example.com", Recorder: replayer})
+
+	_, err = client.PostMessageWithDefaults(context.Background(), "pr-1", "https://example.com/callback", nil)
+	if !errors.Is(err, ErrNoRecordedInteraction) {
+		t.Errorf("Expected ErrNoRecordedInteraction for a different body, got %v", err)
+	}
+}
+
+func TestNewRecorderErrors(t *testing.T) {
+	if _, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+		t.Error("Expected error for a missing fixture")
+	}
+	if _, err := NewRecorder("fixture.json", RecorderMode("live")); err == nil {
+		t.Error("Expected error for an unknown mode")
+	}
+}
@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// reprioritizeRequest is the body of a priority change
+type reprioritizeRequest struct {
+	Priority Priority `json:"priority"`
+}
+
+// UpdateMessagePriority moves a queued message to another priority queue,
+// e.g. to bump a change that became a hotfix, and returns its new state.
+// Messages already being processed or finished cannot be moved; the
+// service rejects them with 409 Conflict
+func (c *Client) UpdateMessagePriority(ctx context.Context, id string, priority Priority) (*MessageDetail, error) {
+	if id == "" {
+		return nil, fmt.Errorf("message id is required")
+	}
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	path := "/api/v1/messages/" + url.PathEscape(id) + "/reprioritize"
+	resp, err := c.doRequest(ctx, http.MethodPost, path, reprioritizeRequest{Priority: priority})
+	if err != nil {
+		return nil, err
+	}
+
+	var detail MessageDetail
+	if err := c.parseResponse(resp, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// ReprioritizeFilter selects the queued messages moved by
+// ReprioritizeMessages; at least one of Topic and ItemID is required
+type ReprioritizeFilter struct {
+	Topic  Topic  `json:"topic,omitempty"`
+	ItemID string `json:"item_id,omitempty"`
+	// Priority restricts the change to messages currently in that queue
+	Priority Priority `json:"from_priority,omitempty"`
+}
+
+// bulkReprioritizeRequest is the body of a filtered priority change
+type bulkReprioritizeRequest struct {
+	ReprioritizeFilter
+	NewPriority Priority `json:"priority"`
+}
+
+// ReprioritizeResponse represents the result of a filtered priority change
+type ReprioritizeResponse struct {
+	Status   string   `json:"status"`
+	Priority Priority `json:"priority"`
+	// Updated is the number of messages moved
+	Updated int `json:"updated"`
+}
+
+// ReprioritizeMessages moves every queued message matching filter to
+// priority and reports how many were moved, waiting for the change to
+// finish if the service processes it asynchronously
+func (c *Client) ReprioritizeMessages(ctx context.Context, filter ReprioritizeFilter, priority Priority) (*ReprioritizeResponse, error) {
+	op, err := c.StartReprioritizeMessages(ctx, filter, priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := op.Wait(ctx, PollOptions{}); err != nil {
+		return nil, err
+	}
+
+	var result ReprioritizeResponse
+	if err := op.Result(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// StartReprioritizeMessages requests a filtered priority change and returns
+// a handle to the operation without waiting for it to finish
+func (c *Client) StartReprioritizeMessages(ctx context.Context, filter ReprioritizeFilter, priority Priority) (*Operation, error) {
+	if filter.Topic == "" && filter.ItemID == "" {
+		return nil, fmt.Errorf("filter requires a topic or an item ID")
+	}
+	if filter.Priority != "" && !filter.Priority.IsValid() {
+		return nil, fmt.Errorf("filter priority must be 'low', 'medium', or 'high'")
+	}
+	if !priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	body := bulkReprioritizeRequest{ReprioritizeFilter: filter, NewPriority: priority}
+	return c.startOperation(ctx, http.MethodPost, "/api/v1/messages/reprioritize", "reprioritize_messages", body)
+}
@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpdateMessagePriority(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/messages/msg-1/reprioritize":
+			var req reprioritizeRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(MessageDetail{ID: "msg-1", Priority: req.Priority, Status: "queued"})
+		case "/api/v1/messages/msg-2/reprioritize":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"conflict","message":"message is processing"}}`))
+		case "/api/v1/messages/reprioritize":
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["topic"] != string(TopicPullRequests) || req["from_priority"] != "low" || req["priority"] != "high" {
+				t.Errorf("Unexpected bulk request: %v", req)
+			}
+			json.NewEncoder(w).Encode(ReprioritizeResponse{Status: "success", Priority: PriorityHigh, Updated: 4})
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	detail, err := client.UpdateMessagePriority(ctx, "msg-1", PriorityHigh)
+	if err != nil || detail.Priority != PriorityHigh {
+		t.Fatalf("Expected message moved to high, got %+v, %v", detail, err)
+	}
+
+	_, err = client.UpdateMessagePriority(ctx, "msg-2", PriorityHigh)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected conflict for processing message, got %v", err)
+	}
+
+	if _, err := client.UpdateMessagePriority(ctx, "msg-1", "urgent"); err == nil {
+		t.Error("Expected error for invalid priority")
+	}
+
+	result, err := client.ReprioritizeMessages(ctx, ReprioritizeFilter{Topic: TopicPullRequests, Priority: PriorityLow}, PriorityHigh)
+	if err != nil || result.Updated != 4 {
+		t.Fatalf("Expected 4 messages moved, got %+v, %v", result, err)
+	}
+
+	if _, err := client.ReprioritizeMessages(ctx, ReprioritizeFilter{Priority: PriorityLow}, PriorityHigh); err == nil {
+		t.Error("Expected error for filter without topic or item ID")
+	}
+}
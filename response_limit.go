@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the largest response body read when
+// Config.MaxResponseSize is not set, in bytes
+const DefaultMaxResponseSize = 32 << 20
+
+// MaxErrorResponseSize is the most of an error response body that is read,
+// in bytes; longer error bodies are truncated rather than rejected
+const MaxErrorResponseSize = 64 << 10
+
+// ErrResponseTooLarge is returned when a response body exceeds
+// Config.MaxResponseSize
+var ErrResponseTooLarge = errors.New("response too large")
+
+// limitedBody reads at most limit bytes from r and fails with
+// ErrResponseTooLarge if r has more
+type limitedBody struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func newLimitedBody(r io.Reader, limit int64) *limitedBody {
+	return &limitedBody{r: r, limit: limit, remaining: limit}
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for a byte past the limit
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.limit)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// readResponseBody reads the body of resp within the client's size limit.
+// Error bodies are truncated to MaxErrorResponseSize instead
+func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, MaxErrorResponseSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return body, nil
+	}
+
+	// Read into a pooled buffer and copy out once, rather than growing a new
+	// slice repeatedly for large bodies
+	buf := getBuffer()
+	defer putBuffer(buf)
+	_, err := buf.ReadFrom(newLimitedBody(resp.Body, c.maxResponseSize))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// streamsResponses reports whether successful responses can be decoded
+// straight from the connection. Version mapping, response hooks, and schema
+// checks all need the whole body, so any of them turns streaming off
+func (c *Client) streamsResponses() bool {
+	return c.APIVersion() == APIVersionV1 && len(c.hooks) == 0 &&
+		c.decoding != DecodeStrict && c.logger == nil
+}
+
+// decodeResponseStream decodes a successful response into target without
+// buffering the body, within the client's size limit
+func (c *Client) decodeResponseStream(resp *http.Response, target interface{}) error {
+	body := newLimitedBody(resp.Body, c.maxResponseSize)
+	err := json.NewDecoder(body).Decode(target)
+	// Drain trailing whitespace so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(body, 4096))
+	if errors.Is(err, ErrResponseTooLarge) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
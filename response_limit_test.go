@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	workers := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/workers/status" {
+			w.Write([]byte(`{"total_workers":` + fmt.Sprint(workers) + `,"all_workers":[`))
+			for i := 0; i < workers; i++ {
+				if i > 0 {
+					w.Write([]byte(","))
+				}
+				fmt.Fprintf(w, `{"id":"worker-%d","status":"idle"}`, i)
+			}
+			w.Write([]byte(`]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"` + strings.Repeat("x", 10*MaxErrorResponseSize) + `"}`))
+	}))
+	defer server.Close()
+
+	hook := func(resp *http.Response, body []byte) ([]byte, error) { return body, nil }
+	for name, config := range map[string]*Config{
+		"streaming": {BaseURL: server.URL, MaxResponseSize: 4096},
+		"buffered":  {BaseURL: server.URL, MaxResponseSize: 4096, ResponseHooks: []ResponseHook{hook}},
+	} {
+		client := NewClient(config)
+		ctx := context.Background()
+
+		workers = 10
+		status, err := client.GetWorkerStatus(ctx)
+		if err != nil || len(status.AllWorkers) != 10 {
+			t.Fatalf("%s: expected small response to decode, got %v", name, err)
+		}
+
+		workers = 1000
+		if _, err := client.GetWorkerStatus(ctx); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("%s: expected ErrResponseTooLarge, got %v", name, err)
+		}
+
+		// Oversized error bodies are truncated, not rejected
+		var apiErr *APIError
+		if _, err := client.GetWorker(ctx, "worker-1"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("%s: expected APIError from truncated body, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers read into ResponseMeta
+const (
+	RequestIDHeader          = "X-Request-Id"
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader holds the Unix time, in seconds, at which the
+	// rate limit window resets
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+// RateLimit is the service's rate limit as reported on a response
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	// Reset is when the current window ends; zero when not reported
+	Reset time.Time
+}
+
+// ResponseMeta describes a response beyond its body, for correlating client
+// calls with the service's logs and traces
+type ResponseMeta struct {
+	StatusCode int
+	// RequestID is the ID the service assigned to the request; give it to
+	// support when reporting a failure
+	RequestID string
+	// RateLimit is nil unless the response reported both the limit and the
+	// remaining quota
+	RateLimit *RateLimit
+	// RetryAfter is how long the service asked clients to wait, from the
+	// Retry-After header
+	RetryAfter time.Duration
+}
+
+// responseMetaFrom reads the metadata of resp
+func (c *Client) responseMetaFrom(resp *http.Response) ResponseMeta {
+	meta := ResponseMeta{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(RequestIDHeader),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now()),
+	}
+
+	limit, limitErr := strconv.Atoi(resp.Header.Get(RateLimitLimitHeader))
+	remaining, remainingErr := strconv.Atoi(resp.Header.Get(RateLimitRemainingHeader))
+	// A limit is only reported when both headers are, since a missing one
+	// would read as a quota of zero
+	if limitErr == nil && remainingErr == nil {
+		meta.RateLimit = &RateLimit{Limit: limit, Remaining: remaining}
+		if reset, err := strconv.ParseInt(resp.Header.Get(RateLimitResetHeader), 10, 64); err == nil {
+			meta.RateLimit.Reset = time.Unix(reset, 0)
+		}
+	}
+	return meta
+}
+
+// parseRetryAfter parses a Retry-After value given in seconds or as an HTTP
+// date, returning zero when it is absent or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// responseMetaHolder receives the metadata of the responses to requests
+// made with its context
+type responseMetaHolder struct {
+	mu   sync.Mutex
+	meta *ResponseMeta
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context that captures the metadata of the
+// responses to requests made with it, for ResponseMetaFromContext:
+//
+//	ctx = sdk.WithResponseMeta(ctx)
+//	_, err := client.PostMessage(ctx, req)
+//	if meta := sdk.ResponseMetaFromContext(ctx); meta != nil {
+//		log.Printf("request ID %s", meta.RequestID)
+//	}
+func WithResponseMeta(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, &responseMetaHolder{})
+}
+
+// ResponseMetaFromContext returns the metadata of the last response received
+// for a request made with ctx, or nil when ctx was not prepared with
+// WithResponseMeta or no response has been received yet
+func ResponseMetaFromContext(ctx context.Context) *ResponseMeta {
+	holder, ok := ctx.Value(responseMetaKey{}).(*responseMetaHolder)
+	if !ok {
+		return nil
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	if holder.meta == nil {
+		return nil
+	}
+	meta := *holder.meta
+	return &meta
+}
+
+// recordResponseMeta stores the metadata of resp in the holder of the
+// request's context, if any
+func (c *Client) recordResponseMeta(resp *http.Response) {
+	if resp.Request == nil {
+		return
+	}
+	holder, ok := resp.Request.Context().Value(responseMetaKey{}).(*responseMetaHolder)
+	if !ok {
+		return
+	}
+	meta := c.responseMetaFrom(resp)
+	holder.mu.Lock()
+	holder.meta = &meta
+	holder.mu.Unlock()
+}
@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseMetaFromContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-123")
+		w.Header().Set(RateLimitLimitHeader, "100")
+		w.Header().Set(RateLimitRemainingHeader, "42")
+		w.Header().Set(RateLimitResetHeader, "1767225600")
+		w.Write([]byte(`{"status":"queued","item_id":"pr-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if _, err := client.PostMessage(context.Background(), newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	ctx := WithResponseMeta(context.Background())
+	if meta := ResponseMetaFromContext(ctx); meta != nil {
+		t.Errorf("Expected no metadata before a request, got %+v", meta)
+	}
+	if _, err := client.PostMessage(ctx, newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	meta := ResponseMetaFromContext(ctx)
+	if meta == nil || meta.RequestID != "req-123" || meta.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the response's request ID, got %+v", meta)
+	}
+	if meta.RateLimit == nil || meta.RateLimit.Limit != 100 || meta.RateLimit.Remaining != 42 || !meta.RateLimit.Reset.Equal(time.Unix(1767225600, 0)) {
+		t.Errorf("Unexpected rate limit %+v", meta.RateLimit)
+	}
+	if ResponseMetaFromContext(context.Background()) != nil {
+		t.Error("Expected no metadata for a context without WithResponseMeta")
+	}
+}
+
+func TestResponseMetaPartialRateLimit(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://example.com"})
+	for _, header := range []string{RateLimitLimitHeader, RateLimitRemainingHeader} {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		resp.Header.Set(header, "42")
+		if meta := client.responseMetaFrom(resp); meta.RateLimit != nil {
+			t.Errorf("Expected no rate limit with only %s, got %+v", header, meta.RateLimit)
+		}
+	}
+}
+
+func TestAPIErrorResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "req-456")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"rate_limited","message":"slow down"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	_, err := client.GetMessage(context.Background(), "msg-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.Meta.RequestID != "req-456" || apiErr.Meta.RetryAfter != 30*time.Second || apiErr.Meta.RateLimit != nil {
+		t.Errorf("Unexpected response metadata %+v", apiErr.Meta)
+	}
+	if !strings.Contains(err.Error(), "request ID req-456") {
+		t.Errorf("Expected the error message to include the request ID, got '%v'", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BackoffStrategy is how the delay between processing attempts grows
+type BackoffStrategy string
+
+// Backoff strategies
+const (
+	// BackoffFixed waits InitialDelay before every retry
+	BackoffFixed BackoffStrategy = "fixed"
+	// BackoffLinear waits InitialDelay times the number of failed attempts
+	BackoffLinear BackoffStrategy = "linear"
+	// BackoffExponential doubles the delay after every failed attempt
+	BackoffExponential BackoffStrategy = "exponential"
+)
+
+// IsValid reports whether s is one of the known backoff strategies
+func (s BackoffStrategy) IsValid() bool {
+	switch s {
+	case BackoffFixed, BackoffLinear, BackoffExponential:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defaults for fields left unset on a RetryPolicy
+const (
+	DefaultRetryMaxAttempts  = 5
+	DefaultRetryBackoff      = BackoffExponential
+	DefaultRetryInitialDelay = time.Second
+	DefaultRetryMaxDelay     = 5 * time.Minute
+)
+
+// MaxRetryAttempts is the largest MaxAttempts accepted by the service
+const MaxRetryAttempts = 25
+
+// RetryPolicy controls how workers retry a message whose callback fails.
+// Unset fields take the DefaultRetry values
+type RetryPolicy struct {
+	// MaxAttempts is the total number of processing attempts, including the
+	// first; 1 disables retries
+	MaxAttempts int
+	Backoff     BackoffStrategy
+	// InitialDelay is the delay before the first retry
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts
+	MaxDelay time.Duration
+	// DiscardOnExhaustion drops a message that failed every attempt instead
+	// of moving it to the dead-letter queue
+	DiscardOnExhaustion bool
+}
+
+// Common retry policies
+var (
+	// NoRetry makes a single attempt and dead-letters the message if it fails
+	NoRetry = RetryPolicy{MaxAttempts: 1}
+	// DefaultRetryPolicy retries with exponential backoff from one second
+	// up to five minutes, five attempts in total
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts:  DefaultRetryMaxAttempts,
+		Backoff:      DefaultRetryBackoff,
+		InitialDelay: DefaultRetryInitialDelay,
+		MaxDelay:     DefaultRetryMaxDelay,
+	}
+	// PersistentRetryPolicy keeps retrying for over twelve hours, for callbacks
+	// that must eventually succeed through long downstream outages
+	PersistentRetryPolicy = RetryPolicy{
+		MaxAttempts:  MaxRetryAttempts,
+		Backoff:      BackoffExponential,
+		InitialDelay: 5 * time.Second,
+		MaxDelay:     time.Hour,
+	}
+	// BestEffortRetryPolicy retries briefly and then drops the message, for
+	// notifications that are worthless once stale
+	BestEffortRetryPolicy = RetryPolicy{
+		MaxAttempts:         3,
+		Backoff:             BackoffFixed,
+		InitialDelay:        time.Second,
+		MaxDelay:            time.Second,
+		DiscardOnExhaustion: true,
+	}
+)
+
+// withDefaults fills unset retry policy fields
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if p.Backoff == "" {
+		p.Backoff = DefaultRetryBackoff
+	}
+	if p.InitialDelay == 0 {
+		p.InitialDelay = DefaultRetryInitialDelay
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = DefaultRetryMaxDelay
+		if p.MaxDelay < p.InitialDelay {
+			p.MaxDelay = p.InitialDelay
+		}
+	}
+	return p
+}
+
+// Delay returns how long workers wait before the given attempt, counting
+// the first attempt as 1; it is zero for the first attempt
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	p = p.withDefaults()
+	if attempt <= 1 {
+		return 0
+	}
+
+	retries := attempt - 1
+	delay := p.InitialDelay
+	switch p.Backoff {
+	case BackoffLinear:
+		delay = p.InitialDelay * time.Duration(retries)
+	case BackoffExponential:
+		for i := 1; i < retries && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+	}
+	if delay > p.MaxDelay || delay < 0 {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// validate appends the violations of p to verr, prefixing field names with prefix
+func (p RetryPolicy) validate(prefix string, verr *ValidationError) {
+	p = p.withDefaults()
+	if p.MaxAttempts < 1 || p.MaxAttempts > MaxRetryAttempts {
+		verr.add(prefix+"max_attempts", "must be between 1 and %d, got %d", MaxRetryAttempts, p.MaxAttempts)
+	}
+	if !p.Backoff.IsValid() {
+		verr.add(prefix+"backoff", "must be 'fixed', 'linear', or 'exponential', got '%s'", p.Backoff)
+	}
+	if p.InitialDelay < 0 {
+		verr.add(prefix+"initial_delay", "cannot be negative")
+	}
+	if p.MaxDelay < 0 {
+		verr.add(prefix+"max_delay", "cannot be negative")
+	} else if p.MaxDelay < p.InitialDelay {
+		verr.add(prefix+"max_delay", "cannot be shorter than initial_delay")
+	}
+}
+
+// retryPolicyJSON is the wire form of RetryPolicy
+type retryPolicyJSON struct {
+	MaxAttempts    int             `json:"max_attempts"`
+	Backoff        BackoffStrategy `json:"backoff"`
+	InitialDelayMs int64           `json:"initial_delay_ms"`
+	MaxDelayMs     int64           `json:"max_delay_ms"`
+	DeadLetter     bool            `json:"dead_letter"`
+}
+
+// MarshalJSON encodes delays as integer milliseconds
+func (p RetryPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(retryPolicyJSON{
+		MaxAttempts:    p.MaxAttempts,
+		Backoff:        p.Backoff,
+		InitialDelayMs: p.InitialDelay.Milliseconds(),
+		MaxDelayMs:     p.MaxDelay.Milliseconds(),
+		DeadLetter:     !p.DiscardOnExhaustion,
+	})
+}
+
+// UnmarshalJSON decodes delays from integer milliseconds
+func (p *RetryPolicy) UnmarshalJSON(data []byte) error {
+	wire := retryPolicyJSON{DeadLetter: true}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*p = RetryPolicy{
+		MaxAttempts:         wire.MaxAttempts,
+		Backoff:             wire.Backoff,
+		InitialDelay:        time.Duration(wire.InitialDelayMs) * time.Millisecond,
+		MaxDelay:            time.Duration(wire.MaxDelayMs) * time.Millisecond,
+		DiscardOnExhaustion: !wire.DeadLetter,
+	}
+	return nil
+}
+
+// String describes the policy, e.g. "5 attempts, exponential backoff
+// 1s-5m0s, then dead-letter"
+func (p RetryPolicy) String() string {
+	p = p.withDefaults()
+	exhausted := "dead-letter"
+	if p.DiscardOnExhaustion {
+		exhausted = "discard"
+	}
+	return fmt.Sprintf("%d attempts, %s backoff %s-%s, then %s", p.MaxAttempts, p.Backoff, p.InitialDelay, p.MaxDelay, exhausted)
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{DefaultRetryPolicy, 1, 0},
+		{DefaultRetryPolicy, 2, time.Second},
+		{DefaultRetryPolicy, 4, 4 * time.Second},
+		{DefaultRetryPolicy, 20, DefaultRetryMaxDelay},
+		{RetryPolicy{Backoff: BackoffLinear, InitialDelay: 10 * time.Second}, 4, 30 * time.Second},
+		{RetryPolicy{Backoff: BackoffFixed, InitialDelay: 3 * time.Second}, 9, 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.Delay(tt.attempt); got != tt.want {
+			t.Errorf("%s: expected delay before attempt %d to be %s, got %s", tt.policy, tt.attempt, tt.want, got)
+		}
+	}
+}
+
+func TestRetryPolicyForwarded(t *testing.T) {
+	var received map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if err := client.SetTopicDefaults(TopicPullRequests, TopicDefaults{RetryPolicy: &BestEffortRetryPolicy}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := string(received["retry_policy"]); got != `{"max_attempts":3,"backoff":"fixed","initial_delay_ms":1000,"max_delay_ms":1000,"dead_letter":false}` {
+		t.Errorf("Expected topic default policy, got %s", got)
+	}
+
+	// Unset fields are filled with defaults before sending
+	req.RetryPolicy = &RetryPolicy{MaxAttempts: 10}
+	if _, err := client.PostMessage(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var policy RetryPolicy
+	json.Unmarshal(received["retry_policy"], &policy)
+	want := DefaultRetryPolicy
+	want.MaxAttempts = 10
+	if policy != want {
+		t.Errorf("Expected %+v, got %+v", want, policy)
+	}
+	if req.RetryPolicy.Backoff != "" {
+		t.Error("Expected caller's policy to be left untouched")
+	}
+
+	for _, invalid := range []RetryPolicy{
+		{MaxAttempts: MaxRetryAttempts + 1},
+		{Backoff: "random"},
+		{InitialDelay: time.Minute, MaxDelay: time.Second},
+	} {
+		req.RetryPolicy = &invalid
+		if _, err := client.PostMessage(context.Background(), req); !IsValidationError(err) {
+			t.Errorf("Expected ValidationError for %+v, got %v", invalid, err)
+		}
+	}
+
+	if err := client.SetTopicDefaults("deployments", TopicDefaults{RetryPolicy: &RetryPolicy{MaxAttempts: -1}}); err == nil {
+		t.Error("Expected error for invalid default policy")
+	}
+}
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// temporaryErrorCodes are the service error codes of failures expected to
+// clear without the request changing
+var temporaryErrorCodes = map[string]bool{
+	"rate_limited": true,
+	"queue_full":   true,
+	"overloaded":   true,
+	"unavailable":  true,
+	"timeout":      true,
+}
+
+// Temporary reports whether the failure is expected to clear by itself:
+// rate limiting, a full queue, an overloaded or unreachable backend, or a
+// timeout. Retrying after a delay is likely to succeed
+func (e *APIError) Temporary() bool {
+	if temporaryErrorCodes[e.Code] {
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusInsufficientStorage:
+		return true
+	default:
+		return false
+	}
+}
+
+// Retryable reports whether sending the same request again may succeed:
+// temporary failures and server errors are retryable, while client errors
+// such as validation failures, conflicts, and missing resources are not
+func (e *APIError) Retryable() bool {
+	if e.Temporary() {
+		return true
+	}
+	return e.StatusCode >= 500 &&
+		e.StatusCode != http.StatusNotImplemented && e.StatusCode != http.StatusHTTPVersionNotSupported
+}
+
+// IsRetryable reports whether the operation that returned err may succeed
+// if retried. API errors are classified by APIError.Retryable; network
+// failures, timed-out attempts, and ErrBackpressure are retryable; canceled
+// contexts, client-side validation failures, TLS certificate errors,
+// partially accepted bulk requests, and unknown errors are not
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case IsValidationError(err), IsBulkPartialError(err), errors.As(err, &certErr):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrBackpressure):
+		return true
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED):
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+	// Errors of the SDK's own transports, such as ErrAuditFailed, also come
+	// wrapped in *url.Error, so only socket errors and timeouts count
+	var opErr *net.OpError
+	var netErr net.Error
+	return errors.As(err, &opErr) || (errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorRetryable(t *testing.T) {
+	tests := []struct {
+		err       APIError
+		temporary bool
+		retryable bool
+	}{
+		{APIError{StatusCode: 400}, false, false},
+		{APIError{StatusCode: 404}, false, false},
+		{APIError{StatusCode: 409}, false, false},
+		{APIError{StatusCode: 408}, true, true},
+		{APIError{StatusCode: 429}, true, true},
+		{APIError{StatusCode: 500}, false, true},
+		{APIError{StatusCode: 501}, false, false},
+		{APIError{StatusCode: 502}, true, true},
+		{APIError{StatusCode: 503}, true, true},
+		{APIError{StatusCode: 504}, true, true},
+		{APIError{StatusCode: 507}, true, true},
+		{APIError{StatusCode: 400, Code: "queue_full"}, true, true},
+		{APIError{StatusCode: 500, Code: "overloaded"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := tt.err.Temporary(); got != tt.temporary {
+				t.Errorf("Expected Temporary() %v, got %v", tt.temporary, got)
+			}
+			if got := tt.err.Retryable(); got != tt.retryable {
+				t.Errorf("Expected Retryable() %v, got %v", tt.retryable, got)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil":               {nil, false},
+		"wrapped 503":       {fmt.Errorf("submitting: %w", &APIError{StatusCode: 503}), true},
+		"400":               {&APIError{StatusCode: 400}, false},
+		"validation":        {&ValidationError{Violations: []FieldViolation{{Field: "item_id", Message: "is required"}}}, false},
+		"canceled":          {context.Canceled, false},
+		"deadline exceeded": {fmt.Errorf("request failed: %w", context.DeadlineExceeded), true},
+		"backpressure":      {fmt.Errorf("%w: high queue depth 10", ErrBackpressure), true},
+		"partial bulk":      {&BulkPartialError{Response: &BulkMessageResponse{}}, false},
+		"unknown":           {errors.New("boom"), false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("Expected IsRetryable(%v) to be %v", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableClientErrors(t *testing.T) {
+	// Connection refused
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+	_, err := NewClient(&Config{BaseURL: url}).CheckHealth(context.Background())
+	if err == nil || !IsRetryable(err) {
+		t.Errorf("Expected a refused connection to be retryable, got %v", err)
+	}
+
+	// Timed-out attempt
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	_, err = NewClient(&Config{BaseURL: slow.URL, Timeout: 20 * time.Millisecond}).CheckHealth(context.Background())
+	if err == nil || !IsRetryable(err) {
+		t.Errorf("Expected a timed-out request to be retryable, got %v", err)
+	}
+
+	// Failures of the client's own transports are not network errors
+	sink := AuditSinkFunc(func(ctx context.Context, record AuditRecord) error { return errors.New("log unavailable") })
+	_, err = NewClient(&Config{BaseURL: slow.URL, Audit: &AuditConfig{Sink: sink}}).CheckHealth(context.Background())
+	if !errors.Is(err, ErrAuditFailed) || IsRetryable(err) {
+		t.Errorf("Expected an audit failure not to be retryable, got %v", err)
+	}
+
+	// Service errors
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":"queue_full","message":"queue is full"}}`))
+	}))
+	defer unavailable.Close()
+	_, err = NewClient(&Config{BaseURL: unavailable.URL}).GetWorkerStatus(context.Background())
+	if !IsRetryable(err) {
+		t.Errorf("Expected a full queue to be retryable, got %v", err)
+	}
+}
@@ -0,0 +1,422 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultScaleDriftInterval is how often a ScaleSchedule re-applies the
+// active profile to undo manual or failed scaling
+const DefaultScaleDriftInterval = 5 * time.Minute
+
+// cronLookback bounds how far back a schedule looks for the last firing of
+// an entry
+const cronLookback = 5 * 365 * 24 * time.Hour
+
+// ScalingProfile is a named set of worker counts, keyed by priority ("low",
+// "medium", or "high"). Priorities left out of Workers are not touched; a
+// count of 0 scales that priority to zero
+type ScalingProfile struct {
+	Name    string
+	Workers map[string]int
+}
+
+// ScaleScheduleEntry switches to a profile whenever Cron fires. Cron is a
+// five-field expression (minute, hour, day of month, month, day of week)
+// supporting *, lists, ranges, and steps, or one of @hourly, @daily,
+// @midnight, @weekly, @monthly, and @yearly
+type ScaleScheduleEntry struct {
+	Cron    string
+	Profile string
+}
+
+// ScaleScheduleOptions controls how a ScaleSchedule applies its profiles
+type ScaleScheduleOptions struct {
+	// Location is the time zone the cron expressions are evaluated in;
+	// defaults to time.Local
+	Location *time.Location
+	// DriftInterval is how often the active profile is re-applied between
+	// scheduled changes; defaults to DefaultScaleDriftInterval
+	DriftInterval time.Duration
+	// Logger receives a record of every applied change and failure; defaults
+	// to the client's Logger. Nothing is logged when both are nil
+	Logger *slog.Logger
+}
+
+// withDefaults fills unset schedule options
+func (o ScaleScheduleOptions) withDefaults(client *Client) ScaleScheduleOptions {
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+	if o.DriftInterval <= 0 {
+		o.DriftInterval = DefaultScaleDriftInterval
+	}
+	if o.Logger == nil {
+		o.Logger = client.logger
+	}
+	return o
+}
+
+// ScaleChange is a worker count changed by a ScaleSchedule
+type ScaleChange struct {
+	Profile  string
+	Priority string
+	From     int
+	To       int
+}
+
+// scheduledProfile is an entry with its parsed expression
+type scheduledProfile struct {
+	cron    *cronSpec
+	profile *ScalingProfile
+}
+
+// ScaleSchedule scales workers to the profile of the most recently fired
+// schedule entry, e.g. low-priority workers to zero overnight and back up at
+// 8am. Once started it checks the schedule every minute in the background
+// and re-applies the active profile every DriftInterval, so counts changed
+// by hand or by a failed scale request converge back to the profile
+type ScaleSchedule struct {
+	client  *Client
+	opts    ScaleScheduleOptions
+	entries []scheduledProfile
+	now     func() time.Time
+
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	stopped    chan struct{}
+	unregister func()
+}
+
+// NewScaleSchedule creates a schedule that scales through client. Every
+// entry must name one of profiles; when two entries last fired at the same
+// minute, the later entry wins
+func NewScaleSchedule(client *Client, profiles []ScalingProfile, entries []ScaleScheduleEntry, opts ScaleScheduleOptions) (*ScaleSchedule, error) {
+	byName := make(map[string]*ScalingProfile, len(profiles))
+	for i := range profiles {
+		profile := &profiles[i]
+		if profile.Name == "" {
+			return nil, fmt.Errorf("profiles[%d]: name is required", i)
+		}
+		if _, ok := byName[profile.Name]; ok {
+			return nil, fmt.Errorf("duplicate profile '%s'", profile.Name)
+		}
+		for priority, count := range profile.Workers {
+			if err := validateWorkerPriority(priority); err != nil {
+				return nil, fmt.Errorf("profile '%s': %w", profile.Name, err)
+			}
+			if count < 0 {
+				return nil, fmt.Errorf("profile '%s': %s worker count cannot be negative", profile.Name, priority)
+			}
+		}
+		byName[profile.Name] = profile
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("at least one schedule entry is required")
+	}
+	scheduled := make([]scheduledProfile, len(entries))
+	for i, entry := range entries {
+		spec, err := parseCron(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("entries[%d]: %w", i, err)
+		}
+		profile, ok := byName[entry.Profile]
+		if !ok {
+			return nil, fmt.Errorf("entries[%d]: unknown profile '%s'", i, entry.Profile)
+		}
+		scheduled[i] = scheduledProfile{cron: spec, profile: profile}
+	}
+
+	return &ScaleSchedule{
+		client:  client,
+		opts:    opts.withDefaults(client),
+		entries: scheduled,
+		now:     client.clock.Now,
+	}, nil
+}
+
+// ActiveProfile returns the profile in effect at t, or nil if no entry has
+// fired yet
+func (s *ScaleSchedule) ActiveProfile(t time.Time) *ScalingProfile {
+	var active *ScalingProfile
+	var activeAt time.Time
+	for _, entry := range s.entries {
+		fired, ok := entry.cron.prev(t.In(s.opts.Location))
+		if ok && !fired.Before(activeAt) {
+			active, activeAt = entry.profile, fired
+		}
+	}
+	return active
+}
+
+// Apply scales workers to the currently active profile once and returns
+// the changes made
+func (s *ScaleSchedule) Apply(ctx context.Context) ([]ScaleChange, error) {
+	profile := s.ActiveProfile(s.now())
+	if profile == nil {
+		return nil, nil
+	}
+	return s.apply(ctx, profile, "manual")
+}
+
+// Start applies the active profile and keeps it applied in a background
+// goroutine until ctx is done or Stop is called
+func (s *ScaleSchedule) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("scale schedule already started")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.stopped = make(chan struct{})
+	s.unregister = s.client.RegisterShutdownHook(ShutdownStopIntake, ShutdownHookFunc(func(context.Context) error {
+		s.Stop()
+		return nil
+	}))
+	go func() {
+		defer close(s.stopped)
+		s.run(ctx)
+	}()
+	return nil
+}
+
+// Stop ends the background goroutine and waits for it to return. A scale
+// request in flight is canceled
+func (s *ScaleSchedule) Stop() {
+	s.mu.Lock()
+	cancel, stopped, unregister := s.cancel, s.stopped, s.unregister
+	s.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	unregister()
+	cancel()
+	<-stopped
+}
+
+// run applies the active profile when it changes or has not been verified
+// for DriftInterval, checking at the start of every minute
+func (s *ScaleSchedule) run(ctx context.Context) {
+	var applied string
+	var verified time.Time
+	for {
+		now := s.now()
+		if profile := s.ActiveProfile(now); profile != nil {
+			reason := ""
+			switch {
+			case profile.Name != applied:
+				reason = "schedule"
+			case now.Sub(verified) >= s.opts.DriftInterval:
+				reason = "drift"
+			}
+			if reason != "" {
+				if _, err := s.apply(ctx, profile, reason); err == nil {
+					applied, verified = profile.Name, now
+				}
+			}
+		}
+
+		if sleep(ctx, s.client.clock, now.Truncate(time.Minute).Add(time.Minute).Sub(now)) != nil {
+			return
+		}
+	}
+}
+
+// apply scales every priority in profile that differs from its target
+func (s *ScaleSchedule) apply(ctx context.Context, profile *ScalingProfile, reason string) ([]ScaleChange, error) {
+	status, err := s.client.GetWorkerStatus(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to get worker status: %w", err)
+		s.log(ctx, slog.LevelWarn, "failed to apply scaling profile", slog.String("profile", profile.Name), slog.Any("error", err))
+		return nil, err
+	}
+
+	priorities := make([]string, 0, len(profile.Workers))
+	for priority := range profile.Workers {
+		priorities = append(priorities, priority)
+	}
+	sort.Strings(priorities)
+
+	var changes []ScaleChange
+	var errs []error
+	current := status.priorities()
+	for _, priority := range priorities {
+		from, to := current[priority].Count, profile.Workers[priority]
+		if from == to {
+			continue
+		}
+		if _, err := s.client.ScaleWorkers(ctx, priority, to-from); err != nil {
+			err = fmt.Errorf("failed to scale %s workers from %d to %d: %w", priority, from, to, err)
+			s.log(ctx, slog.LevelWarn, "failed to apply scaling profile", slog.String("profile", profile.Name), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		changes = append(changes, ScaleChange{Profile: profile.Name, Priority: priority, From: from, To: to})
+		s.log(ctx, slog.LevelInfo, "applied scaling profile",
+			slog.String("profile", profile.Name),
+			slog.String("priority", priority),
+			slog.Int("from", from),
+			slog.Int("to", to),
+			slog.String("reason", reason))
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// log writes a record to the schedule's logger, if any
+func (s *ScaleSchedule) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.opts.Logger != nil {
+		s.opts.Logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+// cronSpec is a parsed five-field cron expression, one bit per allowed value
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record unrestricted day fields; when both day fields
+	// are restricted a day matches either of them, as in cron
+	domAny, dowAny bool
+}
+
+// cronDescriptors are the supported @ shorthands
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron parses a five-field cron expression or descriptor
+func parseCron(expr string) (*cronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := cronDescriptors[expr]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor '%s'", expr)
+		}
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression '%s' must have 5 fields, got %d", expr, len(fields))
+	}
+
+	var spec cronSpec
+	var err error
+	if spec.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("cron minute: %w", err)
+	}
+	if spec.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("cron hour: %w", err)
+	}
+	if spec.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("cron day of month: %w", err)
+	}
+	if spec.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("cron month: %w", err)
+	}
+	if spec.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("cron day of week: %w", err)
+	}
+	// 7 is another name for Sunday
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domAny = fields[2] == "*"
+	spec.dowAny = fields[4] == "*"
+	return &spec, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges, and steps
+// within [min, max]
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", rangePart)
+			}
+			lo, hi = value, value
+			if strings.Contains(part, "/") {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("'%s' is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// matchesDay reports whether the day of t is allowed
+func (s *cronSpec) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// prev returns the latest minute at or before t at which the expression
+// fires, looking back at most cronLookback
+func (s *cronSpec) prev(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	limit := t.Add(-cronLookback)
+
+	for t.After(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Minute)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,187 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	loc := time.UTC
+	at := func(s string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		expr string
+		now  string
+		want string
+	}{
+		{"0 8 * * *", "2024-03-05 07:59", "2024-03-04 08:00"},
+		{"0 8 * * *", "2024-03-05 08:00", "2024-03-05 08:00"},
+		{"*/15 * * * *", "2024-03-05 10:44", "2024-03-05 10:30"},
+		{"0 22 * * 1-5", "2024-03-03 12:00", "2024-03-01 22:00"}, // Sunday -> Friday
+		{"0 0 1 * *", "2024-03-05 12:00", "2024-03-01 00:00"},
+		{"0 9 * * 7", "2024-03-05 12:00", "2024-03-03 09:00"},
+		{"@hourly", "2024-03-05 12:30", "2024-03-05 12:00"},
+		{"30 6 1,15 2 *", "2024-03-05 12:00", "2024-02-15 06:30"},
+	}
+
+	for _, tt := range tests {
+		spec, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		got, ok := spec.prev(at(tt.now))
+		if !ok || !got.Equal(at(tt.want)) {
+			t.Errorf("prev(%q, %s) = %v, %v; want %s", tt.expr, tt.now, got, ok, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "@often"} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("Expected error for %q", expr)
+		}
+	}
+}
+
+// scaleServer is a worker service whose counts can be changed by scale requests
+type scaleServer struct {
+	mu     sync.Mutex
+	counts map[string]int
+	scales []string
+}
+
+func (s *scaleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/api/v1/workers/scale/") {
+		priority := strings.TrimPrefix(r.URL.Path, "/api/v1/workers/scale/")
+		delta, _ := strconv.Atoi(r.URL.Query().Get("count"))
+		s.counts[priority] += delta
+		s.scales = append(s.scales, priority+":"+r.URL.Query().Get("count"))
+		w.Write([]byte(`{"status":"success"}`))
+		return
+	}
+
+	json.NewEncoder(w).Encode(WorkerStatusResponse{
+		LowPriority:    PriorityWorkerInfo{Count: s.counts["low"]},
+		MediumPriority: PriorityWorkerInfo{Count: s.counts["medium"]},
+		HighPriority:   PriorityWorkerInfo{Count: s.counts["high"]},
+	})
+}
+
+func TestScaleSchedule(t *testing.T) {
+	service := &scaleServer{counts: map[string]int{"low": 3, "medium": 2, "high": 4}}
+	server := httptest.NewServer(service)
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	schedule, err := NewScaleSchedule(client,
+		[]ScalingProfile{
+			{Name: "day", Workers: map[string]int{"low": 3, "medium": 2}},
+			{Name: "night", Workers: map[string]int{"low": 0, "medium": 1}},
+		},
+		[]ScaleScheduleEntry{
+			{Cron: "0 8 * * *", Profile: "day"},
+			{Cron: "0 22 * * *", Profile: "night"},
+		},
+		ScaleScheduleOptions{Location: time.UTC},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	now := time.Date(2024, 3, 5, 23, 0, 0, 0, time.UTC)
+	schedule.now = func() time.Time { return now }
+
+	if profile := schedule.ActiveProfile(now); profile == nil || profile.Name != "night" {
+		t.Fatalf("Expected night profile at 23:00, got %+v", profile)
+	}
+
+	changes, err := schedule.Apply(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []ScaleChange{
+		{Profile: "night", Priority: "low", From: 3, To: 0},
+		{Profile: "night", Priority: "medium", From: 2, To: 1},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected change %+v, got %+v", expected[i], changes[i])
+		}
+	}
+	if service.counts["high"] != 4 {
+		t.Errorf("Expected high priority to be left alone, got %d", service.counts["high"])
+	}
+
+	// Applying again is a no-op
+	changes, err = schedule.Apply(context.Background())
+	if err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v, %v", changes, err)
+	}
+
+	// Drift is corrected by the background loop
+	service.mu.Lock()
+	service.counts["low"] = 2
+	service.mu.Unlock()
+	if err := schedule.Start(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := schedule.Start(context.Background()); err == nil {
+		t.Error("Expected error starting twice")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		service.mu.Lock()
+		low := service.counts["low"]
+		service.mu.Unlock()
+		if low == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected low workers to be scaled back to 0, got %d", low)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	schedule.Stop()
+}
+
+func TestNewScaleScheduleValidation(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://example.com"})
+	profiles := []ScalingProfile{{Name: "night", Workers: map[string]int{"low": 0}}}
+
+	tests := []struct {
+		name     string
+		profiles []ScalingProfile
+		entries  []ScaleScheduleEntry
+	}{
+		{"no entries", profiles, nil},
+		{"unknown profile", profiles, []ScaleScheduleEntry{{Cron: "@daily", Profile: "day"}}},
+		{"bad cron", profiles, []ScaleScheduleEntry{{Cron: "0 25 * * *", Profile: "night"}}},
+		{"bad priority", []ScalingProfile{{Name: "night", Workers: map[string]int{"urgent": 1}}}, []ScaleScheduleEntry{{Cron: "@daily", Profile: "night"}}},
+		{"negative count", []ScalingProfile{{Name: "night", Workers: map[string]int{"low": -1}}}, []ScaleScheduleEntry{{Cron: "@daily", Profile: "night"}}},
+		{"duplicate profile", append(profiles, profiles[0]), []ScaleScheduleEntry{{Cron: "@daily", Profile: "night"}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewScaleSchedule(client, tt.profiles, tt.entries, ScaleScheduleOptions{}); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
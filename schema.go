@@ -0,0 +1,217 @@
+package sdk
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DecodingMode controls how responses that do not match the SDK types are
+// handled
+type DecodingMode string
+
+// Decoding modes
+const (
+	// DecodeLenient ignores unknown fields and tolerates missing ones, logging
+	// a warning through Config.Logger when one is set. It is the default
+	DecodeLenient DecodingMode = "lenient"
+	// DecodeStrict fails with a *SchemaError when a response has fields the
+	// SDK does not know or lacks fields it expects, e.g. to catch contract
+	// drift in CI
+	DecodeStrict DecodingMode = "strict"
+)
+
+// SchemaError reports a response that does not match the SDK type it is
+// decoded into. Fields are reported as JSON paths, e.g. "workers[0].status"
+type SchemaError struct {
+	Path string
+	// Unknown lists fields in the response that the SDK type does not have
+	Unknown []string
+	// Missing lists fields the SDK type expects, i.e. those not tagged
+	// omitempty or omitzero, that are absent from the response
+	Missing []string
+}
+
+func (e *SchemaError) Error() string {
+	var parts []string
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "unknown fields "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing fields "+strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("response from %s does not match schema: %s", e.Path, strings.Join(parts, "; "))
+}
+
+// checkResponseSchema compares body with the type of target. In strict mode
+// a mismatch is returned as a *SchemaError; in lenient mode it is logged
+func (c *Client) checkResponseSchema(resp *http.Response, body []byte, target interface{}) error {
+	if c.decoding != DecodeStrict && c.logger == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Left to the decoder to report
+		return nil
+	}
+
+	schemaErr := &SchemaError{}
+	if resp.Request != nil {
+		schemaErr.Path = resp.Request.URL.Path
+	}
+	compareSchema(value, reflect.TypeOf(target), "", schemaErr)
+	if len(schemaErr.Unknown) == 0 && len(schemaErr.Missing) == 0 {
+		return nil
+	}
+	sort.Strings(schemaErr.Unknown)
+	sort.Strings(schemaErr.Missing)
+
+	if c.decoding == DecodeStrict {
+		return schemaErr
+	}
+	c.logger.Warn("response does not match schema",
+		"path", schemaErr.Path, "unknown", schemaErr.Unknown, "missing", schemaErr.Missing)
+	return nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// compareSchema records the fields of value that t lacks and the expected
+// fields of t that value lacks. Types with custom decoding are not inspected
+func compareSchema(value interface{}, t reflect.Type, path string, schemaErr *SchemaError) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := schemaFields(t)
+		for name, fieldValue := range object {
+			field, ok := lookupSchemaField(fields, name)
+			if !ok {
+				schemaErr.Unknown = append(schemaErr.Unknown, joinSchemaPath(path, name))
+				continue
+			}
+			compareSchema(fieldValue, field.typ, joinSchemaPath(path, name), schemaErr)
+		}
+		for _, field := range fields {
+			if field.optional {
+				continue
+			}
+			if _, ok := lookupObjectKey(object, field.name); !ok {
+				schemaErr.Missing = append(schemaErr.Missing, joinSchemaPath(path, field.name))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			compareSchema(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), schemaErr)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, item := range object {
+			compareSchema(item, t.Elem(), joinSchemaPath(path, key), schemaErr)
+		}
+	}
+}
+
+// schemaField is a JSON field of a struct type
+type schemaField struct {
+	name     string
+	typ      reflect.Type
+	optional bool
+}
+
+// schemaFields returns the JSON fields of struct type t, with the fields of
+// untagged embedded structs promoted
+func schemaFields(t reflect.Type) []schemaField {
+	var fields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, schemaFields(embedded)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		optional := strings.Contains(","+opts+",", ",omitempty,") || strings.Contains(","+opts+",", ",omitzero,")
+		fields = append(fields, schemaField{name: name, typ: f.Type, optional: optional})
+	}
+	return fields
+}
+
+// lookupSchemaField finds the field for a JSON key, matching case
+// insensitively like encoding/json
+func lookupSchemaField(fields []schemaField, key string) (schemaField, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return schemaField{}, false
+}
+
+// lookupObjectKey finds a field name in a JSON object, matching case
+// insensitively like encoding/json
+func lookupObjectKey(object map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := object[name]; ok {
+		return v, true
+	}
+	for k, v := range object {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// joinSchemaPath appends a field name to a JSON path
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
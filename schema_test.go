@@ -0,0 +1,89 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodingModes(t *testing.T) {
+	body := `{"id":"msg-1","item_id":"pr-1","priority":"high","topic":"pullrequests","status":"queued","attempts":1,
+		"callback_url":"https://example.com/callback","created_at":"2024-01-01T00:00:00Z",
+		"shard":"s-3","metadata":{"tenant":"acme"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	// Strict mode reports the unknown field and the missing updated_at
+	strict := NewClient(&Config{BaseURL: server.URL, Decoding: DecodeStrict})
+	_, err := strict.GetMessage(ctx, "msg-1")
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected schema error, got %v", err)
+	}
+	if len(schemaErr.Unknown) != 1 || schemaErr.Unknown[0] != "shard" {
+		t.Errorf("Expected unknown 'shard', got %v", schemaErr.Unknown)
+	}
+	if len(schemaErr.Missing) != 1 || schemaErr.Missing[0] != "updated_at" {
+		t.Errorf("Expected missing 'updated_at', got %v", schemaErr.Missing)
+	}
+	if schemaErr.Path != "/api/v1/messages/msg-1" {
+		t.Errorf("Expected request path, got '%s'", schemaErr.Path)
+	}
+
+	// Lenient mode decodes and warns through the logger
+	var logs bytes.Buffer
+	lenient := NewClient(&Config{BaseURL: server.URL, Logger: slog.New(slog.NewTextHandler(&logs, nil))})
+	detail, err := lenient.GetMessage(ctx, "msg-1")
+	if err != nil || detail.ItemID != "pr-1" {
+		t.Fatalf("Expected lenient decoding, got %+v, %v", detail, err)
+	}
+	if out := logs.String(); !strings.Contains(out, "level=WARN") || !strings.Contains(out, "shard") || !strings.Contains(out, "updated_at") {
+		t.Errorf("Expected schema warning, got '%s'", out)
+	}
+
+	if err := (&Config{Decoding: "loose"}).Validate(); err == nil {
+		t.Error("Expected error for unknown decoding mode")
+	}
+}
+
+func TestCompareSchemaNested(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Count int    `json:"count,omitempty"`
+	}
+	type embedded struct {
+		Version string `json:"version"`
+	}
+	type response struct {
+		embedded
+		Items  []item          `json:"items"`
+		Extras map[string]item `json:"extras,omitempty"`
+		Raw    []byte          `json:"raw,omitempty"`
+	}
+
+	var value interface{}
+	jsonData := `{"version":"1","items":[{"name":"a"},{"Name":"b","size":2}],"extras":{"x":{"count":1}}}`
+	if err := json.Unmarshal([]byte(jsonData), &value); err != nil {
+		t.Fatal(err)
+	}
+
+	schemaErr := &SchemaError{}
+	compareSchema(value, reflect.TypeOf(&response{}), "", schemaErr)
+	if len(schemaErr.Unknown) != 1 || schemaErr.Unknown[0] != "items[1].size" {
+		t.Errorf("Unexpected unknown fields: %v", schemaErr.Unknown)
+	}
+	if len(schemaErr.Missing) != 1 || schemaErr.Missing[0] != "extras.x.name" {
+		t.Errorf("Unexpected missing fields: %v", schemaErr.Missing)
+	}
+}
@@ -0,0 +1,191 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ItemError is the failure of a single submission in a Scope
+type ItemError struct {
+	// Index is the order in which the message was submitted to the scope
+	Index  int
+	ItemID string
+	Err    error
+}
+
+func (e ItemError) Error() string {
+	return fmt.Sprintf("message %d (%s): %v", e.Index, e.ItemID, e.Err)
+}
+
+func (e ItemError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates the per-item failures of a Scope
+type MultiError struct {
+	Errors []ItemError
+	// Fatal is the error that canceled the scope, if any
+	Fatal error
+}
+
+func (e *MultiError) Error() string {
+	parts := make([]string, 0, len(e.Errors)+1)
+	if e.Fatal != nil {
+		parts = append(parts, fmt.Sprintf("canceled: %v", e.Fatal))
+	}
+	for _, item := range e.Errors {
+		parts = append(parts, item.Error())
+	}
+	return fmt.Sprintf("%d submissions failed: %s", len(e.Errors), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the fatal error and every item error
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	if e.Fatal != nil {
+		errs = append(errs, e.Fatal)
+	}
+	for _, item := range e.Errors {
+		errs = append(errs, item)
+	}
+	return errs
+}
+
+// IsFatalError is the default classification used by Scope: validation
+// failures and client errors concern a single message, while authorization
+// failures, server errors, network errors, and a closed client affect every
+// submission and cancel the scope
+func IsFatalError(err error) bool {
+	if IsValidationError(err) || IsBulkPartialError(err) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return true
+		}
+		return apiErr.StatusCode >= 500
+	}
+
+	return true
+}
+
+// Scope runs message submissions concurrently with bounded parallelism and
+// collects their failures; it is meant to replace ad-hoc fan-out code in
+// batch jobs
+type Scope struct {
+	client *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// IsFatal decides which errors cancel the scope; it defaults to
+	// IsFatalError and must be set before the first Submit
+	IsFatal func(error) bool
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu    sync.Mutex
+	next  int
+	errs  []ItemError
+	fatal error
+}
+
+// Go starts a submission scope bound to ctx. Concurrency defaults to the
+// client's AsyncWorkers; change it with SetLimit before submitting
+func Go(ctx context.Context, client *Client) *Scope {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Scope{
+		client:  client,
+		ctx:     ctx,
+		cancel:  cancel,
+		IsFatal: IsFatalError,
+		sem:     make(chan struct{}, client.asyncWorkers),
+	}
+}
+
+// SetLimit bounds the number of submissions in flight; it must be called
+// before the first Submit
+func (s *Scope) SetLimit(n int) {
+	if n <= 0 {
+		n = 1
+	}
+	s.sem = make(chan struct{}, n)
+}
+
+// Context returns the scope's context, which is canceled on the first fatal
+// error or once Wait returns
+func (s *Scope) Context() context.Context {
+	return s.ctx
+}
+
+// Submit posts req in the background, blocking while the concurrency limit
+// is reached. It returns the context's error if the scope was canceled
+func (s *Scope) Submit(req *MessageRequest) error {
+	if req == nil {
+		return fmt.Errorf("message request cannot be nil")
+	}
+
+	select {
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	case s.sem <- struct{}{}:
+	}
+
+	s.mu.Lock()
+	index := s.next
+	s.next++
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			<-s.sem
+			s.wg.Done()
+		}()
+
+		if _, err := s.client.PostMessage(s.ctx, req); err != nil {
+			s.fail(index, req.ItemID, err)
+		}
+	}()
+
+	return nil
+}
+
+// fail records a submission error and cancels the scope if it is fatal
+func (s *Scope) fail(index int, itemID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Submissions aborted by an earlier fatal error are not failures of their own
+	if s.fatal != nil && errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.errs = append(s.errs, ItemError{Index: index, ItemID: itemID, Err: err})
+	if s.fatal == nil && s.IsFatal(err) {
+		s.fatal = err
+		s.cancel()
+	}
+}
+
+// Wait blocks until every submission finished and returns a *MultiError
+// when any failed
+func (s *Scope) Wait() error {
+	s.wg.Wait()
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.errs) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: append([]ItemError(nil), s.errs...), Fatal: s.fatal}
+}
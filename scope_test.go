@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScopeBoundsConcurrencyAndCollectsErrors(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(`{"id":"msg-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	scope := Go(context.Background(), client)
+	scope.SetLimit(3)
+
+	for i := 0; i < 12; i++ {
+		req := newMessageRequest(fmt.Sprintf("pr-%d", i), PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+		if i == 4 {
+			req.Priority = "urgent"
+		}
+		if err := scope.Submit(req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	err := scope.Wait()
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("Expected MultiError, got %v", err)
+	}
+	if len(multi.Errors) != 1 || multi.Errors[0].Index != 4 || multi.Fatal != nil {
+		t.Errorf("Unexpected errors: %+v", multi)
+	}
+	if !IsValidationError(multi.Errors[0].Err) {
+		t.Errorf("Expected validation error, got %v", multi.Errors[0].Err)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("Expected at most 3 concurrent submissions, got %d", maxInFlight)
+	}
+}
+
+func TestScopeCancelsOnFatalError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	scope := Go(context.Background(), NewClient(&Config{BaseURL: server.URL}))
+	scope.SetLimit(1)
+
+	var submitErr error
+	for i := 0; i < 50 && submitErr == nil; i++ {
+		submitErr = scope.Submit(newMessageRequest(fmt.Sprintf("pr-%d", i), PriorityLow, TopicPullRequests, "https://example.com/callback", nil))
+	}
+	if !errors.Is(submitErr, context.Canceled) {
+		t.Errorf("Expected Submit to fail after a fatal error, got %v", submitErr)
+	}
+
+	var multi *MultiError
+	if err := scope.Wait(); !errors.As(err, &multi) || multi.Fatal == nil {
+		t.Fatalf("Expected fatal MultiError, got %v", err)
+	}
+	if apiErr, ok := multi.Fatal.(*APIError); !ok || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 fatal error, got %v", multi.Fatal)
+	}
+}
@@ -0,0 +1,211 @@
+// Package sdktest provides test doubles for code built on the SDK
+package sdktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+// FakeClock is an sdk.Clock whose time only moves when Advance or Set is
+// called, so that retries, batching, polling, and schedules can be tested
+// without waiting. Timers and tickers fire during Advance, in order of their
+// deadlines
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan struct{}
+}
+
+var _ sdk.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a fake clock set to now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the clock's current time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the time elapsed on the clock since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer returns a timer firing once the clock has advanced by d
+func (c *FakeClock) NewTimer(d time.Duration) sdk.Timer {
+	w := &fakeWaiter{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	c.schedule(w, d)
+	c.mu.Unlock()
+	return &fakeTimer{w}
+}
+
+// NewTicker returns a ticker firing every d of clock time. Like a
+// *time.Ticker, it drops ticks the receiver is not ready for
+func (c *FakeClock) NewTicker(d time.Duration) sdk.Ticker {
+	if d <= 0 {
+		panic("sdktest: non-positive interval for NewTicker")
+	}
+	w := &fakeWaiter{clock: c, c: make(chan time.Time, 1), period: d}
+	c.mu.Lock()
+	c.schedule(w, d)
+	c.mu.Unlock()
+	return &fakeTicker{w}
+}
+
+// Advance moves the clock forward by d, firing the timers and tickers that
+// come due on the way
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+	c.Set(target)
+}
+
+// Set moves the clock to t, firing the timers and tickers that come due on
+// the way. Setting a time before the current one only changes Now
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fire(t)
+	c.now = t
+	c.notify()
+}
+
+// fire delivers the timers and tickers due by t, moving the clock to each
+// deadline in turn; c.mu must be held
+func (c *FakeClock) fire(t time.Time) {
+	for len(c.waiters) > 0 && !c.waiters[0].at.After(t) {
+		w := c.waiters[0]
+		c.waiters = c.waiters[1:]
+		if w.at.After(c.now) {
+			c.now = w.at
+		}
+		select {
+		case w.c <- c.now:
+		default:
+		}
+		if w.period > 0 {
+			c.schedule(w, w.period)
+		}
+	}
+}
+
+// Waiters returns the number of timers and tickers waiting to fire
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are waiting to fire,
+// e.g. until the code under test has started its backoff, so that the next
+// Advance is guaranteed to wake it
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			c.mu.Unlock()
+			return
+		}
+		changed := c.changed
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// schedule adds w to fire d from now; c.mu must be held
+func (c *FakeClock) schedule(w *fakeWaiter, d time.Duration) {
+	w.at = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+	if d <= 0 {
+		// Fire immediately, like a real timer with a non-positive duration
+		c.fire(c.now)
+	}
+	c.notify()
+}
+
+// remove takes w off the waiting list, reporting whether it was there;
+// c.mu must be held
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.notify()
+			return true
+		}
+	}
+	return false
+}
+
+// notify wakes BlockUntil callers; c.mu must be held
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// fakeWaiter is a timer or, when period is set, a ticker of a FakeClock
+type fakeWaiter struct {
+	clock  *FakeClock
+	c      chan time.Time
+	at     time.Time
+	period time.Duration
+}
+
+// stop removes w from its clock and drops any undelivered tick, like timers
+// since Go 1.23
+func (w *fakeWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	select {
+	case <-w.c:
+	default:
+	}
+	return w.clock.remove(w)
+}
+
+// reset reschedules w to fire d from now
+func (w *fakeWaiter) reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	select {
+	case <-w.c:
+	default:
+	}
+	active := w.clock.remove(w)
+	if w.period > 0 {
+		w.period = d
+	}
+	w.clock.schedule(w, d)
+	return active
+}
+
+type fakeTimer struct{ w *fakeWaiter }
+
+func (t *fakeTimer) C() <-chan time.Time { return t.w.c }
+
+func (t *fakeTimer) Stop() bool { return t.w.stop() }
+
+func (t *fakeTimer) Reset(d time.Duration) bool { return t.w.reset(d) }
+
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t *fakeTicker) C() <-chan time.Time { return t.w.c }
+
+func (t *fakeTicker) Stop() { t.w.stop() }
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("sdktest: non-positive interval for Ticker.Reset")
+	}
+	t.w.reset(d)
+}
@@ -0,0 +1,177 @@
+package sdktest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/ericbrisrubio/messages-worker-sdk"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClockTimer(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("Expected the timer not to fire before its deadline")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case fired := <-timer.C():
+		if !fired.Equal(epoch.Add(time.Minute)) {
+			t.Errorf("Expected the timer to fire at its deadline, got %v", fired)
+		}
+	default:
+		t.Fatal("Expected the timer to fire")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Expected Reset of a fired timer to report it inactive")
+	}
+	if !timer.Stop() {
+		t.Error("Expected Stop of a pending timer to report it active")
+	}
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Error("Expected a stopped timer not to fire")
+	default:
+	}
+	if clock.Waiters() != 0 {
+		t.Errorf("Expected no waiters, got %d", clock.Waiters())
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	ticker := clock.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for i := 1; i <= 3; i++ {
+		clock.Advance(10 * time.Second)
+		select {
+		case tick := <-ticker.C():
+			if want := epoch.Add(time.Duration(i) * 10 * time.Second); !tick.Equal(want) {
+				t.Errorf("Expected tick %d at %v, got %v", i, want, tick)
+			}
+		default:
+			t.Fatalf("Expected tick %d", i)
+		}
+	}
+
+	// Ticks the receiver misses are dropped
+	clock.Advance(time.Minute)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Error("Expected missed ticks to be dropped")
+	default:
+	}
+	if !clock.Now().Equal(epoch.Add(90 * time.Second)) {
+		t.Errorf("Unexpected time %v", clock.Now())
+	}
+}
+
+func TestFakeClockDrivesRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	clock := NewFakeClock(epoch)
+	client := sdk.NewClient(&sdk.Config{BaseURL: server.URL, Clock: clock})
+	policy := sdk.RetryPolicy{MaxAttempts: 3, Backoff: sdk.BackoffFixed, InitialDelay: time.Hour}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Call(context.Background(), http.MethodGet, "/api/v1/preview", nil, nil, sdk.WithRetry(policy))
+	}()
+
+	// Each hour-long backoff passes as soon as the clock is advanced
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected the call to succeed after retries, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the fake clock to end the backoff")
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestFakeClockDrivesBatching(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","count":1,"messages":[{"item_id":"pr-1","status":"queued"}]}`))
+	}))
+	defer server.Close()
+
+	clock := NewFakeClock(epoch)
+	client := sdk.NewClient(&sdk.Config{BaseURL: server.URL, Clock: clock})
+	producer := sdk.NewProducer(client, sdk.ProducerConfig{BatchSize: 10, BatchDelay: time.Hour})
+	defer producer.Close()
+
+	future := producer.Send(context.Background(), &sdk.MessageRequest{
+		ItemID: "pr-1", Priority: sdk.PriorityLow, Topic: sdk.TopicPullRequests, CallbackURL: "https://example.com/callback",
+	})
+
+	// The partial batch waits for BatchDelay, which passes on Advance
+	clock.BlockUntil(1)
+	select {
+	case <-future.Done():
+		t.Fatal("Expected the batch to wait for its delay")
+	default:
+	}
+	clock.Advance(time.Hour)
+
+	select {
+	case <-future.Done():
+		if _, err := future.Result(); err != nil {
+			t.Errorf("Expected the message to be sent, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the fake clock to dispatch the batch")
+	}
+}
+
+func TestFakeClockDrivesResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", epoch.Add(30*time.Second).Format(http.TimeFormat))
+		w.Write([]byte(`{"id":"msg-1","item_id":"pr-1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	client := sdk.NewClient(&sdk.Config{BaseURL: server.URL, Clock: NewFakeClock(epoch)})
+	ctx := sdk.WithResponseMeta(context.Background())
+	if _, err := client.PostMessage(ctx, &sdk.MessageRequest{
+		ItemID: "pr-1", Priority: sdk.PriorityLow, Topic: sdk.TopicPullRequests, CallbackURL: "https://example.com/callback",
+	}); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	// A Retry-After date is measured from the fake clock, not the wall clock
+	meta := sdk.ResponseMetaFromContext(ctx)
+	if meta == nil || meta.RetryAfter != 30*time.Second {
+		t.Errorf("Expected a 30s Retry-After, got %+v", meta)
+	}
+}
@@ -0,0 +1,172 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Outcomes of a self-check
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// Names of the checks run by SelfCheck
+const (
+	CheckConnectivity = "connectivity"
+	CheckCredentials  = "credentials"
+	CheckAPIVersion   = "api_version"
+)
+
+// CheckResult is the outcome of a single self-check
+type CheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Hint suggests how to fix a failed check
+	Hint string `json:"hint,omitempty"`
+}
+
+// SelfCheckReport lists the outcome of every self-check, in the order run
+type SelfCheckReport struct {
+	Checks []CheckResult `json:"checks"`
+}
+
+// OK reports whether no check failed
+func (r *SelfCheckReport) OK() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the checks that failed
+func (r *SelfCheckReport) Failed() []CheckResult {
+	var failed []CheckResult
+	for _, check := range r.Checks {
+		if check.Status == CheckFailed {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// SelfCheck verifies that the client can work against its service: that the
+// base URL is reachable, that the service accepts the client's credentials,
+// and that it supports the configured API version. Checks that depend on a
+// failed one are skipped. Failures are reported in the report, not as errors
+func (c *Client) SelfCheck(ctx context.Context) *SelfCheckReport {
+	report := &SelfCheckReport{}
+
+	connectivity := c.checkConnectivity(ctx)
+	report.Checks = append(report.Checks, connectivity)
+	if connectivity.Status != CheckPassed {
+		report.Checks = append(report.Checks,
+			CheckResult{Name: CheckCredentials, Status: CheckSkipped, Detail: "service unreachable"},
+			CheckResult{Name: CheckAPIVersion, Status: CheckSkipped, Detail: "service unreachable"},
+		)
+		return report
+	}
+
+	report.Checks = append(report.Checks, c.checkCredentials(ctx), c.checkAPIVersion(ctx))
+	return report
+}
+
+// checkConnectivity queries the health endpoint
+func (c *Client) checkConnectivity(ctx context.Context) CheckResult {
+	result := CheckResult{Name: CheckConnectivity}
+
+	_, err := c.CheckHealth(ctx)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		result.Status = CheckPassed
+		result.Detail = fmt.Sprintf("%s is healthy", c.baseURL)
+	case errors.As(err, &apiErr):
+		result.Status = CheckFailed
+		result.Detail = fmt.Sprintf("%s answered the health check with %d", c.baseURL, apiErr.StatusCode)
+		result.Hint = "the service is reachable but unhealthy; check its readiness with CheckReadiness"
+		if apiErr.StatusCode == http.StatusNotFound {
+			result.Hint = "check that BaseURL points at the messages-worker service and not a path below it"
+		}
+	default:
+		result.Status = CheckFailed
+		result.Detail = fmt.Sprintf("cannot reach %s: %v", c.baseURL, err)
+		result.Hint = "check BaseURL, DNS, and firewall rules between this host and the service"
+	}
+	return result
+}
+
+// checkCredentials makes an authenticated read-only request
+func (c *Client) checkCredentials(ctx context.Context) CheckResult {
+	result := CheckResult{Name: CheckCredentials}
+
+	_, err := c.GetWorkerStatus(ctx)
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		result.Status = CheckPassed
+		result.Detail = "the service accepted an authenticated request"
+	case errors.Is(err, ErrSigningKeyRequired):
+		result.Status = CheckFailed
+		result.Detail = err.Error()
+		result.Hint = "set Signing.Secret or remove Signing"
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		result.Status = CheckFailed
+		result.Detail = fmt.Sprintf("the service rejected the client's credentials with %d: %s", apiErr.StatusCode, apiErr.Message)
+		result.Hint = "check the signing secret and key ID against the ones registered with the service"
+	default:
+		result.Status = CheckFailed
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// checkAPIVersion compares the configured API version with those the
+// service advertises
+func (c *Client) checkAPIVersion(ctx context.Context) CheckResult {
+	result := CheckResult{Name: CheckAPIVersion}
+
+	version, err := c.resolveAPIVersion(ctx)
+	if err != nil {
+		result.Status = CheckFailed
+		result.Detail = err.Error()
+		return result
+	}
+
+	info, err := c.GetServiceInfo(ctx)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		// Services that predate /info only speak v1
+		if version != APIVersionV1 {
+			result.Status = CheckFailed
+			result.Detail = fmt.Sprintf("the service does not report its API versions and %s cannot be verified", version)
+			result.Hint = "use APIVersion v1 with services older than the /info endpoint"
+			return result
+		}
+		result.Status = CheckPassed
+		result.Detail = "v1 (service does not report API versions)"
+		return result
+	}
+	if err != nil {
+		result.Status = CheckFailed
+		result.Detail = err.Error()
+		return result
+	}
+
+	if len(info.APIVersions) > 0 && !info.SupportsAPIVersion(version) {
+		supported := make([]string, len(info.APIVersions))
+		for i, v := range info.APIVersions {
+			supported[i] = string(v)
+		}
+		result.Status = CheckFailed
+		result.Detail = fmt.Sprintf("service %s supports %s, not %s", info.Version, strings.Join(supported, ", "), version)
+		result.Hint = "set APIVersion to a supported version, or to detect"
+		return result
+	}
+
+	result.Status = CheckPassed
+	result.Detail = fmt.Sprintf("%s on service %s", version, info.Version)
+	return result
+}
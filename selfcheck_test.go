@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelfCheck(t *testing.T) {
+	authorized := true
+	versions := []APIVersion{APIVersionV1, APIVersionV2}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte("OK"))
+		case "/api/v1/workers/status", "/api/v2/workers/status":
+			if !authorized {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"code":"unauthorized","message":"bad signature"}}`))
+				return
+			}
+			json.NewEncoder(w).Encode(WorkerStatusResponse{})
+		case "/info":
+			json.NewEncoder(w).Encode(ServiceInfo{Version: "2.4.0", APIVersions: versions})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	status := func(report *SelfCheckReport) map[string]string {
+		statuses := make(map[string]string)
+		for _, check := range report.Checks {
+			statuses[check.Name] = check.Status
+		}
+		return statuses
+	}
+
+	client := NewClient(&Config{BaseURL: server.URL, APIVersion: APIVersionV2})
+	if report := client.SelfCheck(ctx); !report.OK() || len(report.Checks) != 3 {
+		t.Errorf("Expected passing self-check, got %+v", report)
+	}
+
+	authorized = false
+	versions = []APIVersion{APIVersionV1}
+	report := client.SelfCheck(ctx)
+	got := status(report)
+	if got[CheckConnectivity] != CheckPassed || got[CheckCredentials] != CheckFailed || got[CheckAPIVersion] != CheckFailed {
+		t.Errorf("Unexpected check statuses: %v", got)
+	}
+	for _, check := range report.Failed() {
+		if check.Hint == "" {
+			t.Errorf("Expected hint for failed check %s", check.Name)
+		}
+	}
+
+	unreachable := NewClient(&Config{BaseURL: "http://127.0.0.1:1"})
+	got = status(unreachable.SelfCheck(ctx))
+	if got[CheckConnectivity] != CheckFailed || got[CheckCredentials] != CheckSkipped || got[CheckAPIVersion] != CheckSkipped {
+		t.Errorf("Expected dependent checks to be skipped, got %v", got)
+	}
+}
@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// Sender submits messages with preset fields, so that code sending the same
+// kind of message repeatedly sets its priority, topic, callback URL, and
+// other defaults in one place. Presets take precedence over topic and client
+// defaults, and fields set on a submitted request take precedence over
+// presets. Each setter returns a new Sender, leaving the receiver unchanged,
+// so senders can be shared and derived from one another
+type Sender struct {
+	client  *Client
+	presets MessageRequest
+}
+
+// Priority returns a Sender of messages with priority, sent to
+// TopicPullRequests until Topic picks another topic
+func (c *Client) Priority(priority Priority) *Sender {
+	return &Sender{client: c, presets: MessageRequest{Priority: priority, Topic: TopicPullRequests}}
+}
+
+// Priority returns a Sender of messages with priority to the topic
+func (t *TopicClient) Priority(priority Priority) *Sender {
+	return &Sender{client: t.client, presets: MessageRequest{Priority: priority, Topic: t.topic}}
+}
+
+// with returns a copy of s with set applied to its presets
+func (s *Sender) with(set func(presets *MessageRequest)) *Sender {
+	presets := s.presets
+	presets.Metadata = maps.Clone(s.presets.Metadata)
+	set(&presets)
+	return &Sender{client: s.client, presets: presets}
+}
+
+// Priority returns a Sender of messages with priority
+func (s *Sender) Priority(priority Priority) *Sender {
+	return s.with(func(p *MessageRequest) { p.Priority = priority })
+}
+
+// Topic returns a Sender of messages to topic
+func (s *Sender) Topic(topic Topic) *Sender {
+	return s.with(func(p *MessageRequest) { p.Topic = topic })
+}
+
+// CallbackURL returns a Sender whose messages call back url unless they
+// have their own callback URL
+func (s *Sender) CallbackURL(url string) *Sender {
+	return s.with(func(p *MessageRequest) { p.CallbackURL = url })
+}
+
+// TTL returns a Sender of messages that expire after ttl
+func (s *Sender) TTL(ttl time.Duration) *Sender {
+	return s.with(func(p *MessageRequest) { p.TTL = ttl })
+}
+
+// RetryPolicy returns a Sender of messages retried according to policy
+func (s *Sender) RetryPolicy(policy *RetryPolicy) *Sender {
+	return s.with(func(p *MessageRequest) { p.RetryPolicy = policy })
+}
+
+// Metadata returns a Sender of messages carrying md, merged under the
+// metadata of each request
+func (s *Sender) Metadata(md map[string]string) *Sender {
+	return s.with(func(p *MessageRequest) {
+		if p.Metadata == nil {
+			p.Metadata = make(map[string]string, len(md))
+		}
+		maps.Copy(p.Metadata, md)
+	})
+}
+
+// Presets returns the fields the Sender fills in
+func (s *Sender) Presets() MessageRequest {
+	presets := s.presets
+	presets.Metadata = maps.Clone(s.presets.Metadata)
+	return presets
+}
+
+// Post submits a message with the Sender's presets. An empty callbackURL
+// uses the preset callback URL
+func (s *Sender) Post(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return s.client.PostMessage(ctx, s.apply(&MessageRequest{ItemID: itemID, CallbackURL: callbackURL, ObjectBody: objectBody}))
+}
+
+// PostMessage submits req with its empty fields filled from the Sender's
+// presets, leaving req untouched
+func (s *Sender) PostMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+	return s.client.PostMessage(ctx, s.apply(req))
+}
+
+// apply returns a copy of req with its empty fields filled from the presets
+func (s *Sender) apply(req *MessageRequest) *MessageRequest {
+	out := *req
+	p := s.presets
+	if out.Priority == "" {
+		out.Priority = p.Priority
+	}
+	if out.Topic == "" {
+		out.Topic = p.Topic
+	}
+	if out.CallbackURL == "" {
+		out.CallbackURL = p.CallbackURL
+	}
+	if out.TTL == 0 {
+		out.TTL = p.TTL
+	}
+	if out.RetryPolicy == nil {
+		out.RetryPolicy = p.RetryPolicy
+	}
+	if len(p.Metadata) > 0 {
+		metadata := maps.Clone(p.Metadata)
+		maps.Copy(metadata, out.Metadata)
+		out.Metadata = metadata
+	}
+	return &out
+}
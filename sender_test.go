@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSender(t *testing.T) {
+	var received []MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		w.Write([]byte(`{"id":"msg-1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	client.SetTopicDefaults("deployments", TopicDefaults{Priority: PriorityLow, TTL: time.Hour})
+	deployments := client.Priority(PriorityHigh).Topic("deployments").
+		CallbackURL("https://example.com/deployed").
+		Metadata(map[string]string{"source": "ci"})
+
+	ctx := context.Background()
+	if _, err := deployments.Post(ctx, "deploy-1", "", map[string]string{"sha": "abc"}); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if _, err := deployments.Post(ctx, "deploy-2", "https://example.com/other", nil); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if _, err := deployments.PostMessage(ctx, &MessageRequest{
+		ItemID:   "deploy-3",
+		Priority: PriorityMedium,
+		Metadata: map[string]string{"source": "manual"},
+	}); err != nil {
+		t.Fatalf("PostMessage failed: %v", err)
+	}
+
+	first := received[0]
+	if first.Priority != PriorityHigh || first.Topic != "deployments" || first.CallbackURL != "https://example.com/deployed" {
+		t.Errorf("Expected presets to apply, got %+v", first)
+	}
+	if first.TTL != time.Hour {
+		t.Errorf("Expected topic defaults to fill fields without presets, got TTL %v", first.TTL)
+	}
+	if first.Metadata["source"] != "ci" {
+		t.Errorf("Expected preset metadata, got %v", first.Metadata)
+	}
+	if received[1].CallbackURL != "https://example.com/other" {
+		t.Errorf("Expected an explicit callback URL to win, got '%s'", received[1].CallbackURL)
+	}
+	if received[2].Priority != PriorityMedium || received[2].Metadata["source"] != "manual" {
+		t.Errorf("Expected request fields to win over presets, got %+v", received[2])
+	}
+}
+
+func TestSenderDerivation(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://localhost"})
+	base := client.Topic("deployments").Priority(PriorityHigh).Metadata(map[string]string{"team": "infra"})
+	low := base.Priority(PriorityLow).Metadata(map[string]string{"tier": "batch"})
+
+	if presets := base.Presets(); presets.Priority != PriorityHigh || presets.Topic != "deployments" || len(presets.Metadata) != 1 {
+		t.Errorf("Expected deriving a sender to leave the original unchanged, got %+v", presets)
+	}
+	if presets := low.Presets(); presets.Priority != PriorityLow || presets.Metadata["team"] != "infra" || presets.Metadata["tier"] != "batch" {
+		t.Errorf("Unexpected derived presets %+v", presets)
+	}
+	if presets := client.Priority(PriorityMedium).Presets(); presets.Topic != TopicPullRequests {
+		t.Errorf("Expected the default topic, got '%s'", presets.Topic)
+	}
+}
@@ -0,0 +1,151 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+)
+
+// Shard is a logical partition: a client for the deployment serving it and,
+// optionally, the topic its messages are sent to
+type Shard struct {
+	Client *Client
+	// Topic replaces the topic of routed messages when set
+	Topic Topic
+}
+
+// ShardKeyFunc returns the key a message is partitioned by. Messages with the
+// same key always go to the same shard, preserving their relative order
+type ShardKeyFunc func(req *MessageRequest) string
+
+// ShardByItemID partitions messages by their item ID
+func ShardByItemID(req *MessageRequest) string {
+	return req.ItemID
+}
+
+// ShardByGroupID partitions messages by their group, so that a group's
+// ordering holds across shards; ungrouped messages fall back to their item ID
+func ShardByGroupID(req *MessageRequest) string {
+	if req.GroupID != "" {
+		return "group:" + req.GroupID
+	}
+	return req.ItemID
+}
+
+// ShardRouter spreads messages over shards by hashing a key of each message.
+// It uses jump consistent hashing, so adding a shard at the end moves only
+// about 1/N of the keys
+type ShardRouter struct {
+	shards []Shard
+	key    ShardKeyFunc
+}
+
+// NewShardRouter creates a router over shards. key selects the partition
+// key; nil partitions by group, or by item ID for ungrouped messages
+func NewShardRouter(shards []Shard, key ShardKeyFunc) (*ShardRouter, error) {
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("at least one shard is required")
+	}
+	for i, shard := range shards {
+		if shard.Client == nil {
+			return nil, fmt.Errorf("shard %d has no client", i)
+		}
+	}
+
+	if key == nil {
+		key = ShardByGroupID
+	}
+
+	return &ShardRouter{shards: append([]Shard(nil), shards...), key: key}, nil
+}
+
+// ShardFor returns the index of the shard owning key
+func (r *ShardRouter) ShardFor(key string) int {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return jumpHash(h.Sum64(), len(r.shards))
+}
+
+// Route returns the shard index for req and a copy of req addressed to it
+func (r *ShardRouter) Route(req *MessageRequest) (int, *MessageRequest) {
+	index := r.ShardFor(r.key(req))
+	routed := *req
+	if topic := r.shards[index].Topic; topic != "" {
+		routed.Topic = topic
+	}
+	return index, &routed
+}
+
+// PostMessage submits req through the shard owning its key
+func (r *ShardRouter) PostMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	index, routed := r.Route(req)
+	return r.shards[index].Client.PostMessage(ctx, routed)
+}
+
+// Split partitions messages by shard index, keeping their relative order
+// within each shard, so that they can be submitted in per-shard bulk requests
+func (r *ShardRouter) Split(messages []MessageRequest) map[int][]MessageRequest {
+	split := make(map[int][]MessageRequest)
+	for i := range messages {
+		index, routed := r.Route(&messages[i])
+		split[index] = append(split[index], *routed)
+	}
+	return split
+}
+
+// PostBulkMessages splits req by shard and submits one bulk request per
+// shard, returning the responses keyed by shard index. Partially failed
+// shards do not stop the others and are reported afterwards with a wrapped
+// *BulkPartialError; any other error stops submission
+func (r *ShardRouter) PostBulkMessages(ctx context.Context, req *BulkMessageRequest) (map[int]*BulkMessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("bulk message request cannot be nil")
+	}
+
+	split := r.Split(req.Messages)
+	responses := make(map[int]*BulkMessageResponse)
+	var partialErr error
+	for index := range r.shards {
+		messages := split[index]
+		if len(messages) == 0 {
+			continue
+		}
+
+		resp, err := r.shards[index].Client.PostBulkMessages(ctx, &BulkMessageRequest{Messages: messages})
+		if resp != nil {
+			responses[index] = resp
+		}
+		if IsBulkPartialError(err) {
+			if partialErr == nil {
+				partialErr = fmt.Errorf("shard %d: %w", index, err)
+			}
+			continue
+		}
+		if err != nil {
+			return responses, fmt.Errorf("shard %d: %w", index, err)
+		}
+	}
+
+	return responses, partialErr
+}
+
+// Len returns the number of shards
+func (r *ShardRouter) Len() int {
+	return len(r.shards)
+}
+
+// jumpHash maps key onto one of n buckets (Lamping and Veach, "A Fast,
+// Minimal Memory, Consistent Hash Algorithm")
+func jumpHash(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShardRouter(t *testing.T) {
+	received := make([][]MessageRequest, 2)
+	var servers []*httptest.Server
+	for i := range received {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req MessageRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			received[i] = append(received[i], req)
+			json.NewEncoder(w).Encode(MessageResponse{ID: fmt.Sprintf("shard-%d", i)})
+		}))
+		defer server.Close()
+		servers = append(servers, server)
+	}
+
+	router, err := NewShardRouter([]Shard{
+		{Client: NewClient(&Config{BaseURL: servers[0].URL})},
+		{Client: NewClient(&Config{BaseURL: servers[1].URL}), Topic: "pullrequests-b"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 40; i++ {
+		itemID := fmt.Sprintf("pr-%d", i%10)
+		req := newMessageRequest(itemID, PriorityMedium, TopicPullRequests, "https://example.com/callback", nil)
+		resp, err := router.PostMessage(ctx, req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if want := fmt.Sprintf("shard-%d", router.ShardFor(itemID)); resp.ID != want {
+			t.Errorf("Expected %s to be routed to %s, got %s", itemID, want, resp.ID)
+		}
+	}
+
+	if len(received[0]) == 0 || len(received[1]) == 0 {
+		t.Errorf("Expected both shards to receive messages, got %d and %d", len(received[0]), len(received[1]))
+	}
+	for _, req := range received[1] {
+		if req.Topic != "pullrequests-b" {
+			t.Errorf("Expected shard topic override, got '%s'", req.Topic)
+		}
+	}
+}
+
+func TestJumpHashStability(t *testing.T) {
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := uint64(i) * 0x9E3779B97F4A7C15
+		before, after := jumpHash(key, 10), jumpHash(key, 11)
+		if before < 0 || before >= 10 || after < 0 || after >= 11 {
+			t.Fatalf("Bucket out of range: %d, %d", before, after)
+		}
+		if before != after {
+			if after != 10 {
+				t.Errorf("Expected keys to move only to the new shard, key %d moved %d -> %d", i, before, after)
+			}
+			moved++
+		}
+	}
+
+	if moved == 0 || moved > 200 {
+		t.Errorf("Expected roughly 1/11 of keys to move, got %d of 1000", moved)
+	}
+}
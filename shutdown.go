@@ -0,0 +1,256 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds how long HandleSignals waits for a clean shutdown
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Flusher is implemented by components that buffer outgoing submissions,
+// such as a producer or batcher
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Drainer is implemented by components that process incoming callbacks,
+// such as a callback receiver
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// HandleSignals waits for SIGINT or SIGTERM and then shuts down the given
+// components within DefaultShutdownTimeout. The receiver is drained first so
+// that callbacks still being handled can submit follow-up messages, the
+// producer is flushed next, and the client is shut down last, running its
+// registered hooks. producer and receiver may be nil; a ConsumerLoop or a
+// receiver.Drainer can serve as the receiver. The returned channel receives
+// the shutdown result and is then closed. When ctx is done before a signal
+// arrives, HandleSignals stops listening for signals and closes the channel
+// without shutting anything down
+func HandleSignals(ctx context.Context, client *Client, producer Flusher, receiver Drainer) <-chan error {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		select {
+		case <-sigs:
+		case <-ctx.Done():
+			signal.Stop(sigs)
+			return
+		}
+		signal.Stop(sigs)
+
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+		defer cancel()
+		done <- shutdownComponents(ctx, client, producer, receiver)
+	}()
+
+	return done
+}
+
+// shutdownComponents drains the receiver, flushes the producer, and shuts
+// the client down, continuing past failures so that every component gets a
+// chance to stop
+func shutdownComponents(ctx context.Context, client *Client, producer Flusher, receiver Drainer) error {
+	var errs []error
+
+	if !isNil(receiver) {
+		if err := receiver.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drain receiver: %w", err))
+		}
+	}
+
+	if !isNil(producer) {
+		if err := producer.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush producer: %w", err))
+		}
+	}
+
+	if client != nil {
+		if err := client.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// isNil reports whether v is nil or holds a nil pointer, e.g. a
+// *Producer variable that was never assigned
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Func, reflect.Chan, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// ShutdownHook is a component torn down by Client.Shutdown. Shutdown must
+// return promptly once ctx is done, abandoning whatever work remains
+type ShutdownHook interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ShutdownHookFunc adapts a function to the ShutdownHook interface, e.g. a
+// callback receiver's Drain method
+type ShutdownHookFunc func(ctx context.Context) error
+
+// Shutdown calls f(ctx)
+func (f ShutdownHookFunc) Shutdown(ctx context.Context) error {
+	return f(ctx)
+}
+
+// ShutdownPhase orders the hooks run by Client.Shutdown
+type ShutdownPhase int
+
+// Shutdown phases, in the order they run
+const (
+	// ShutdownStopIntake stops components that take in new work, such as
+	// consumer loops, callback receivers, and scale schedules
+	ShutdownStopIntake ShutdownPhase = iota
+	// ShutdownFlush submits what is still buffered, by producers and the
+	// asynchronous submission queue
+	ShutdownFlush
+	// ShutdownCancel ends long-lived work, such as event subscriptions
+	ShutdownCancel
+)
+
+func (p ShutdownPhase) String() string {
+	switch p {
+	case ShutdownStopIntake:
+		return "stop intake"
+	case ShutdownFlush:
+		return "flush"
+	case ShutdownCancel:
+		return "cancel"
+	default:
+		return fmt.Sprintf("phase %d", int(p))
+	}
+}
+
+// shutdownPhases lists the phases in the order they run
+var shutdownPhases = []ShutdownPhase{ShutdownStopIntake, ShutdownFlush, ShutdownCancel}
+
+// shutdownRegistry holds the hooks registered with a client
+type shutdownRegistry struct {
+	mu     sync.Mutex
+	nextID int
+	hooks  map[ShutdownPhase]map[int]ShutdownHook
+}
+
+// RegisterShutdownHook adds hook to the given phase of Shutdown and returns
+// a function that removes it again. Producers, consumer loops, scale
+// schedules, event subscriptions, and the asynchronous submission queue
+// register themselves while they run
+func (c *Client) RegisterShutdownHook(phase ShutdownPhase, hook ShutdownHook) (unregister func()) {
+	r := &c.shutdown
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hooks == nil {
+		r.hooks = make(map[ShutdownPhase]map[int]ShutdownHook)
+	}
+	if r.hooks[phase] == nil {
+		r.hooks[phase] = make(map[int]ShutdownHook)
+	}
+	id := r.nextID
+	r.nextID++
+	r.hooks[phase][id] = hook
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.hooks[phase], id)
+	}
+}
+
+// Shutdown tears down the components registered with the client and then
+// closes it. The hooks of each phase run concurrently, and a phase starts
+// once the previous one finished, so intake stops before buffers are
+// flushed and flushing completes before subscriptions end. When ctx is done
+// before a phase finishes, Shutdown stops waiting for it and runs the
+// remaining phases with the expired ctx, which makes them cancel their
+// in-flight work, including retries; Shutdown then returns without waiting
+// for hooks that ignore ctx
+func (c *Client) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, phase := range shutdownPhases {
+		if err := c.runShutdownPhase(ctx, phase); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := c.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close client: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// runShutdownPhase runs the hooks of phase concurrently and waits for them
+// or for ctx
+func (c *Client) runShutdownPhase(ctx context.Context, phase ShutdownPhase) error {
+	r := &c.shutdown
+	r.mu.Lock()
+	hooks := make([]ShutdownHook, 0, len(r.hooks[phase]))
+	for _, hook := range r.hooks[phase] {
+		hooks = append(hooks, hook)
+	}
+	delete(r.hooks, phase)
+	r.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	results := make(chan error, len(hooks))
+	for _, hook := range hooks {
+		go func() {
+			results <- hook.Shutdown(ctx)
+		}()
+	}
+
+	var errs []error
+	for range hooks {
+		select {
+		case err := <-results:
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case <-ctx.Done():
+			errs = append(errs, ctx.Err())
+			return fmt.Errorf("shutdown %s: %w", phase, errors.Join(errs...))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("shutdown %s: %w", phase, errors.Join(errs...))
+	}
+	return nil
+}
+
+// cancelOnShutdown returns a context that is also canceled in the
+// ShutdownCancel phase, and a function releasing it once it is no longer used
+func (c *Client) cancelOnShutdown(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	unregister := c.RegisterShutdownHook(ShutdownCancel, ShutdownHookFunc(func(context.Context) error {
+		cancel()
+		return nil
+	}))
+	return ctx, func() {
+		unregister()
+		cancel()
+	}
+}
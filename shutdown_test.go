@@ -0,0 +1,197 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingComponent struct {
+	name  string
+	order *[]string
+	err   error
+}
+
+func (r *recordingComponent) Flush(ctx context.Context) error {
+	*r.order = append(*r.order, r.name)
+	return r.err
+}
+
+func (r *recordingComponent) Drain(ctx context.Context) error {
+	*r.order = append(*r.order, r.name)
+	return r.err
+}
+
+func TestShutdownComponentsOrder(t *testing.T) {
+	var order []string
+	producer := &recordingComponent{name: "producer", order: &order, err: errors.New("flush failed")}
+	receiver := &recordingComponent{name: "receiver", order: &order}
+
+	err := shutdownComponents(context.Background(), NewClientWithDefaults(), producer, receiver)
+	if err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("Expected flush error, got %v", err)
+	}
+
+	if strings.Join(order, ",") != "receiver,producer" {
+		t.Errorf("Expected receiver to drain before producer flush, got %v", order)
+	}
+}
+
+func TestShutdownComponentsNil(t *testing.T) {
+	if err := shutdownComponents(context.Background(), nil, nil, nil); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestClientShutdownPhases(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://example.com"})
+
+	var mu sync.Mutex
+	var order []string
+	hook := func(name string, err error) ShutdownHook {
+		return ShutdownHookFunc(func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return err
+		})
+	}
+
+	client.RegisterShutdownHook(ShutdownCancel, hook("subscription", nil))
+	client.RegisterShutdownHook(ShutdownFlush, hook("producer", errors.New("flush failed")))
+	client.RegisterShutdownHook(ShutdownStopIntake, hook("consumer", nil))
+	unregister := client.RegisterShutdownHook(ShutdownFlush, hook("removed", nil))
+	unregister()
+
+	err := client.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "shutdown flush: flush failed") {
+		t.Errorf("Expected the flush error, got %v", err)
+	}
+	if strings.Join(order, ",") != "consumer,producer,subscription" {
+		t.Errorf("Expected hooks to run by phase, got %v", order)
+	}
+
+	future := client.PostMessageAsync(context.Background(), newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/cb", nil))
+	if _, err := future.Result(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after shutdown, got %v", err)
+	}
+}
+
+func TestClientShutdownDeadline(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://example.com"})
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	client.RegisterShutdownHook(ShutdownFlush, ShutdownHookFunc(func(ctx context.Context) error {
+		<-stuck
+		return nil
+	}))
+	canceled := make(chan error, 1)
+	client.RegisterShutdownHook(ShutdownCancel, ShutdownHookFunc(func(ctx context.Context) error {
+		canceled <- ctx.Err()
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := client.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected Shutdown to return by the deadline, took %v", elapsed)
+	}
+	select {
+	case err := <-canceled:
+		if err == nil {
+			t.Error("Expected later phases to run with the expired context")
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected later phases to run after the deadline")
+	}
+}
+
+func TestClientShutdownCancelsProducerRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	producer := NewProducer(client, ProducerConfig{BatchSize: 1, MaxRetries: 100, RetryBackoff: time.Hour})
+	future := producer.Send(context.Background(), newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/cb", nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-future.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the retrying batch to be abandoned")
+	}
+	if _, err := future.Result(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the future to fail with context.Canceled, got %v", err)
+	}
+	if f := producer.Send(context.Background(), newMessageRequest("pr-2", PriorityLow, TopicPullRequests, "https://example.com/cb", nil)); !errors.Is(f.err, ErrProducerClosed) {
+		t.Errorf("Expected ErrProducerClosed after shutdown, got %v", f.err)
+	}
+}
+
+func TestClientShutdownEndsSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	events, err := client.SubscribeWorkerEvents(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Expected no events")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the subscription to end")
+	}
+}
+
+func TestShutdownComponentsTypedNil(t *testing.T) {
+	var producer *Producer
+	var receiver *ConsumerLoop
+	if err := shutdownComponents(context.Background(), nil, producer, receiver); err != nil {
+		t.Errorf("Expected nil components to be skipped, got %v", err)
+	}
+}
+
+func TestHandleSignalsStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := HandleSignals(ctx, nil, nil, nil)
+	cancel()
+
+	select {
+	case err, ok := <-done:
+		if ok {
+			t.Errorf("Expected the channel to be closed without a result, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected HandleSignals to stop with its context")
+	}
+}
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRequestSigning(t *testing.T) {
+	secret := []byte("s3cret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+		if err != nil {
+			t.Errorf("Invalid timestamp header: %v", err)
+		}
+		expected := Sign(secret, CanonicalRequest(r.Method, r.URL.RequestURI(), hex.EncodeToString(sum[:]), timestamp))
+		if r.Header.Get(SignatureHeader) != expected {
+			t.Errorf("Signature mismatch for %s %s", r.Method, r.URL.RequestURI())
+		}
+		if r.Header.Get(SignatureKeyIDHeader) != "key-1" {
+			t.Errorf("Expected key id 'key-1', got '%s'", r.Header.Get(SignatureKeyIDHeader))
+		}
+
+		w.Write([]byte(`{"id":"msg-1","total_workers":0}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:    server.URL,
+		Compressor: GzipCompressor{},
+		Signing:    &SigningConfig{Secret: secret, KeyID: "key-1"},
+	})
+	ctx := context.Background()
+
+	if _, err := client.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/callback", map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetQueueDepthHistory(ctx, PriorityHigh, time.Hour, 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestSigningRequiresSecret(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	config := &Config{BaseURL: server.URL, Signing: &SigningConfig{KeyID: "key-1"}}
+	if err := config.Validate(); !errors.Is(err, ErrSigningKeyRequired) {
+		t.Errorf("Expected ErrSigningKeyRequired from Validate, got %v", err)
+	}
+
+	_, err := NewClient(config).GetWorkerStatus(context.Background())
+	if !errors.Is(err, ErrSigningKeyRequired) {
+		t.Errorf("Expected ErrSigningKeyRequired, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no unsigned requests to be sent, got %d", calls)
+	}
+}
@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrSpilled is returned by PostMessageOrSpill when the service was at
+// capacity and the message was handed to the spill handler instead
+var ErrSpilled = errors.New("message spilled: service at capacity")
+
+// SpillHandler receives messages the service rejected for capacity reasons,
+// e.g. to write them to a file, object storage, or a secondary cluster
+type SpillHandler interface {
+	Spill(ctx context.Context, req *MessageRequest, cause error) error
+}
+
+// SpillHandlerFunc adapts a function to the SpillHandler interface
+type SpillHandlerFunc func(ctx context.Context, req *MessageRequest, cause error) error
+
+// Spill calls f(ctx, req, cause)
+func (f SpillHandlerFunc) Spill(ctx context.Context, req *MessageRequest, cause error) error {
+	return f(ctx, req, cause)
+}
+
+// IsCapacityError reports whether err is the service signalling that it is at
+// capacity or applying backpressure (429, 503, or 507)
+func IsCapacityError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusInsufficientStorage:
+		return true
+	default:
+		return false
+	}
+}
+
+// PostMessageOrSpill submits the message and, if the service rejects it
+// because it is at capacity, hands it to spill rather than dropping it or
+// blocking. It returns ErrSpilled once the spill handler accepted the message
+func (c *Client) PostMessageOrSpill(ctx context.Context, req *MessageRequest, spill SpillHandler) (*MessageResponse, error) {
+	if spill == nil {
+		return nil, fmt.Errorf("spill handler cannot be nil")
+	}
+
+	resp, err := c.PostMessage(ctx, req)
+	if err == nil || !IsCapacityError(err) {
+		return resp, err
+	}
+
+	// Spilled messages are stored locally, so they are encrypted like
+	// submitted ones
+	spilled := c.withDefaults(ctx, req)
+	if encErr := c.encryptPayload(ctx, "", spilled); encErr != nil {
+		return nil, fmt.Errorf("failed to spill message after %v: %w", err, encErr)
+	}
+	if spillErr := spill.Spill(ctx, spilled, err); spillErr != nil {
+		return nil, fmt.Errorf("failed to spill message after %v: %w", err, spillErr)
+	}
+
+	return nil, ErrSpilled
+}
+
+// Spill stores the message in the outbox for later replay, so an outbox can
+// be used as the spill handler of PostMessageOrSpill
+func (o *Outbox) Spill(ctx context.Context, req *MessageRequest, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.append(ArchiveRecord{EnqueuedAt: o.client.clock.Now(), Request: *req})
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestPostMessageOrSpill(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+
+	var spilled []string
+	handler := SpillHandlerFunc(func(ctx context.Context, req *MessageRequest, cause error) error {
+		if !IsCapacityError(cause) {
+			t.Errorf("Expected capacity error as cause, got %v", cause)
+		}
+		spilled = append(spilled, req.ItemID)
+		return nil
+	})
+
+	_, err := client.PostMessageOrSpill(context.Background(), req, handler)
+	if !errors.Is(err, ErrSpilled) {
+		t.Fatalf("Expected ErrSpilled, got %v", err)
+	}
+	if len(spilled) != 1 || spilled[0] != "pr-1" {
+		t.Errorf("Expected pr-1 to be spilled, got %v", spilled)
+	}
+
+	outbox, err := NewOutbox(client, OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.PostMessageOrSpill(context.Background(), req, outbox); !errors.Is(err, ErrSpilled) {
+		t.Fatalf("Expected ErrSpilled, got %v", err)
+	}
+	if outbox.Stats().Depth != 1 {
+		t.Errorf("Expected outbox depth 1, got %d", outbox.Stats().Depth)
+	}
+}
+
+func TestPostMessageOrSpillPassesThroughOtherErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	req := newMessageRequest("pr-1", PriorityLow, TopicPullRequests, "https://example.com/callback", nil)
+	handler := SpillHandlerFunc(func(ctx context.Context, req *MessageRequest, cause error) error {
+		t.Error("Expected spill handler not to be called")
+		return nil
+	})
+
+	if _, err := client.PostMessageOrSpill(context.Background(), req, handler); !IsAPIError(err) {
+		t.Errorf("Expected APIError, got %v", err)
+	}
+}
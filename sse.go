@@ -0,0 +1,180 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reconnection backoff for event streams
+const (
+	streamMinBackoff = time.Second
+	streamMaxBackoff = 30 * time.Second
+)
+
+// sseEvent is a single dispatched Server-Sent Event
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  []byte
+}
+
+// eventStream follows a Server-Sent Events endpoint, reconnecting with
+// backoff and resuming from the last event ID it delivered
+type eventStream struct {
+	client      *Client
+	path        string
+	query       url.Values
+	lastEventID string
+	retry       time.Duration
+}
+
+// openStream connects to the stream once so that configuration errors are
+// reported to the caller, then follows it in the background, calling deliver
+// for each event until ctx is done or the server rejects the stream
+// permanently. done is called once the stream stops
+func (c *Client) openStream(ctx context.Context, path string, query url.Values, deliver func(sseEvent), done func()) error {
+	s := &eventStream{client: c, path: path, query: query}
+
+	body, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer done()
+		s.follow(ctx, body, deliver)
+	}()
+
+	return nil
+}
+
+// follow reads events from body and reconnects when the stream drops
+func (s *eventStream) follow(ctx context.Context, body io.ReadCloser, deliver func(sseEvent)) {
+	backoff := streamMinBackoff
+	for {
+		if body != nil {
+			s.read(body, deliver)
+			body.Close()
+			backoff = streamMinBackoff
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		delay := backoff
+		if s.retry > 0 {
+			delay = s.retry
+		}
+
+		if sleep(ctx, s.client.clock, delay) != nil {
+			return
+		}
+
+		var err error
+		body, err = s.connect(ctx)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+				return
+			}
+			body = nil
+			backoff *= 2
+			if backoff > streamMaxBackoff {
+				backoff = streamMaxBackoff
+			}
+		}
+	}
+}
+
+// connect opens the stream, resuming after the last delivered event
+func (s *eventStream) connect(ctx context.Context) (io.ReadCloser, error) {
+	path := s.path
+	if len(s.query) > 0 {
+		path += "?" + s.query.Encode()
+	}
+
+	req, err := s.client.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+
+	resp, err := s.client.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	s.client.version.observe(resp)
+	s.client.recordResponseMeta(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, s.client.translateError(resp, msg)
+	}
+
+	return resp.Body, nil
+}
+
+// read parses events from r until it ends
+func (s *eventStream) read(r io.Reader, deliver func(sseEvent)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxPayloadSize)
+
+	var event sseEvent
+	var data bytes.Buffer
+	var hasID bool
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			// An id line moves the resume point even for an event without
+			// data, and an empty id clears it
+			if hasID {
+				s.lastEventID = event.ID
+			}
+			if data.Len() > 0 {
+				event.Data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+				deliver(event)
+			}
+			event = sseEvent{}
+			data = bytes.Buffer{}
+			hasID = false
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+			hasID = true
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
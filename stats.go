@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// StatsOptions narrows the messages GetMessageStats reports on; empty
+// fields match everything
+type StatsOptions struct {
+	Topic    Topic
+	Priority Priority
+	// Window is how far back to aggregate; zero uses the service default
+	Window time.Duration
+}
+
+// query encodes the options as URL query parameters
+func (o StatsOptions) query() url.Values {
+	q := url.Values{}
+	if o.Topic != "" {
+		q.Set("topic", string(o.Topic))
+	}
+	if o.Priority != "" {
+		q.Set("priority", string(o.Priority))
+	}
+	if o.Window > 0 {
+		q.Set("window", o.Window.String())
+	}
+	return q
+}
+
+// StatusCounts holds the number of messages in each processing state
+type StatusCounts struct {
+	Queued       int64 `json:"queued"`
+	Processing   int64 `json:"processing"`
+	Completed    int64 `json:"completed"`
+	Failed       int64 `json:"failed"`
+	DeadLettered int64 `json:"dead_lettered,omitempty"`
+	TimedOut     int64 `json:"timed_out,omitempty"`
+}
+
+// Total returns the number of messages across all states
+func (s StatusCounts) Total() int64 {
+	return s.Queued + s.Processing + s.Completed + s.Failed + s.DeadLettered + s.TimedOut
+}
+
+// LatencyPercentiles holds processing latency percentiles, from dequeue to
+// callback completion
+type LatencyPercentiles struct {
+	P50Ms int64 `json:"p50_ms"`
+	P90Ms int64 `json:"p90_ms"`
+	P95Ms int64 `json:"p95_ms"`
+	P99Ms int64 `json:"p99_ms"`
+	MaxMs int64 `json:"max_ms"`
+}
+
+// P50 returns the median processing latency
+func (l LatencyPercentiles) P50() time.Duration {
+	return time.Duration(l.P50Ms) * time.Millisecond
+}
+
+// P90 returns the 90th percentile processing latency
+func (l LatencyPercentiles) P90() time.Duration {
+	return time.Duration(l.P90Ms) * time.Millisecond
+}
+
+// P95 returns the 95th percentile processing latency
+func (l LatencyPercentiles) P95() time.Duration {
+	return time.Duration(l.P95Ms) * time.Millisecond
+}
+
+// P99 returns the 99th percentile processing latency
+func (l LatencyPercentiles) P99() time.Duration {
+	return time.Duration(l.P99Ms) * time.Millisecond
+}
+
+// Max returns the highest processing latency
+func (l LatencyPercentiles) Max() time.Duration {
+	return time.Duration(l.MaxMs) * time.Millisecond
+}
+
+// MessageStats aggregates message processing over a window
+type MessageStats struct {
+	Topic    Topic    `json:"topic,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+	Window   string   `json:"window"`
+	// Counts holds the messages in each state; finished states count
+	// messages that finished within the window
+	Counts  StatusCounts       `json:"counts"`
+	Latency LatencyPercentiles `json:"latency"`
+	// Throughput is the number of messages completed per second
+	Throughput float64 `json:"throughput_per_second"`
+}
+
+// FailureRate returns the fraction of finished messages that did not
+// complete successfully, or 0 when none finished
+func (s *MessageStats) FailureRate() float64 {
+	failed := s.Counts.Failed + s.Counts.DeadLettered + s.Counts.TimedOut
+	finished := failed + s.Counts.Completed
+	if finished == 0 {
+		return 0
+	}
+	return float64(failed) / float64(finished)
+}
+
+// GetMessageStats returns message counts by state, processing latency
+// percentiles, and throughput for the messages matching opts
+func (c *Client) GetMessageStats(ctx context.Context, opts StatsOptions) (*MessageStats, error) {
+	if opts.Priority != "" && !opts.Priority.IsValid() {
+		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+	if opts.Window < 0 {
+		return nil, fmt.Errorf("window cannot be negative")
+	}
+
+	path := "/api/v1/messages/stats"
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats MessageStats
+	if err := c.parseResponse(resp, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
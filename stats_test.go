@@ -0,0 +1,49 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetMessageStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/messages/stats" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("topic") != string(TopicPullRequests) || q.Get("priority") != "high" || q.Get("window") != "1h0m0s" {
+			t.Errorf("Unexpected query '%s'", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"topic":"pullrequests","priority":"high","window":"1h0m0s",
+			"counts":{"queued":12,"processing":3,"completed":540,"failed":40,"dead_lettered":10,"timed_out":10},
+			"latency":{"p50_ms":120,"p90_ms":450,"p95_ms":800,"p99_ms":2100,"max_ms":9000},
+			"throughput_per_second":0.15}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	stats, err := client.GetMessageStats(context.Background(), StatsOptions{Topic: TopicPullRequests, Priority: PriorityHigh, Window: time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stats.Counts.Total() != 615 || stats.Counts.Processing != 3 {
+		t.Errorf("Unexpected counts: %+v", stats.Counts)
+	}
+	if stats.FailureRate() != 0.1 {
+		t.Errorf("Expected failure rate 0.1, got %v", stats.FailureRate())
+	}
+	if stats.Latency.P50() != 120*time.Millisecond || stats.Latency.P99() != 2100*time.Millisecond || stats.Latency.Max() != 9*time.Second {
+		t.Errorf("Unexpected latency: %+v", stats.Latency)
+	}
+	if stats.Throughput != 0.15 {
+		t.Errorf("Expected throughput 0.15, got %v", stats.Throughput)
+	}
+
+	if _, err := client.GetMessageStats(context.Background(), StatsOptions{Priority: "urgent"}); err == nil {
+		t.Error("Expected error for invalid priority")
+	}
+}
@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultStatusCacheTTL is how long GetWorkerStatusCached reuses a status
+// when Config.StatusCache does not set a TTL
+const DefaultStatusCacheTTL = time.Second
+
+// StatusCacheConfig controls the worker status cache used by
+// GetWorkerStatusCached
+type StatusCacheConfig struct {
+	// TTL is how long a status is served without contacting the service;
+	// defaults to DefaultStatusCacheTTL
+	TTL time.Duration
+	// StaleWhileRevalidate is how long after TTL an expired status is still
+	// served while a fresh one is fetched in the background; 0 makes callers
+	// wait for the fetch instead
+	StaleWhileRevalidate time.Duration
+}
+
+// statusCache holds the last worker status fetched by the client
+type statusCache struct {
+	ttl   time.Duration
+	stale time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	status    *WorkerStatusResponse
+	fetchedAt time.Time
+	inflight  *statusFetch
+}
+
+// statusFetch is a worker status request shared by concurrent callers
+type statusFetch struct {
+	done   chan struct{}
+	status *WorkerStatusResponse
+	err    error
+}
+
+// newStatusCache returns a cache configured by config, which may be nil
+func newStatusCache(config *StatusCacheConfig, clock Clock) *statusCache {
+	cache := &statusCache{ttl: DefaultStatusCacheTTL, now: clock.Now}
+	if config != nil {
+		if config.TTL > 0 {
+			cache.ttl = config.TTL
+		}
+		if config.StaleWhileRevalidate > 0 {
+			cache.stale = config.StaleWhileRevalidate
+		}
+	}
+	return cache
+}
+
+// fork returns an empty cache with the same settings
+func (s *statusCache) fork() *statusCache {
+	return &statusCache{ttl: s.ttl, stale: s.stale, now: s.now}
+}
+
+// store records a freshly fetched status
+func (s *statusCache) store(status *WorkerStatusResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.fetchedAt = status, s.now()
+}
+
+// GetWorkerStatusCached returns the worker status from the client's cache,
+// for hot paths such as dashboards that would otherwise call the service
+// hundreds of times per second. A status younger than the cache TTL is
+// returned as is; one within the stale-while-revalidate window is returned
+// while a refresh runs in the background. Otherwise the status is fetched,
+// once for all concurrent callers. Every GetWorkerStatus call also refreshes
+// the cache, and remains the way to bypass it. The returned status is shared
+// between callers and must not be modified
+func (c *Client) GetWorkerStatusCached(ctx context.Context) (*WorkerStatusResponse, error) {
+	cache := c.statusCache
+	cache.mu.Lock()
+	if cache.status != nil {
+		age := cache.now().Sub(cache.fetchedAt)
+		if age < cache.ttl {
+			status := cache.status
+			cache.mu.Unlock()
+			return status, nil
+		}
+		if age < cache.ttl+cache.stale {
+			status := cache.status
+			c.refreshStatus(context.WithoutCancel(ctx))
+			cache.mu.Unlock()
+			return status, nil
+		}
+	}
+	fetch := c.refreshStatus(context.WithoutCancel(ctx))
+	cache.mu.Unlock()
+
+	select {
+	case <-fetch.done:
+		return fetch.status, fetch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refreshStatus starts fetching the worker status unless a fetch is already
+// running, and returns the running fetch. The cache lock must be held
+func (c *Client) refreshStatus(ctx context.Context) *statusFetch {
+	cache := c.statusCache
+	if cache.inflight != nil {
+		return cache.inflight
+	}
+
+	fetch := &statusFetch{done: make(chan struct{})}
+	cache.inflight = fetch
+	go func() {
+		fetch.status, fetch.err = c.GetWorkerStatus(ctx)
+		if fetch.err != nil && c.logger != nil {
+			c.logger.Warn("failed to refresh cached worker status", "error", fetch.err)
+		}
+
+		cache.mu.Lock()
+		cache.inflight = nil
+		cache.mu.Unlock()
+		close(fetch.done)
+	}()
+	return fetch
+}
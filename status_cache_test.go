@@ -0,0 +1,119 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetWorkerStatusCached(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"total_workers":3}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, StatusCache: &StatusCacheConfig{TTL: time.Minute}})
+	now := time.Now()
+	client.statusCache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Concurrent callers share one fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := client.GetWorkerStatusCached(ctx)
+			if err != nil || status.TotalWorkers != 3 {
+				t.Errorf("Expected cached status, got %+v, %v", status, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("Expected 1 request, got %d", got)
+	}
+
+	if _, err := client.GetWorkerStatusCached(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected a fresh status to be served from cache, got %d requests", got)
+	}
+
+	// Explicit calls bypass the cache
+	if _, err := client.GetWorkerStatus(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected GetWorkerStatus to bypass the cache, got %d requests", got)
+	}
+
+	// Expired statuses are fetched again
+	now = now.Add(2 * time.Minute)
+	if _, err := client.GetWorkerStatusCached(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected an expired status to be refetched, got %d requests", got)
+	}
+}
+
+func TestGetWorkerStatusCachedStaleWhileRevalidate(t *testing.T) {
+	var total atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := total.Add(1)
+		if n > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"total_workers":%d}`, n)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:     server.URL,
+		StatusCache: &StatusCacheConfig{TTL: time.Second, StaleWhileRevalidate: time.Minute},
+	})
+	now := time.Now()
+	client.statusCache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := client.GetWorkerStatusCached(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A stale status is served immediately while the refresh is blocked
+	now = now.Add(10 * time.Second)
+	status, err := client.GetWorkerStatusCached(ctx)
+	if err != nil || status.TotalWorkers != 1 {
+		t.Fatalf("Expected the stale status, got %+v, %v", status, err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		status, err := client.GetWorkerStatusCached(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if status.TotalWorkers == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the background refresh to update the cache")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := total.Load(); got != 2 {
+		t.Errorf("Expected a single background refresh, got %d requests", got)
+	}
+}
@@ -0,0 +1,172 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MinSubscriptionSecretLength is the shortest secret accepted for signing
+// subscription deliveries, in bytes
+const MinSubscriptionSecretLength = 16
+
+// DefaultSecretGracePeriod is a grace period for RotateSubscriptionSecret
+// long enough for receivers to deploy the new secret
+const DefaultSecretGracePeriod = 24 * time.Hour
+
+// SubscriptionConfig describes a standing subscription: the service sends
+// every message event of Topic whose status is one of Statuses to URL, so
+// that e.g. all failed deployments reach one handler without each message
+// carrying a callback URL
+type SubscriptionConfig struct {
+	Topic Topic `json:"topic"`
+	// Statuses selects the events delivered, e.g. StatusFailed and
+	// StatusDeadLettered; empty delivers every status change
+	Statuses []MessageStatus `json:"statuses,omitempty"`
+	URL      string          `json:"url"`
+	// Secret signs each delivery with HMAC-SHA256 in SignatureHeader, like
+	// requests signed with SigningConfig; at least
+	// MinSubscriptionSecretLength bytes
+	Secret string `json:"secret"`
+}
+
+// Validate checks the subscription without contacting the service
+func (s *SubscriptionConfig) Validate() error {
+	verr := &ValidationError{}
+	if err := validateTopicName(s.Topic); err != nil {
+		verr.add("topic", "%v", err)
+	}
+	for i, status := range s.Statuses {
+		if status == "" || ParseMessageStatus(string(status)) == UnknownStatus {
+			verr.add(fmt.Sprintf("statuses[%d]", i), "unknown status '%s'", status)
+		}
+	}
+	if s.URL == "" {
+		verr.add("url", "is required")
+	} else if err := validateCallbackURL(s.URL); err != nil {
+		verr.add("url", "%v", err)
+	}
+	if err := validateSubscriptionSecret(s.Secret); err != nil {
+		verr.add("secret", "%v", err)
+	}
+	return verr.errOrNil()
+}
+
+// validateSubscriptionSecret checks that secret is long enough to sign with
+func validateSubscriptionSecret(secret string) error {
+	if secret == "" {
+		return fmt.Errorf("is required")
+	}
+	if len(secret) < MinSubscriptionSecretLength {
+		return fmt.Errorf("must be at least %d bytes", MinSubscriptionSecretLength)
+	}
+	return nil
+}
+
+// Subscription is a subscription registered with the service. Its secret is
+// never returned
+type Subscription struct {
+	ID        string          `json:"id"`
+	Topic     Topic           `json:"topic"`
+	Statuses  []MessageStatus `json:"statuses,omitempty"`
+	URL       string          `json:"url"`
+	CreatedAt time.Time       `json:"created_at"`
+	// SecretRotatedAt is when the secret was last rotated; zero if never
+	SecretRotatedAt time.Time `json:"secret_rotated_at"`
+	// PreviousSecretExpiresAt is when deliveries stop being signed with the
+	// secret replaced by the last rotation; zero when no previous secret is
+	// in use
+	PreviousSecretExpiresAt time.Time `json:"previous_secret_expires_at"`
+}
+
+// ListSubscriptionsResponse represents the response from listing subscriptions
+type ListSubscriptionsResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// rotateSecretRequest is the body of a secret rotation
+type rotateSecretRequest struct {
+	Secret        string `json:"secret"`
+	GracePeriodMs int64  `json:"grace_period_ms"`
+}
+
+// CreateSubscription registers a standing subscription with the service
+func (c *Client) CreateSubscription(ctx context.Context, config SubscriptionConfig) (*Subscription, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/subscriptions", config)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription Subscription
+	if err := c.parseResponse(resp, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// ListSubscriptions returns every subscription registered with the service
+func (c *Client) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/subscriptions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var listResp ListSubscriptionsResponse
+	if err := c.parseResponse(resp, &listResp); err != nil {
+		return nil, err
+	}
+
+	return listResp.Subscriptions, nil
+}
+
+// DeleteSubscription removes a subscription; events already being delivered
+// may still arrive
+func (c *Client) DeleteSubscription(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("subscription id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/subscriptions/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.parseResponse(resp, nil)
+}
+
+// RotateSubscriptionSecret replaces the secret deliveries of a subscription
+// are signed with. For grace, e.g. DefaultSecretGracePeriod, deliveries are
+// signed with both the old and the new secret, so receivers can switch over
+// without rejecting any; a zero grace stops using the old secret at once,
+// as after a leak
+func (c *Client) RotateSubscriptionSecret(ctx context.Context, id, secret string, grace time.Duration) (*Subscription, error) {
+	if id == "" {
+		return nil, fmt.Errorf("subscription id is required")
+	}
+	if err := validateSubscriptionSecret(secret); err != nil {
+		return nil, fmt.Errorf("secret %v", err)
+	}
+	if grace < 0 {
+		return nil, fmt.Errorf("grace period cannot be negative")
+	}
+
+	req := rotateSecretRequest{Secret: secret, GracePeriodMs: grace.Milliseconds()}
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/subscriptions/"+url.PathEscape(id)+"/secret", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription Subscription
+	if err := c.parseResponse(resp, &subscription); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
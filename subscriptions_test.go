@@ -0,0 +1,105 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSubscriptionSecret = "whsec-0123456789abcdef"
+
+func TestSubscriptions(t *testing.T) {
+	expires := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/subscriptions":
+			var config SubscriptionConfig
+			json.NewDecoder(r.Body).Decode(&config)
+			if config.Topic != "deployments" || len(config.Statuses) != 1 || config.Statuses[0] != StatusFailed ||
+				config.URL != "https://hooks.example.com/failed" || config.Secret != testSubscriptionSecret {
+				t.Errorf("Unexpected subscription request: %+v", config)
+			}
+			json.NewEncoder(w).Encode(Subscription{ID: "sub-1", Topic: config.Topic, Statuses: config.Statuses, URL: config.URL})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/subscriptions":
+			json.NewEncoder(w).Encode(ListSubscriptionsResponse{Subscriptions: []Subscription{{ID: "sub-1", Topic: "deployments"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/subscriptions/sub-1/secret":
+			var req rotateSecretRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Secret != "whsec-fedcba9876543210" || req.GracePeriodMs != DefaultSecretGracePeriod.Milliseconds() {
+				t.Errorf("Unexpected rotation request: %+v", req)
+			}
+			json.NewEncoder(w).Encode(Subscription{ID: "sub-1", PreviousSecretExpiresAt: expires})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/subscriptions/sub-1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	subscription, err := client.CreateSubscription(ctx, SubscriptionConfig{
+		Topic:    "deployments",
+		Statuses: []MessageStatus{StatusFailed},
+		URL:      "https://hooks.example.com/failed",
+		Secret:   testSubscriptionSecret,
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if subscription.ID != "sub-1" {
+		t.Errorf("Expected subscription 'sub-1', got %+v", subscription)
+	}
+
+	subscriptions, err := client.ListSubscriptions(ctx)
+	if err != nil || len(subscriptions) != 1 {
+		t.Fatalf("Expected one subscription, got %v, %v", subscriptions, err)
+	}
+
+	rotated, err := client.RotateSubscriptionSecret(ctx, "sub-1", "whsec-fedcba9876543210", DefaultSecretGracePeriod)
+	if err != nil {
+		t.Fatalf("RotateSubscriptionSecret failed: %v", err)
+	}
+	if !rotated.PreviousSecretExpiresAt.Equal(expires) {
+		t.Errorf("Expected the previous secret to expire at %v, got %v", expires, rotated.PreviousSecretExpiresAt)
+	}
+
+	if err := client.DeleteSubscription(ctx, "sub-1"); err != nil {
+		t.Errorf("DeleteSubscription failed: %v", err)
+	}
+}
+
+func TestSubscriptionValidation(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://localhost:0"})
+	ctx := context.Background()
+
+	_, err := client.CreateSubscription(ctx, SubscriptionConfig{Statuses: []MessageStatus{"exploded"}, URL: "ftp://example.com", Secret: "short"})
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	var fields []string
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	if strings.Join(fields, ",") != "topic,statuses[0],url,secret" {
+		t.Errorf("Expected violations for every field, got %v", verr)
+	}
+
+	if _, err := client.RotateSubscriptionSecret(ctx, "sub-1", "short", 0); err == nil {
+		t.Error("Expected a short secret to be rejected")
+	}
+	if _, err := client.RotateSubscriptionSecret(ctx, "sub-1", testSubscriptionSecret, -time.Second); err == nil {
+		t.Error("Expected a negative grace period to be rejected")
+	}
+	if err := client.DeleteSubscription(ctx, ""); err == nil {
+		t.Error("Expected an empty id to be rejected")
+	}
+}
@@ -0,0 +1,240 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTelemetryInterval is how often usage reports are sent
+const DefaultTelemetryInterval = 10 * time.Minute
+
+// TelemetryConfig enables anonymized SDK usage reporting. Reports contain only
+// operation names with identifiers stripped, call and error counts, and the
+// Go runtime platform; never payloads, item IDs, hostnames, or headers
+type TelemetryConfig struct {
+	// Endpoint receives reports as JSON POST requests
+	Endpoint string
+	// Interval is the time between reports; defaults to DefaultTelemetryInterval
+	Interval time.Duration
+	// Reporter, when set, receives reports instead of Endpoint
+	Reporter func(ctx context.Context, report TelemetryReport) error
+}
+
+// TelemetryOperation holds usage counts for a single SDK operation
+type TelemetryOperation struct {
+	Operation string         `json:"operation"`
+	Calls     int64          `json:"calls"`
+	Errors    int64          `json:"errors"`
+	Statuses  map[string]int `json:"statuses,omitempty"`
+}
+
+// TelemetryReport is a single anonymized usage report
+type TelemetryReport struct {
+	// InstanceID is random per process and not linked to the host
+	InstanceID string               `json:"instance_id"`
+	GoVersion  string               `json:"go_version"`
+	Platform   string               `json:"platform"`
+	Start      time.Time            `json:"start"`
+	End        time.Time            `json:"end"`
+	Operations []TelemetryOperation `json:"operations"`
+}
+
+// telemetry aggregates usage and reports it periodically
+type telemetry struct {
+	config     TelemetryConfig
+	httpClient *http.Client
+	instanceID string
+	clock      Clock
+
+	mu    sync.Mutex
+	start time.Time
+	ops   map[string]*TelemetryOperation
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newTelemetry starts the reporting loop; it returns nil when telemetry is disabled
+func newTelemetry(config *TelemetryConfig, clock Clock) *telemetry {
+	if config == nil || (config.Endpoint == "" && config.Reporter == nil) {
+		return nil
+	}
+
+	cfg := *config
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultTelemetryInterval
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	t := &telemetry{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		instanceID: hex.EncodeToString(id),
+		clock:      clock,
+		start:      clock.Now(),
+		ops:        make(map[string]*TelemetryOperation),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go t.loop()
+	return t
+}
+
+// record counts a call; a nil receiver ignores it
+func (t *telemetry) record(method, path string, resp *http.Response, err error) {
+	if t == nil {
+		return
+	}
+
+	name := method + " " + routeTemplate(path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	op, ok := t.ops[name]
+	if !ok {
+		op = &TelemetryOperation{Operation: name, Statuses: make(map[string]int)}
+		t.ops[name] = op
+	}
+
+	op.Calls++
+	if err != nil {
+		op.Errors++
+		op.Statuses["network"]++
+		return
+	}
+
+	class := fmt.Sprintf("%dxx", resp.StatusCode/100)
+	op.Statuses[class]++
+	if resp.StatusCode >= 400 {
+		op.Errors++
+	}
+}
+
+// loop sends reports every interval until closed
+func (t *telemetry) loop() {
+	defer close(t.done)
+
+	ticker := t.clock.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			t.flush(context.Background())
+		case <-t.stop:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			t.flush(ctx)
+			cancel()
+			return
+		}
+	}
+}
+
+// snapshot returns the current report and resets the counters
+func (t *telemetry) snapshot() TelemetryReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	report := TelemetryReport{
+		InstanceID: t.instanceID,
+		GoVersion:  runtime.Version(),
+		Platform:   runtime.GOOS + "/" + runtime.GOARCH,
+		Start:      t.start,
+		End:        now,
+	}
+	for _, op := range t.ops {
+		report.Operations = append(report.Operations, *op)
+	}
+	sort.Slice(report.Operations, func(i, j int) bool {
+		return report.Operations[i].Operation < report.Operations[j].Operation
+	})
+
+	t.start = now
+	t.ops = make(map[string]*TelemetryOperation)
+	return report
+}
+
+// flush sends the pending report, dropping it on failure; telemetry must
+// never affect the application
+func (t *telemetry) flush(ctx context.Context) {
+	report := t.snapshot()
+	if len(report.Operations) == 0 {
+		return
+	}
+
+	if t.config.Reporter != nil {
+		t.config.Reporter(ctx, report)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", DefaultUserAgent)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// close sends a final report and stops the loop; a nil receiver is a no-op
+func (t *telemetry) close() {
+	if t == nil {
+		return
+	}
+
+	t.once.Do(func() {
+		close(t.stop)
+		<-t.done
+	})
+}
+
+// routeSegments are the literal path segments of the service API; any other
+// segment is an identifier and is replaced so reports stay anonymous
+var routeSegments = map[string]bool{
+	"api": true, "v1": true, "v2": true, "health": true, "ready": true, "live": true, "info": true,
+	"messages": true, "bulk": true, "stream": true, "events": true, "duplicates": true, "reprioritize": true, "stats": true,
+	"callbacks": true, "redeliver": true, "pull": true, "ack": true, "nack": true, "visibility": true,
+	"workers": true, "status": true, "scale": true, "remove-all": true,
+	"pause": true, "resume": true, "restart": true, "drain": true,
+	"queues": true, "depth": true, "history": true, "purge": true,
+	"topics": true, "operations": true, "maintenance-windows": true,
+	"low": true, "medium": true, "high": true,
+}
+
+// routeTemplate strips the query and replaces identifier segments with {id}
+func routeTemplate(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && !routeSegments[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,71 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/messages/msg-123":            "/api/v1/messages/{id}",
+		"/api/v1/workers/scale/high?count=2":  "/api/v1/workers/scale/high",
+		"/api/v1/topics/secret-project/purge": "/api/v1/topics/{id}/purge",
+		"/api/v1/workers/status":              "/api/v1/workers/status",
+		"/api/v1/maintenance-windows/mw-1":    "/api/v1/maintenance-windows/{id}",
+	}
+
+	for path, expected := range tests {
+		if got := routeTemplate(path); got != expected {
+			t.Errorf("routeTemplate(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestTelemetryReportsOnClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/messages/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"msg-1","status":"completed"}`))
+	}))
+	defer server.Close()
+
+	var reports []TelemetryReport
+	client := NewClient(&Config{
+		BaseURL: server.URL,
+		Telemetry: &TelemetryConfig{
+			Interval: time.Hour,
+			Reporter: func(ctx context.Context, report TelemetryReport) error {
+				reports = append(reports, report)
+				return nil
+			},
+		},
+	})
+
+	ctx := context.Background()
+	client.GetMessage(ctx, "msg-1")
+	client.GetMessage(ctx, "missing")
+	client.Close()
+
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(reports))
+	}
+
+	ops := reports[0].Operations
+	if len(ops) != 1 || ops[0].Operation != "GET /api/v1/messages/{id}" {
+		t.Fatalf("Unexpected operations: %+v", ops)
+	}
+	if ops[0].Calls != 2 || ops[0].Errors != 1 || ops[0].Statuses["4xx"] != 1 {
+		t.Errorf("Unexpected counts: %+v", ops[0])
+	}
+}
+
+func TestTelemetryDisabledByDefault(t *testing.T) {
+	if NewClientWithDefaults().telemetry != nil {
+		t.Error("Expected telemetry to be disabled unless configured")
+	}
+}
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// TenantHeader carries the tenant of every request made by a client with a
+// tenant, and of the service's responses to it
+const TenantHeader = "X-Tenant"
+
+// ErrTenantMismatch is returned when the service answers a tenant-scoped
+// request with another tenant's data
+var ErrTenantMismatch = errors.New("response belongs to another tenant")
+
+// validateTenant checks that tenant can be sent in TenantHeader
+func validateTenant(tenant string) error {
+	if strings.TrimSpace(tenant) == "" {
+		return fmt.Errorf("tenant cannot be blank")
+	}
+	if strings.IndexFunc(tenant, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return fmt.Errorf("tenant cannot contain whitespace or control characters")
+	}
+	return nil
+}
+
+// Tenant returns the tenant the client's requests are scoped to, or an
+// empty string
+func (c *Client) Tenant() string {
+	return c.tenant
+}
+
+// ForTenant returns a client scoped to tenant that shares c's connections,
+// configuration, and topic defaults as of the call. Derived clients keep
+// their own worker status cache, backpressure state, and asynchronous
+// submission queue, so one process can serve several tenants without their
+// data mixing. An invalid tenant makes every request fail rather than go out
+// unscoped. Closing a derived client waits for its asynchronous submissions
+// but leaves c's connections open
+func (c *Client) ForTenant(tenant string) *Client {
+	c.topicDefaultsMu.RLock()
+	topicDefaults := maps.Clone(c.topicDefaults)
+	c.topicDefaultsMu.RUnlock()
+
+	derived := &Client{
+		baseURL:              c.baseURL,
+		httpClient:           c.httpClient,
+		streamClient:         c.streamClient,
+		timeout:              c.timeout,
+		healthCheckTimeout:   c.healthCheckTimeout,
+		compressor:           c.compressor,
+		compressionThreshold: c.compressionThreshold,
+		codec:                c.codec,
+		hooks:                c.hooks,
+		maxResponseSize:      c.maxResponseSize,
+		decoding:             c.decoding,
+		logger:               c.logger,
+		clock:                c.clock,
+		payloadStore:         c.payloadStore,
+		encrypter:            c.encrypter,
+		itemIDGenerator:      c.itemIDGenerator,
+		itemIDPrefix:         c.itemIDPrefix,
+		dedupWindow:          c.dedupWindow,
+		dedupCache:           c.dedupCache,
+		callbackConfig:       c.callbackConfig,
+		callbacks:            c.callbacks,
+		userAgent:            c.userAgent,
+		team:                 c.team,
+		costCenter:           c.costCenter,
+		tenant:               tenant,
+		derived:              true,
+
+		topicDefaults: topicDefaults,
+
+		apiVersion:      c.apiVersion,
+		version:         c.version,
+		errorTranslator: c.errorTranslator,
+		telemetry:       c.telemetry,
+		transport:       c.transport,
+		backpressure:    c.backpressure.fork(),
+		statusCache:     c.statusCache.fork(),
+
+		asyncWorkers:   c.asyncWorkers,
+		asyncQueueSize: c.asyncQueueSize,
+	}
+	derived.codecRejected.Store(c.codecRejected.Load())
+	return derived
+}
+
+// setTenantHeader scopes req to the client's tenant
+func (c *Client) setTenantHeader(req *http.Request) error {
+	if c.tenant == "" {
+		return nil
+	}
+	if err := validateTenant(c.tenant); err != nil {
+		return err
+	}
+	req.Header.Set(TenantHeader, c.tenant)
+	return nil
+}
+
+// checkTenant fails a response whose tenant differs from its request's
+func checkTenant(resp *http.Response) error {
+	if resp.Request == nil {
+		return nil
+	}
+	requested := resp.Request.Header.Get(TenantHeader)
+	answered := resp.Header.Get(TenantHeader)
+	if requested == "" || answered == "" || requested == answered {
+		return nil
+	}
+	return fmt.Errorf("%w: requested %s, got %s", ErrTenantMismatch, requested, answered)
+}
@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForTenant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get(TenantHeader)
+		if tenant != "" {
+			w.Header().Set(TenantHeader, tenant)
+		}
+		w.Write([]byte(`{"id":"msg-1","status":"queued","tenant":"` + tenant + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if err := client.SetTopicDefaults(TopicPullRequests, TopicDefaults{Priority: PriorityHigh}); err != nil {
+		t.Fatal(err)
+	}
+	teamA := client.ForTenant("team-a")
+	teamB := client.ForTenant("team-b")
+	ctx := context.Background()
+
+	if teamA.Tenant() != "team-a" || client.Tenant() != "" {
+		t.Errorf("Expected only the derived client to be scoped, got %q and %q", teamA.Tenant(), client.Tenant())
+	}
+	if teamA.TopicDefaults(TopicPullRequests).Priority != PriorityHigh {
+		t.Error("Expected derived clients to inherit topic defaults")
+	}
+
+	for tenant, c := range map[string]*Client{"team-a": teamA, "team-b": teamB, "": client} {
+		resp, err := c.PostMessageWithDefaults(ctx, "pr-1", "https://example.com/cb", nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if resp.Tenant != tenant {
+			t.Errorf("Expected tenant %q, got %q", tenant, resp.Tenant)
+		}
+	}
+
+	// Closing a derived client leaves the parent usable
+	if err := teamA.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.PostMessageWithDefaults(ctx, "pr-2", "https://example.com/cb", nil); err != nil {
+		t.Errorf("Expected the parent to keep working, got %v", err)
+	}
+}
+
+func TestTenantMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(TenantHeader, "team-b")
+		w.Write([]byte(`{"total_workers":3}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL, Tenant: "team-a"})
+	if _, err := client.GetWorkerStatus(context.Background()); !errors.Is(err, ErrTenantMismatch) {
+		t.Errorf("Expected ErrTenantMismatch, got %v", err)
+	}
+}
+
+func TestInvalidTenant(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	if err := (&Config{BaseURL: server.URL, Tenant: "team a"}).Validate(); err == nil {
+		t.Error("Expected Validate to reject a tenant with whitespace")
+	}
+
+	client := NewClient(&Config{BaseURL: server.URL}).ForTenant(" ")
+	if _, err := client.GetWorkerStatus(context.Background()); err == nil {
+		t.Error("Expected requests with a blank tenant to fail")
+	}
+	if calls != 0 {
+		t.Errorf("Expected no request to be sent unscoped, got %d", calls)
+	}
+}
@@ -0,0 +1,285 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults for callback-health throttling
+const (
+	DefaultThrottleWindow        = time.Minute
+	DefaultThrottleMinSamples    = 20
+	DefaultThrottleSlowdownRate  = 0.2
+	DefaultThrottlePauseRate     = 0.5
+	DefaultThrottleMaxDelay      = 5 * time.Second
+	DefaultThrottleCheckInterval = time.Second
+)
+
+// throttleBuckets is the number of slots the failure-rate window is split into
+const throttleBuckets = 10
+
+// ThrottleState describes how submission to a topic is currently limited
+type ThrottleState string
+
+// Throttle states
+const (
+	// ThrottleOpen submits without delay
+	ThrottleOpen ThrottleState = "open"
+	// ThrottleSlowed delays each submission in proportion to the failure rate
+	ThrottleSlowed ThrottleState = "slowed"
+	// ThrottlePaused holds submissions until the failure rate recovers
+	ThrottlePaused ThrottleState = "paused"
+)
+
+// ThrottleOptions controls when a CallbackThrottle slows or pauses a topic
+type ThrottleOptions struct {
+	// Window is how far back callback outcomes are considered; defaults to
+	// DefaultThrottleWindow. Failures older than the window no longer count,
+	// so a paused topic resumes once its outage has aged out
+	Window time.Duration
+	// MinSamples is the number of outcomes in the window below which a topic
+	// is never throttled; defaults to DefaultThrottleMinSamples
+	MinSamples int
+	// SlowdownRate is the failure rate at which submissions start to be
+	// delayed; defaults to DefaultThrottleSlowdownRate
+	SlowdownRate float64
+	// PauseRate is the failure rate at which submissions are held entirely;
+	// defaults to DefaultThrottlePauseRate
+	PauseRate float64
+	// MaxDelay is the delay applied just below PauseRate; defaults to
+	// DefaultThrottleMaxDelay
+	MaxDelay time.Duration
+	// CheckInterval is how often a paused submission rechecks the topic;
+	// defaults to DefaultThrottleCheckInterval
+	CheckInterval time.Duration
+}
+
+// withDefaults fills unset throttle options
+func (o ThrottleOptions) withDefaults() ThrottleOptions {
+	if o.Window <= 0 {
+		o.Window = DefaultThrottleWindow
+	}
+	if o.MinSamples <= 0 {
+		o.MinSamples = DefaultThrottleMinSamples
+	}
+	if o.SlowdownRate <= 0 {
+		o.SlowdownRate = DefaultThrottleSlowdownRate
+	}
+	if o.PauseRate <= 0 {
+		o.PauseRate = DefaultThrottlePauseRate
+	}
+	if o.PauseRate < o.SlowdownRate {
+		o.PauseRate = o.SlowdownRate
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = DefaultThrottleMaxDelay
+	}
+	if o.CheckInterval <= 0 {
+		o.CheckInterval = DefaultThrottleCheckInterval
+	}
+	return o
+}
+
+// throttleBucket counts callback outcomes during one slot of the window
+type throttleBucket struct {
+	slot      int64
+	successes int
+	failures  int
+}
+
+// CallbackThrottle slows or pauses submission to topics whose callback
+// consumers are failing, so that messages do not pile up in the dead-letter
+// queue during a downstream outage. It learns callback outcomes from the
+// message event stream (Watch), from polled message details
+// (ObserveMessage), or from the caller directly (Record)
+type CallbackThrottle struct {
+	client *Client
+	opts   ThrottleOptions
+	now    func() time.Time
+
+	mu     sync.Mutex
+	topics map[Topic]*[throttleBuckets]throttleBucket
+}
+
+// NewCallbackThrottle creates a throttle that submits through client
+func NewCallbackThrottle(client *Client, opts ThrottleOptions) *CallbackThrottle {
+	return &CallbackThrottle{
+		client: client,
+		opts:   opts.withDefaults(),
+		now:    client.clock.Now,
+		topics: make(map[Topic]*[throttleBuckets]throttleBucket),
+	}
+}
+
+// Record adds a callback outcome for topic
+func (t *CallbackThrottle) Record(topic Topic, success bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buckets, ok := t.topics[topic]
+	if !ok {
+		buckets = new([throttleBuckets]throttleBucket)
+		t.topics[topic] = buckets
+	}
+
+	slot := t.slot()
+	b := &buckets[slot%throttleBuckets]
+	if b.slot != slot {
+		*b = throttleBucket{slot: slot}
+	}
+	if success {
+		b.successes++
+	} else {
+		b.failures++
+	}
+}
+
+// ObserveEvent records the outcome carried by a message event; events that
+// are not a completion, failure, or timeout are ignored
+func (t *CallbackThrottle) ObserveEvent(event MessageEvent) {
+	switch {
+	case event.TimedOut(), event.Type == MessageEventFailed:
+		t.Record(event.Topic, false)
+	case event.Type == MessageEventCompleted:
+		t.Record(event.Topic, true)
+	}
+}
+
+// ObserveMessage records the outcome of a message in a terminal state, e.g.
+// one returned by WaitForMessage; other states and expired messages are
+// ignored
+func (t *CallbackThrottle) ObserveMessage(detail *MessageDetail) {
+	if detail == nil || !detail.IsTerminal() || detail.Expired() {
+		// Expired messages never reached the callback
+		return
+	}
+	t.Record(detail.Topic, detail.Status == StatusCompleted)
+}
+
+// Watch subscribes to the message event stream and records callback outcomes
+// in the background until ctx is done
+func (t *CallbackThrottle) Watch(ctx context.Context) error {
+	events, err := t.client.SubscribeMessageEvents(ctx, MessageEventFilter{
+		Types: []string{MessageEventCompleted, MessageEventFailed, MessageEventTimedOut},
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for event := range events {
+			t.ObserveEvent(event)
+		}
+	}()
+	return nil
+}
+
+// FailureRate returns the fraction of failed callbacks for topic within the
+// window and the number of outcomes it is based on
+func (t *CallbackThrottle) FailureRate(topic Topic) (float64, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buckets, ok := t.topics[topic]
+	if !ok {
+		return 0, 0
+	}
+
+	oldest := t.slot() - throttleBuckets + 1
+	successes, failures := 0, 0
+	for _, b := range buckets {
+		if b.slot >= oldest {
+			successes += b.successes
+			failures += b.failures
+		}
+	}
+
+	total := successes + failures
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(failures) / float64(total), total
+}
+
+// State returns how submission to topic is currently limited and the delay
+// applied to each submission while it is slowed
+func (t *CallbackThrottle) State(topic Topic) (ThrottleState, time.Duration) {
+	rate, samples := t.FailureRate(topic)
+	switch {
+	case samples < t.opts.MinSamples || rate < t.opts.SlowdownRate:
+		return ThrottleOpen, 0
+	case rate >= t.opts.PauseRate:
+		return ThrottlePaused, 0
+	}
+
+	// Scale the delay linearly between the slowdown and pause rates
+	fraction := (rate - t.opts.SlowdownRate) / (t.opts.PauseRate - t.opts.SlowdownRate)
+	return ThrottleSlowed, time.Duration(fraction * float64(t.opts.MaxDelay))
+}
+
+// Wait blocks until a message may be submitted to topic: immediately when
+// the topic is healthy, after a delay when it is slowed, and until the
+// failure rate recovers when it is paused. It returns ctx's error if ctx
+// ends first
+func (t *CallbackThrottle) Wait(ctx context.Context, topic Topic) error {
+	for {
+		state, delay := t.State(topic)
+		switch state {
+		case ThrottleOpen:
+			return ctx.Err()
+		case ThrottlePaused:
+			delay = t.opts.CheckInterval
+		}
+
+		if err := sleep(ctx, t.client.clock, delay); err != nil {
+			return err
+		}
+
+		if state == ThrottleSlowed {
+			return nil
+		}
+	}
+}
+
+// PostMessage waits for the message's topic to accept submissions and posts it
+func (t *CallbackThrottle) PostMessage(ctx context.Context, req *MessageRequest) (*MessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("message request cannot be nil")
+	}
+
+	if err := t.Wait(ctx, req.Topic); err != nil {
+		return nil, err
+	}
+	return t.client.PostMessage(ctx, req)
+}
+
+// PostBulkMessages waits for every topic in req to accept submissions and
+// posts the messages in a single request
+func (t *CallbackThrottle) PostBulkMessages(ctx context.Context, req *BulkMessageRequest) (*BulkMessageResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("bulk message request cannot be nil")
+	}
+
+	seen := make(map[Topic]bool)
+	for _, msg := range req.Messages {
+		if seen[msg.Topic] {
+			continue
+		}
+		seen[msg.Topic] = true
+		if err := t.Wait(ctx, msg.Topic); err != nil {
+			return nil, err
+		}
+	}
+	return t.client.PostBulkMessages(ctx, req)
+}
+
+// slot returns the index of the current window slot
+func (t *CallbackThrottle) slot() int64 {
+	width := t.opts.Window / throttleBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return t.now().UnixNano() / int64(width)
+}
@@ -0,0 +1,103 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallbackThrottle(t *testing.T) {
+	var posted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted.Add(1)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	throttle := NewCallbackThrottle(client, ThrottleOptions{
+		Window:        time.Minute,
+		MinSamples:    10,
+		SlowdownRate:  0.2,
+		PauseRate:     0.6,
+		MaxDelay:      40 * time.Millisecond,
+		CheckInterval: 5 * time.Millisecond,
+	})
+	now := time.Unix(1700000000, 0)
+	var clock atomic.Int64
+	clock.Store(now.UnixNano())
+	throttle.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	// Too few samples never throttle
+	for i := 0; i < 5; i++ {
+		throttle.Record(TopicPullRequests, false)
+	}
+	if state, _ := throttle.State(TopicPullRequests); state != ThrottleOpen {
+		t.Errorf("Expected open below MinSamples, got %s", state)
+	}
+
+	// 6 failures out of 15 (40%) is halfway between the slowdown and pause rates
+	for i := 0; i < 10; i++ {
+		throttle.ObserveEvent(MessageEvent{Type: MessageEventCompleted, Topic: TopicPullRequests})
+	}
+	throttle.ObserveEvent(MessageEvent{Type: MessageEventTimedOut, Topic: TopicPullRequests})
+	throttle.ObserveEvent(MessageEvent{Type: MessageEventCallbackDelivered, Topic: TopicPullRequests})
+	rate, samples := throttle.FailureRate(TopicPullRequests)
+	if samples != 16 || rate != 6.0/16 {
+		t.Fatalf("Expected 6/16 failures, got %v of %d", rate, samples)
+	}
+	state, delay := throttle.State(TopicPullRequests)
+	if state != ThrottleSlowed || delay <= 0 || delay >= 40*time.Millisecond {
+		t.Errorf("Expected slowed state with partial delay, got %s %v", state, delay)
+	}
+
+	// Other topics are unaffected
+	if state, _ := throttle.State("deployments"); state != ThrottleOpen {
+		t.Errorf("Expected other topic open, got %s", state)
+	}
+
+	// Pausing holds submissions until ctx ends
+	for i := 0; i < 10; i++ {
+		throttle.ObserveMessage(&MessageDetail{Topic: TopicPullRequests, Status: "dead_lettered"})
+	}
+	throttle.ObserveMessage(&MessageDetail{Topic: TopicPullRequests, Status: "processing"})
+	if state, _ := throttle.State(TopicPullRequests); state != ThrottlePaused {
+		t.Fatalf("Expected paused, got %s", state)
+	}
+
+	req := newMessageRequest("pr-1", PriorityHigh, TopicPullRequests, "https://example.com/callback", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := throttle.PostMessage(ctx, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected paused submission to wait for ctx, got %v", err)
+	}
+	if posted.Load() != 0 {
+		t.Errorf("Expected no submissions while paused, got %d", posted.Load())
+	}
+
+	// Once the failures age out of the window the topic resumes
+	done := make(chan error, 1)
+	go func() {
+		_, err := throttle.PostMessage(context.Background(), req)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	clock.Store(now.Add(2 * time.Minute).UnixNano())
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected paused submission to resume")
+	}
+	if posted.Load() != 1 {
+		t.Errorf("Expected one submission, got %d", posted.Load())
+	}
+}
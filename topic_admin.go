@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// TopicConfig is the service-side configuration of a topic
+type TopicConfig struct {
+	Name Topic `json:"name"`
+	// RetryPolicy applies to messages of the topic that do not carry their
+	// own; nil uses the service default
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// DLQEnabled moves messages that exhausted their retries to the topic's
+	// dead-letter queue instead of dropping them
+	DLQEnabled bool `json:"dlq_enabled"`
+}
+
+// Validate checks the topic configuration without contacting the service
+func (t *TopicConfig) Validate() error {
+	verr := &ValidationError{}
+	if err := validateTopicName(t.Name); err != nil {
+		verr.add("name", "%v", err)
+	}
+	if t.RetryPolicy != nil {
+		t.RetryPolicy.validate("retry_policy.", verr)
+	}
+	return verr.errOrNil()
+}
+
+// ListTopicsResponse represents the response from listing topics
+type ListTopicsResponse struct {
+	Topics []TopicConfig `json:"topics"`
+}
+
+// validateTopicName checks that name can be used in a topic's path
+func validateTopicName(name Topic) error {
+	if name == "" {
+		return fmt.Errorf("is required")
+	}
+	if strings.IndexFunc(string(name), func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return fmt.Errorf("cannot contain whitespace or control characters")
+	}
+	return nil
+}
+
+// CreateTopic creates a topic on the service. Creating a topic that already
+// exists fails with a 409 *APIError
+func (c *Client) CreateTopic(ctx context.Context, config TopicConfig) (*TopicConfig, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/topics", config)
+	if err != nil {
+		return nil, err
+	}
+
+	var created TopicConfig
+	if err := c.parseResponse(resp, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// GetTopic returns the configuration of a topic
+func (c *Client) GetTopic(ctx context.Context, name Topic) (*TopicConfig, error) {
+	if err := validateTopicName(name); err != nil {
+		return nil, fmt.Errorf("topic name %v", err)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/topics/"+url.PathEscape(string(name)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var topic TopicConfig
+	if err := c.parseResponse(resp, &topic); err != nil {
+		return nil, err
+	}
+
+	return &topic, nil
+}
+
+// ListTopics returns the configuration of every topic on the service
+func (c *Client) ListTopics(ctx context.Context) ([]TopicConfig, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/topics", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var listResp ListTopicsResponse
+	if err := c.parseResponse(resp, &listResp); err != nil {
+		return nil, err
+	}
+
+	return listResp.Topics, nil
+}
+
+// UpdateTopic replaces the configuration of an existing topic with config
+func (c *Client) UpdateTopic(ctx context.Context, config TopicConfig) (*TopicConfig, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPut, "/api/v1/topics/"+url.PathEscape(string(config.Name)), config)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated TopicConfig
+	if err := c.parseResponse(resp, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteTopic deletes a topic from the service
+func (c *Client) DeleteTopic(ctx context.Context, name Topic) error {
+	if err := validateTopicName(name); err != nil {
+		return fmt.Errorf("topic name %v", err)
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodDelete, "/api/v1/topics/"+url.PathEscape(string(name)), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.parseResponse(resp, nil)
+}
@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTopicAdmin(t *testing.T) {
+	stored := map[Topic]TopicConfig{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := Topic(r.URL.Path[len("/api/v1/topics"):])
+		if name != "" {
+			name = name[1:]
+		}
+
+		switch {
+		case r.Method == http.MethodPost && name == "":
+			var config TopicConfig
+			json.NewDecoder(r.Body).Decode(&config)
+			if _, ok := stored[config.Name]; ok {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":"topic exists"}`))
+				return
+			}
+			stored[config.Name] = config
+			json.NewEncoder(w).Encode(config)
+		case r.Method == http.MethodGet && name == "":
+			var list ListTopicsResponse
+			for _, config := range stored {
+				list.Topics = append(list.Topics, config)
+			}
+			json.NewEncoder(w).Encode(list)
+		case r.Method == http.MethodPut:
+			var config TopicConfig
+			json.NewDecoder(r.Body).Decode(&config)
+			if config.Name != name {
+				t.Errorf("Expected body name %s to match path %s", config.Name, name)
+			}
+			stored[name] = config
+			json.NewEncoder(w).Encode(config)
+		case r.Method == http.MethodGet:
+			config, ok := stored[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(config)
+		case r.Method == http.MethodDelete:
+			delete(stored, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	ctx := context.Background()
+
+	created, err := client.CreateTopic(ctx, TopicConfig{
+		Name:        "deployments",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 3, Backoff: BackoffFixed, InitialDelay: time.Second, MaxDelay: time.Second},
+		DLQEnabled:  true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.RetryPolicy == nil || created.RetryPolicy.MaxAttempts != 3 || !created.DLQEnabled {
+		t.Errorf("Expected the created configuration back, got %+v", created)
+	}
+
+	if _, err := client.CreateTopic(ctx, TopicConfig{Name: "deployments"}); !IsAPIError(err) {
+		t.Errorf("Expected an API error creating an existing topic, got %v", err)
+	}
+
+	if _, err := client.UpdateTopic(ctx, TopicConfig{Name: "deployments"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	topic, err := client.GetTopic(ctx, "deployments")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if topic.DLQEnabled || topic.RetryPolicy != nil {
+		t.Errorf("Expected the configuration to be replaced, got %+v", topic)
+	}
+
+	topics, err := client.ListTopics(ctx)
+	if err != nil || len(topics) != 1 || topics[0].Name != "deployments" {
+		t.Errorf("Expected one listed topic, got %+v, %v", topics, err)
+	}
+
+	if err := client.DeleteTopic(ctx, "deployments"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("Expected the topic to be deleted, got %+v", stored)
+	}
+}
+
+func TestTopicConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TopicConfig
+	}{
+		{"missing name", TopicConfig{}},
+		{"whitespace in name", TopicConfig{Name: "pull requests"}},
+		{"invalid retry policy", TopicConfig{Name: "deployments", RetryPolicy: &RetryPolicy{MaxAttempts: MaxRetryAttempts + 1}}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.config.Validate(); !IsValidationError(err) {
+			t.Errorf("%s: expected validation error, got %v", tt.name, err)
+		}
+	}
+
+	client := NewClient(&Config{BaseURL: "http://example.com"})
+	if err := client.DeleteTopic(context.Background(), ""); err == nil {
+		t.Error("Expected error deleting a topic without a name")
+	}
+}
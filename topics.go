@@ -0,0 +1,216 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BodyValidator validates the ObjectBody of messages sent to a topic
+type BodyValidator interface {
+	ValidateBody(body interface{}) error
+}
+
+// BodyValidatorFunc adapts a function to the BodyValidator interface
+type BodyValidatorFunc func(body interface{}) error
+
+// ValidateBody calls f(body)
+func (f BodyValidatorFunc) ValidateBody(body interface{}) error {
+	return f(body)
+}
+
+var (
+	topicsMu sync.RWMutex
+	topics   = map[Topic]BodyValidator{
+		TopicPullRequests: nil,
+	}
+)
+
+// RegisterTopic registers a topic with an optional ObjectBody validator.
+// Messages for unregistered topics are still accepted; registration only adds
+// body validation and makes the topic visible through RegisteredTopics
+func RegisterTopic(topic Topic, validator BodyValidator) error {
+	if strings.TrimSpace(string(topic)) == "" {
+		return fmt.Errorf("topic name is required")
+	}
+
+	topicsMu.Lock()
+	defer topicsMu.Unlock()
+	topics[topic] = validator
+	return nil
+}
+
+// RegisteredTopics returns all registered topics sorted by name
+func RegisteredTopics() []Topic {
+	topicsMu.RLock()
+	defer topicsMu.RUnlock()
+
+	result := make([]Topic, 0, len(topics))
+	for topic := range topics {
+		result = append(result, topic)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// topicValidator returns the body validator registered for topic, if any
+func topicValidator(topic Topic) BodyValidator {
+	topicsMu.RLock()
+	defer topicsMu.RUnlock()
+	return topics[topic]
+}
+
+// StructValidator returns a BodyValidator that requires ObjectBody to decode
+// into the type of prototype without unknown fields, and that every field
+// tagged `validate:"required"` is non-zero
+func StructValidator(prototype interface{}) BodyValidator {
+	typ := reflect.TypeOf(prototype)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return BodyValidatorFunc(func(body interface{}) error {
+		if body == nil {
+			return fmt.Errorf("body is required")
+		}
+
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("cannot be marshaled: %v", err)
+		}
+
+		target := reflect.New(typ)
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(target.Interface()); err != nil {
+			return fmt.Errorf("does not match %s: %v", typ.Name(), err)
+		}
+
+		if typ.Kind() != reflect.Struct {
+			return nil
+		}
+
+		value := target.Elem()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Tag.Get("validate") != "required" {
+				continue
+			}
+			if value.Field(i).IsZero() {
+				return fmt.Errorf("field %s is required", jsonFieldName(field))
+			}
+		}
+
+		return nil
+	})
+}
+
+// jsonFieldName returns the JSON name of a struct field
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// TopicDefaults holds values applied to messages for a topic when the
+// corresponding request fields are left empty
+type TopicDefaults struct {
+	Priority          Priority
+	CallbackURL       string
+	ProcessingTimeout time.Duration
+	Budget            time.Duration
+	TTL               time.Duration
+	RetryPolicy       *RetryPolicy
+}
+
+// SetTopicDefaults sets the defaults applied to messages submitted to topic,
+// replacing any previous defaults for it. A zero TopicDefaults clears them
+func (c *Client) SetTopicDefaults(topic Topic, defaults TopicDefaults) error {
+	if strings.TrimSpace(string(topic)) == "" {
+		return fmt.Errorf("topic name is required")
+	}
+	if defaults.Priority != "" && !defaults.Priority.IsValid() {
+		return fmt.Errorf("default priority must be 'low', 'medium', or 'high', got '%s'", defaults.Priority)
+	}
+	if defaults.CallbackURL != "" {
+		if err := validateCallbackURL(defaults.CallbackURL); err != nil {
+			return fmt.Errorf("default callback_url %v", err)
+		}
+	}
+	if defaults.RetryPolicy != nil {
+		verr := &ValidationError{}
+		defaults.RetryPolicy.validate("retry_policy.", verr)
+		if err := verr.errOrNil(); err != nil {
+			return fmt.Errorf("default %v", err)
+		}
+	}
+
+	c.topicDefaultsMu.Lock()
+	defer c.topicDefaultsMu.Unlock()
+
+	if defaults == (TopicDefaults{}) {
+		delete(c.topicDefaults, topic)
+		return nil
+	}
+	if c.topicDefaults == nil {
+		c.topicDefaults = make(map[Topic]TopicDefaults)
+	}
+	c.topicDefaults[topic] = defaults
+	return nil
+}
+
+// TopicDefaults returns the defaults configured for topic
+func (c *Client) TopicDefaults(topic Topic) TopicDefaults {
+	c.topicDefaultsMu.RLock()
+	defer c.topicDefaultsMu.RUnlock()
+	return c.topicDefaults[topic]
+}
+
+// TopicClient submits messages to a single topic
+type TopicClient struct {
+	client *Client
+	topic  Topic
+}
+
+// Topic returns a sub-client whose convenience methods send to the given topic
+func (c *Client) Topic(topic Topic) *TopicClient {
+	return &TopicClient{client: c, topic: topic}
+}
+
+// Name returns the topic this sub-client sends to
+func (t *TopicClient) Name() Topic {
+	return t.topic
+}
+
+// PostMessage submits a message with the given priority to the topic
+func (t *TopicClient) PostMessage(ctx context.Context, priority Priority, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return t.client.PostMessage(ctx, newMessageRequest(itemID, priority, t.topic, callbackURL, objectBody))
+}
+
+// PostMessageWithDefaults submits a message with the topic's default
+// priority, or medium priority when the topic has none
+func (t *TopicClient) PostMessageWithDefaults(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	priority := t.client.TopicDefaults(t.topic).Priority
+	if priority == "" {
+		priority = PriorityMedium
+	}
+	return t.PostMessage(ctx, priority, itemID, callbackURL, objectBody)
+}
+
+// PostHighPriorityMessage submits a high priority message to the topic
+func (t *TopicClient) PostHighPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return t.PostMessage(ctx, PriorityHigh, itemID, callbackURL, objectBody)
+}
+
+// PostLowPriorityMessage submits a low priority message to the topic
+func (t *TopicClient) PostLowPriorityMessage(ctx context.Context, itemID string, callbackURL string, objectBody interface{}) (*MessageResponse, error) {
+	return t.PostMessage(ctx, PriorityLow, itemID, callbackURL, objectBody)
+}
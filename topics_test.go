@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type deploymentBody struct {
+	Service string `json:"service" validate:"required"`
+	Version string `json:"version"`
+}
+
+func TestRegisterTopicValidation(t *testing.T) {
+	topic := Topic("deployments-test")
+	if err := RegisterTopic(topic, StructValidator(deploymentBody{})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	req := &MessageRequest{
+		ItemID:      "deploy-1",
+		Priority:    PriorityHigh,
+		Topic:       topic,
+		CallbackURL: "https://example.com/callback",
+		ObjectBody:  map[string]interface{}{"version": "1.2.3"},
+	}
+	if err := req.Validate(); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for missing service, got %v", err)
+	}
+
+	req.ObjectBody = map[string]interface{}{"service": "api", "region": "eu"}
+	if err := req.Validate(); !IsValidationError(err) {
+		t.Errorf("Expected ValidationError for unknown field, got %v", err)
+	}
+
+	req.ObjectBody = deploymentBody{Service: "api", Version: "1.2.3"}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected valid body, got %v", err)
+	}
+
+	if err := RegisterTopic("", nil); err == nil {
+		t.Error("Expected error for empty topic name, got nil")
+	}
+}
+
+func TestTopicClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		if req.Topic != "deployments" {
+			t.Errorf("Expected topic 'deployments', got '%s'", req.Topic)
+		}
+		if req.Priority != PriorityHigh {
+			t.Errorf("Expected priority 'high', got '%s'", req.Priority)
+		}
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1", Topic: req.Topic})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	resp, err := client.Topic("deployments").PostHighPriorityMessage(context.Background(), "deploy-1", "https://example.com/callback", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Topic != "deployments" {
+		t.Errorf("Expected topic 'deployments', got '%s'", resp.Topic)
+	}
+}
+
+func TestTopicDefaults(t *testing.T) {
+	var received []MessageRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req MessageRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received = append(received, req)
+		json.NewEncoder(w).Encode(MessageResponse{ID: "msg-1"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	err := client.SetTopicDefaults("deployments", TopicDefaults{
+		Priority:          PriorityHigh,
+		CallbackURL:       "https://example.com/deployments",
+		ProcessingTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.PostMessage(ctx, &MessageRequest{ItemID: "d-1", Topic: "deployments"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.PostMessage(ctx, &MessageRequest{ItemID: "d-2", Topic: "deployments", Priority: PriorityLow}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Topic("deployments").PostMessageWithDefaults(ctx, "d-3", "", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received[0].Priority != PriorityHigh || received[0].CallbackURL != "https://example.com/deployments" || received[0].ProcessingTimeout != 5*time.Second {
+		t.Errorf("Expected topic defaults to be applied, got %+v", received[0])
+	}
+	if received[1].Priority != PriorityLow {
+		t.Errorf("Expected explicit priority to win, got '%s'", received[1].Priority)
+	}
+	if received[2].Priority != PriorityHigh {
+		t.Errorf("Expected default priority for PostMessageWithDefaults, got '%s'", received[2].Priority)
+	}
+
+	// Other topics are unaffected
+	if _, err := client.PostMessage(ctx, &MessageRequest{ItemID: "pr-1", Topic: TopicPullRequests}); !IsValidationError(err) {
+		t.Errorf("Expected validation error without defaults, got %v", err)
+	}
+
+	if err := client.SetTopicDefaults("deployments", TopicDefaults{Priority: "urgent"}); err == nil {
+		t.Error("Expected error for invalid default priority, got nil")
+	}
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Defaults for dialing the service
+const (
+	DefaultDialTimeout = 10 * time.Second
+	// DefaultDialFallbackDelay is how long a dual-stack dial waits on the
+	// first address family before racing the other one (RFC 6555)
+	DefaultDialFallbackDelay = 300 * time.Millisecond
+)
+
+// newTransport returns an HTTP transport that dials dual-stack endpoints
+// with happy-eyeballs fallback
+func newTransport(config *Config) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:       config.DialTimeout,
+		KeepAlive:     30 * time.Second,
+		FallbackDelay: config.DialFallbackDelay,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// wrapTransport layers recording, auditing, and signing over base, as
+// configured
+func wrapTransport(config *Config, base http.RoundTripper) http.RoundTripper {
+	transport := base
+	if config.Recorder != nil {
+		transport = config.Recorder.wrap(transport)
+	}
+	// Audited inside signing, so records show the request as sent
+	if config.Audit != nil && config.Audit.Sink != nil {
+		transport = &auditTransport{next: transport, config: config.Audit, now: config.Clock.Now, logger: config.Logger}
+	}
+	if config.Signing != nil {
+		transport = &signingTransport{next: transport, config: config.Signing, now: config.Clock.Now}
+	}
+	return transport
+}
@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIPv6LiteralBaseURL(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(&Config{
+		BaseURL:           server.URL,
+		Timeout:           5 * time.Second,
+		DialFallbackDelay: 50 * time.Millisecond,
+	})
+
+	if _, err := client.CheckHealth(context.Background()); err != nil {
+		t.Errorf("Expected no error against %s, got %v", server.URL, err)
+	}
+}
+
+func TestNewTransportDialSettings(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://[::1]:8083"})
+	if client.baseURL != "http://[::1]:8083" {
+		t.Errorf("Expected IPv6 base URL to be preserved, got '%s'", client.baseURL)
+	}
+	if _, ok := client.httpClient.Transport.(*http.Transport); !ok {
+		t.Error("Expected client to use an *http.Transport")
+	}
+}
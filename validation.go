@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxPayloadSize is the largest marshaled ObjectBody accepted by the service, in bytes
+const MaxPayloadSize = 1 << 20
+
+// MaxGroupIDLength is the longest GroupID accepted by the service, in bytes
+const MaxGroupIDLength = 128
+
+// ErrPayloadTooLarge reports an ObjectBody larger than MaxPayloadSize. It
+// matches, through errors.Is, both client-side validation failures and 413
+// responses from the service
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// FieldViolation describes a single invalid field of a request
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a request fails client-side validation
+// and lists every violation found
+type ValidationError struct {
+	Violations []FieldViolation
+
+	// causes holds sentinel errors matched by errors.Is
+	causes []error
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(parts, "; "))
+}
+
+// add records a violation for the given field
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Violations = append(e.Violations, FieldViolation{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Unwrap returns the sentinel errors behind the violations, such as ErrPayloadTooLarge
+func (e *ValidationError) Unwrap() []error {
+	return e.causes
+}
+
+// errOrNil returns e when it holds violations and nil otherwise
+func (e *ValidationError) errOrNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// IsValidationError checks if an error is a validation error
+func IsValidationError(err error) bool {
+	_, ok := err.(*ValidationError)
+	return ok
+}
+
+// IsValid reports whether p is one of the known priority levels
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the message request for missing or malformed fields
+func (r *MessageRequest) Validate() error {
+	verr := &ValidationError{}
+	r.validate("", verr)
+	return verr.errOrNil()
+}
+
+// validate appends the violations of r to verr, prefixing field names with prefix
+func (r *MessageRequest) validate(prefix string, verr *ValidationError) {
+	if strings.TrimSpace(r.ItemID) == "" {
+		verr.add(prefix+"item_id", "is required")
+	}
+
+	if r.Priority == "" {
+		verr.add(prefix+"priority", "is required")
+	} else if !r.Priority.IsValid() {
+		verr.add(prefix+"priority", "must be 'low', 'medium', or 'high', got '%s'", r.Priority)
+	}
+
+	if r.Topic == "" {
+		verr.add(prefix+"topic", "is required")
+	} else if isPayloadReference(r.ObjectBody) || isEncryptedPayload(r.ObjectBody) {
+		// The body was validated before it was offloaded or encrypted
+	} else if validator := topicValidator(r.Topic); validator != nil {
+		if err := validator.ValidateBody(r.ObjectBody); err != nil {
+			verr.add(prefix+"object_body", "%v", err)
+		}
+	}
+
+	if r.CallbackURL == "" {
+		verr.add(prefix+"callback_url", "is required")
+	} else if r.callbackErr != nil {
+		verr.add(prefix+"callback_url", "%v", r.callbackErr)
+	} else if err := validateCallbackURL(r.CallbackURL); err != nil {
+		verr.add(prefix+"callback_url", "%v", err)
+	}
+
+	if r.ProcessingTimeout < 0 {
+		verr.add(prefix+"processing_timeout", "cannot be negative")
+	} else if r.ProcessingTimeout > 0 && r.ProcessingTimeout < time.Millisecond {
+		verr.add(prefix+"processing_timeout", "must be at least 1ms")
+	}
+
+	if r.Budget < 0 {
+		verr.add(prefix+"budget", "cannot be negative")
+	}
+
+	if r.TTL < 0 {
+		verr.add(prefix+"ttl", "cannot be negative")
+	} else if r.TTL > 0 && r.TTL < time.Millisecond {
+		verr.add(prefix+"ttl", "must be at least 1ms")
+	}
+
+	if r.RetryPolicy != nil {
+		r.RetryPolicy.validate(prefix+"retry_policy.", verr)
+	}
+
+	if r.GroupID != "" {
+		if err := validateGroupID(r.GroupID); err != nil {
+			verr.add(prefix+"group_id", "%v", err)
+		}
+	}
+
+	if r.ObjectBody != nil {
+		data, err := json.Marshal(r.ObjectBody)
+		if err != nil {
+			verr.add(prefix+"object_body", "cannot be marshaled: %v", err)
+		} else if len(data) > MaxPayloadSize {
+			verr.add(prefix+"object_body", "is %d bytes, exceeds maximum of %d", len(data), MaxPayloadSize)
+			verr.causes = append(verr.causes, ErrPayloadTooLarge)
+		}
+	}
+}
+
+// Validate checks every message in the bulk request
+func (r *BulkMessageRequest) Validate() error {
+	verr := &ValidationError{}
+	if len(r.Messages) == 0 {
+		verr.add("messages", "at least one message is required")
+	}
+
+	for i := range r.Messages {
+		r.Messages[i].validate(fmt.Sprintf("messages[%d].", i), verr)
+	}
+
+	return verr.errOrNil()
+}
+
+// validateGroupID checks that id is a usable ordering key
+func validateGroupID(id string) error {
+	if len(id) > MaxGroupIDLength {
+		return fmt.Errorf("is %d bytes, exceeds maximum of %d", len(id), MaxGroupIDLength)
+	}
+	if strings.TrimSpace(id) != id {
+		return fmt.Errorf("cannot have leading or trailing whitespace")
+	}
+	for _, r := range id {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("cannot contain control characters")
+		}
+	}
+	return nil
+}
+
+// validateCallbackURL checks that raw is an absolute http(s) URL
+func validateCallbackURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("must use http or https scheme")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("must include a host")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMessageRequestValidate(t *testing.T) {
+	req := &MessageRequest{
+		ItemID:      "pr-123",
+		Priority:    PriorityHigh,
+		Topic:       TopicPullRequests,
+		CallbackURL: "https://example.com/callback",
+		ObjectBody:  map[string]interface{}{"test": true},
+	}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Expected valid request, got %v", err)
+	}
+
+	invalid := &MessageRequest{
+		Priority:    "urgent",
+		Topic:       TopicPullRequests,
+		CallbackURL: "ftp://example.com/callback",
+		ObjectBody:  strings.Repeat("a", MaxPayloadSize+1),
+	}
+	err := invalid.Validate()
+	if !IsValidationError(err) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	verr := err.(*ValidationError)
+	if len(verr.Violations) != 4 {
+		t.Errorf("Expected 4 violations, got %d: %v", len(verr.Violations), verr)
+	}
+}
+
+func TestBulkMessageRequestValidate(t *testing.T) {
+	req := &BulkMessageRequest{
+		Messages: []MessageRequest{
+			{ItemID: "pr-1", Priority: PriorityLow, Topic: TopicPullRequests, CallbackURL: "https://example.com/cb"},
+			{ItemID: "pr-2", Priority: PriorityLow, Topic: TopicPullRequests, CallbackURL: "/relative"},
+		},
+	}
+
+	err := req.Validate()
+	if !IsValidationError(err) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	verr := err.(*ValidationError)
+	if len(verr.Violations) != 1 || verr.Violations[0].Field != "messages[1].callback_url" {
+		t.Errorf("Expected a single callback_url violation on messages[1], got %v", verr)
+	}
+}
+
+func TestPostMessageValidatesBeforeSending(t *testing.T) {
+	client := NewClient(&Config{BaseURL: "http://127.0.0.1:0"})
+
+	_, err := client.PostMessage(context.Background(), &MessageRequest{ItemID: "pr-1"})
+	if !IsValidationError(err) {
+		t.Errorf("Expected ValidationError without a network call, got %v", err)
+	}
+}
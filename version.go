@@ -0,0 +1,31 @@
+package sdk
+
+import "strings"
+
+// Version is the SDK release, reported to the service in the User-Agent header
+const Version = "0.1.0"
+
+// DefaultUserAgent identifies SDK traffic in service logs
+const DefaultUserAgent = "messages-worker-sdk/" + Version
+
+// WithUserAgentSuffix appends suffix, e.g. "billing-service/2.3.1", to the
+// User-Agent sent by clients created from c and returns c for chaining
+func (c *Config) WithUserAgentSuffix(suffix string) *Config {
+	suffix = strings.TrimSpace(suffix)
+	if suffix == "" {
+		return c
+	}
+	if c.UserAgentSuffix != "" {
+		suffix = c.UserAgentSuffix + " " + suffix
+	}
+	c.UserAgentSuffix = suffix
+	return c
+}
+
+// userAgent builds the User-Agent header from the default and a suffix
+func userAgent(suffix string) string {
+	if suffix == "" {
+		return DefaultUserAgent
+	}
+	return DefaultUserAgent + " " + suffix
+}
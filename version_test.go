@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client := NewClient(&Config{BaseURL: server.URL})
+	if _, err := client.CheckHealth(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != "messages-worker-sdk/"+Version {
+		t.Errorf("Expected default User-Agent, got '%s'", got)
+	}
+
+	config := &Config{BaseURL: server.URL}
+	client = NewClient(config.WithUserAgentSuffix("billing-service/2.3.1").WithUserAgentSuffix(" batch "))
+	if _, err := client.CheckHealth(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := DefaultUserAgent + " billing-service/2.3.1 batch"; got != want {
+		t.Errorf("Expected User-Agent '%s', got '%s'", want, got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 // WorkerInfo represents information about a single worker
@@ -12,6 +14,23 @@ type WorkerInfo struct {
 	QueueName string `json:"queue_name"`
 	Status    string `json:"status"`
 	StartedAt string `json:"started_at"`
+	Tenant    string `json:"tenant,omitempty"`
+}
+
+// WorkerDetail describes a single worker and its processing history
+type WorkerDetail struct {
+	WorkerInfo
+	Priority       string `json:"priority"`
+	Processed      int64  `json:"processed"`
+	Failed         int64  `json:"failed"`
+	CurrentMessage string `json:"current_message,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	UptimeMs       int64  `json:"uptime_ms"`
+}
+
+// Uptime returns how long the worker has been running
+func (d *WorkerDetail) Uptime() time.Duration {
+	return time.Duration(d.UptimeMs) * time.Millisecond
 }
 
 // PriorityWorkerInfo represents worker information for a specific priority
@@ -23,11 +42,95 @@ type PriorityWorkerInfo struct {
 
 // WorkerStatusResponse represents the response from the worker status endpoint
 type WorkerStatusResponse struct {
-	TotalWorkers    int                    `json:"total_workers"`
-	LowPriority     PriorityWorkerInfo    `json:"low_priority"`
-	MediumPriority  PriorityWorkerInfo    `json:"medium_priority"`
-	HighPriority    PriorityWorkerInfo    `json:"high_priority"`
-	AllWorkers      []WorkerInfo          `json:"all_workers"`
+	TotalWorkers   int                `json:"total_workers"`
+	LowPriority    PriorityWorkerInfo `json:"low_priority"`
+	MediumPriority PriorityWorkerInfo `json:"medium_priority"`
+	HighPriority   PriorityWorkerInfo `json:"high_priority"`
+	AllWorkers     []WorkerInfo       `json:"all_workers"`
+	Tenant         string             `json:"tenant,omitempty"`
+}
+
+// priorities returns the per-priority sections keyed by priority name
+func (r *WorkerStatusResponse) priorities() map[string]*PriorityWorkerInfo {
+	return map[string]*PriorityWorkerInfo{
+		"low":    &r.LowPriority,
+		"medium": &r.MediumPriority,
+		"high":   &r.HighPriority,
+	}
+}
+
+// Inconsistencies describes every way the response disagrees with itself:
+// totals that do not match the per-priority counts, counts that do not match
+// the listed workers, and workers missing from AllWorkers
+func (r *WorkerStatusResponse) Inconsistencies() []string {
+	var problems []string
+
+	sum := r.LowPriority.Count + r.MediumPriority.Count + r.HighPriority.Count
+	if r.TotalWorkers != sum {
+		problems = append(problems, fmt.Sprintf("total_workers is %d but priority counts sum to %d", r.TotalWorkers, sum))
+	}
+
+	all := make(map[string]bool, len(r.AllWorkers))
+	for _, w := range r.AllWorkers {
+		all[w.ID] = true
+	}
+
+	for _, name := range []string{"low", "medium", "high"} {
+		info := r.priorities()[name]
+		if len(info.Workers) > 0 && info.Count != len(info.Workers) {
+			problems = append(problems, fmt.Sprintf("%s_priority count is %d but %d workers are listed", name, info.Count, len(info.Workers)))
+		}
+		for _, w := range info.Workers {
+			if !all[w.ID] {
+				problems = append(problems, fmt.Sprintf("worker %s is listed under %s_priority but missing from all_workers", w.ID, name))
+			}
+		}
+	}
+
+	if len(r.AllWorkers) > 0 && len(r.AllWorkers) != r.TotalWorkers {
+		problems = append(problems, fmt.Sprintf("all_workers lists %d workers but total_workers is %d", len(r.AllWorkers), r.TotalWorkers))
+	}
+
+	return problems
+}
+
+// Consistent reports whether the totals, per-priority counts, and worker lists agree
+func (r *WorkerStatusResponse) Consistent() bool {
+	return len(r.Inconsistencies()) == 0
+}
+
+// Reconcile returns a copy of the response with derived fields recomputed:
+// per-priority counts follow their listed workers when present, AllWorkers
+// includes every listed worker exactly once, and TotalWorkers is the sum of
+// the per-priority counts
+func (r *WorkerStatusResponse) Reconcile() *WorkerStatusResponse {
+	fixed := *r
+	fixed.AllWorkers = nil
+
+	seen := make(map[string]bool)
+	addWorker := func(w WorkerInfo) {
+		if !seen[w.ID] {
+			seen[w.ID] = true
+			fixed.AllWorkers = append(fixed.AllWorkers, w)
+		}
+	}
+
+	for _, w := range r.AllWorkers {
+		addWorker(w)
+	}
+
+	for _, name := range []string{"low", "medium", "high"} {
+		info := fixed.priorities()[name]
+		if len(info.Workers) > 0 {
+			info.Count = len(info.Workers)
+		}
+		for _, w := range info.Workers {
+			addWorker(w)
+		}
+	}
+
+	fixed.TotalWorkers = fixed.LowPriority.Count + fixed.MediumPriority.Count + fixed.HighPriority.Count
+	return &fixed
 }
 
 // ScaleWorkersRequest represents a request to scale workers
@@ -53,7 +156,31 @@ type RemoveAllWorkersResponse struct {
 	Errors        []string `json:"errors,omitempty"`
 }
 
-// GetWorkerStatus returns the current status of all workers
+// PauseWorkersResponse represents the response from pausing or resuming workers
+type PauseWorkersResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Priority string `json:"priority,omitempty"`
+	WorkerID string `json:"worker_id,omitempty"`
+	Affected int    `json:"affected"`
+	Paused   bool   `json:"paused"`
+}
+
+// validateWorkerPriority checks that priority names a worker tier
+func validateWorkerPriority(priority string) error {
+	if priority == "" {
+		return fmt.Errorf("priority is required")
+	}
+
+	if priority != "low" && priority != "medium" && priority != "high" {
+		return fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	}
+
+	return nil
+}
+
+// GetWorkerStatus returns the current status of all workers, bypassing and
+// refreshing the cache of GetWorkerStatusCached
 func (c *Client) GetWorkerStatus(ctx context.Context) (*WorkerStatusResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/workers/status", nil)
 	if err != nil {
@@ -65,17 +192,15 @@ func (c *Client) GetWorkerStatus(ctx context.Context) (*WorkerStatusResponse, er
 		return nil, err
 	}
 
+	cached := statusResp
+	c.statusCache.store(&cached)
 	return &statusResp, nil
 }
 
 // ScaleWorkers scales workers for a specific priority queue
 func (c *Client) ScaleWorkers(ctx context.Context, priority string, count int) (*ScaleWorkersResponse, error) {
-	if priority == "" {
-		return nil, fmt.Errorf("priority is required")
-	}
-
-	if priority != "low" && priority != "medium" && priority != "high" {
-		return nil, fmt.Errorf("priority must be 'low', 'medium', or 'high'")
+	if err := validateWorkerPriority(priority); err != nil {
+		return nil, err
 	}
 
 	if count == 0 {
@@ -114,21 +239,33 @@ func (c *Client) RemoveWorkers(ctx context.Context, priority string, count int)
 	return c.ScaleWorkers(ctx, priority, -count)
 }
 
-// RemoveAllWorkers removes all running workers across all priority queues
+// RemoveAllWorkers removes all running workers across all priority queues.
+// If the service processes the removal asynchronously, it waits for the
+// operation to finish
 func (c *Client) RemoveAllWorkers(ctx context.Context) (*RemoveAllWorkersResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/workers/remove-all", nil)
+	op, err := c.StartRemoveAllWorkers(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, err := op.Wait(ctx, PollOptions{}); err != nil {
+		return nil, err
+	}
+
 	var removeResp RemoveAllWorkersResponse
-	if err := c.parseResponse(resp, &removeResp); err != nil {
+	if err := op.Result(&removeResp); err != nil {
 		return nil, err
 	}
 
 	return &removeResp, nil
 }
 
+// StartRemoveAllWorkers requests removal of all workers and returns a handle
+// to the operation without waiting for it to finish
+func (c *Client) StartRemoveAllWorkers(ctx context.Context) (*Operation, error) {
+	return c.startOperation(ctx, http.MethodPost, "/api/v1/workers/remove-all", "remove_all_workers", nil)
+}
+
 // GetWorkerCount returns the number of workers for a specific priority
 func (c *Client) GetWorkerCount(ctx context.Context, priority string) (int, error) {
 	status, err := c.GetWorkerStatus(ctx)
@@ -158,3 +295,103 @@ func (c *Client) GetTotalWorkerCount(ctx context.Context) (int, error) {
 	return status.TotalWorkers, nil
 }
 
+// GetWorker returns the details of a single worker
+func (c *Client) GetWorker(ctx context.Context, id string) (*WorkerDetail, error) {
+	if id == "" {
+		return nil, fmt.Errorf("worker id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/workers/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail WorkerDetail
+	if err := c.parseResponse(resp, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// RestartWorker replaces a single worker with a fresh one on the same
+// priority and returns the new worker's details
+func (c *Client) RestartWorker(ctx context.Context, id string) (*WorkerDetail, error) {
+	if id == "" {
+		return nil, fmt.Errorf("worker id is required")
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodPost, "/api/v1/workers/"+url.PathEscape(id)+"/restart", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail WorkerDetail
+	if err := c.parseResponse(resp, &detail); err != nil {
+		return nil, err
+	}
+
+	return &detail, nil
+}
+
+// RemoveWorker removes a single worker; removing a worker that no longer
+// exists is not an error
+func (c *Client) RemoveWorker(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("worker id is required")
+	}
+
+	return c.removeWorker(ctx, id)
+}
+
+// PauseWorkers stops the workers of a priority from consuming messages
+// without removing them, so they keep their warm state
+func (c *Client) PauseWorkers(ctx context.Context, priority string) (*PauseWorkersResponse, error) {
+	if err := validateWorkerPriority(priority); err != nil {
+		return nil, err
+	}
+
+	return c.postPause(ctx, "/api/v1/workers/pause/"+priority)
+}
+
+// ResumeWorkers lets paused workers of a priority consume messages again
+func (c *Client) ResumeWorkers(ctx context.Context, priority string) (*PauseWorkersResponse, error) {
+	if err := validateWorkerPriority(priority); err != nil {
+		return nil, err
+	}
+
+	return c.postPause(ctx, "/api/v1/workers/resume/"+priority)
+}
+
+// PauseWorker stops a single worker from consuming messages
+func (c *Client) PauseWorker(ctx context.Context, id string) (*PauseWorkersResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("worker id is required")
+	}
+
+	return c.postPause(ctx, "/api/v1/workers/"+url.PathEscape(id)+"/pause")
+}
+
+// ResumeWorker lets a single paused worker consume messages again
+func (c *Client) ResumeWorker(ctx context.Context, id string) (*PauseWorkersResponse, error) {
+	if id == "" {
+		return nil, fmt.Errorf("worker id is required")
+	}
+
+	return c.postPause(ctx, "/api/v1/workers/"+url.PathEscape(id)+"/resume")
+}
+
+// postPause sends a pause or resume request to path
+func (c *Client) postPause(ctx context.Context, path string) (*PauseWorkersResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pauseResp PauseWorkersResponse
+	if err := c.parseResponse(resp, &pauseResp); err != nil {
+		return nil, err
+	}
+
+	return &pauseResp, nil
+}